@@ -3,6 +3,8 @@ package main
 import (
 	"flag"
 	"log"
+	// Embedded timezone database, so user timezones can be resolved in minimal images.
+	_ "time/tzdata"
 
 	_ "github.com/go-sql-driver/mysql"
 
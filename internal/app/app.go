@@ -66,6 +66,7 @@ func Run(cfg *config.Config) {
 	}
 
 	withClaims := auth.WithClaims(l, clerkClient)
+	withOptionalClaims := auth.WithOptionalClaims(l, clerkClient)
 	userService := auth.NewUserService(l, clerkClient)
 
 	// Repos
@@ -101,10 +102,24 @@ func Run(cfg *config.Config) {
 	chatUseCase := usecase.NewChatUseCase(gptService, validate)
 	studySetUseCase := usecase.NewStudySetUseCase(mysqlDataStore, userService, validate)
 	definitionUseCase := usecase.NewDefinitionUseCase(l, mysqlDataStore, gptService, validate)
-	profileUseCase := usecase.NewProfileUseCase(mysqlDataStore, userService)
+	profileUseCase := usecase.NewProfileUseCase(mysqlDataStore, userService, validate)
 	userUseCase := usecase.NewUserUseCase(mysqlDataStore)
-	studySessionUseCase := usecase.NewStudySessionUseCase(mysqlDataStore)
+	studySessionUseCase := usecase.NewStudySessionUseCase(mysqlDataStore, validate)
 	taskUseCase := usecase.NewTaskUseCase(mysqlDataStore)
+	reviewUseCase := usecase.NewReviewUseCase(mysqlDataStore, validate)
+	quizUseCase := usecase.NewQuizUseCase(mysqlDataStore, validate)
+	examUseCase := usecase.NewExamUseCase(mysqlDataStore, validate)
+	clozeUseCase := usecase.NewClozeUseCase(mysqlDataStore, validate)
+	streakUseCase := usecase.NewStreakUseCase(mysqlDataStore, validate)
+	syncUseCase := usecase.NewSyncUseCase(mysqlDataStore, validate)
+	skillUseCase := usecase.NewSkillUseCase(mysqlDataStore)
+	placementUseCase := usecase.NewPlacementUseCase(mysqlDataStore, validate)
+	searchUseCase := usecase.NewSearchUseCase(mysqlDataStore, validate)
+	collaboratorUseCase := usecase.NewCollaboratorUseCase(mysqlDataStore, validate)
+	revisionUseCase := usecase.NewRevisionUseCase(mysqlDataStore)
+	tagUseCase := usecase.NewTagUseCase(mysqlDataStore, validate)
+	folderUseCase := usecase.NewFolderUseCase(mysqlDataStore, validate)
+	exportUseCase := usecase.NewExportUseCase(mysqlDataStore)
 
 	// Controllers
 	ai := controller.NewAiController(
@@ -122,6 +137,20 @@ func Run(cfg *config.Config) {
 
 	me := controller.NewMeController(l, profileUseCase, studySessionUseCase, userService)
 	task := controller.NewTaskController(l, userService, taskUseCase)
+	review := controller.NewReviewController(l, userService, reviewUseCase)
+	quiz := controller.NewQuizController(l, userService, quizUseCase)
+	exam := controller.NewExamController(l, userService, examUseCase)
+	cloze := controller.NewClozeController(l, userService, clozeUseCase)
+	streak := controller.NewStreakController(l, userService, streakUseCase)
+	sync := controller.NewSyncController(l, userService, syncUseCase)
+	skill := controller.NewSkillController(l, userService, skillUseCase)
+	placement := controller.NewPlacementController(l, userService, placementUseCase)
+	search := controller.NewSearchController(l, userService, searchUseCase)
+	collaborator := controller.NewCollaboratorController(l, userService, collaboratorUseCase)
+	revision := controller.NewRevisionController(l, userService, revisionUseCase)
+	tag := controller.NewTagController(l, tagUseCase)
+	folder := controller.NewFolderController(l, userService, folderUseCase)
+	export := controller.NewExportController(l, userService, exportUseCase)
 
 	clerkWebhook, err := webhook.NewClerkWebhook(l, cfg, userUseCase)
 	if err != nil {
@@ -156,8 +185,27 @@ func Run(cfg *config.Config) {
 			10*time.Second,
 			httprate.WithKeyFuncs(httprate.KeyByIP, httprate.KeyByEndpoint),
 		))
-		r.Route("/study-sets", studySet.Router(withClaims))
-		r.With(withClaims).Route("/me", me.Router)
+		r.Route("/study-sets", func(r chi.Router) {
+			studySet.Router(withClaims, withOptionalClaims)(r)
+			r.With(withOptionalClaims).Group(cloze.Router)
+			r.With(withClaims).Group(collaborator.Router)
+			r.With(withClaims).Group(revision.Router)
+			r.With(withOptionalClaims).Group(export.Router)
+		})
+		r.With(withOptionalClaims).Route("/search", search.Router)
+		r.Route("/tags", tag.Router)
+		r.Route("/categories", tag.CategoryRouter)
+		r.With(withClaims).Route("/me", func(r chi.Router) {
+			me.Router(r)
+			review.Router(r)
+			quiz.Router(r)
+			exam.Router(r)
+			streak.Router(r)
+			sync.Router(r)
+			skill.Router(r)
+			placement.Router(r)
+			folder.Router(r)
+		})
 		r.With(withClaims).Route("/task", task.Router)
 	})
 
@@ -0,0 +1,111 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+
+	"ailingo/internal/domain"
+	"ailingo/internal/usecase"
+	"ailingo/pkg/apiutil"
+	"ailingo/pkg/auth"
+)
+
+type ClozeController struct {
+	l            *slog.Logger
+	userService  *auth.UserService
+	clozeUseCase domain.ClozeUseCase
+}
+
+func NewClozeController(l *slog.Logger, userService *auth.UserService, clozeUseCase domain.ClozeUseCase) *ClozeController {
+	return &ClozeController{
+		l:            l,
+		userService:  userService,
+		clozeUseCase: clozeUseCase,
+	}
+}
+
+// Router registers cloze endpoints. It is meant to be mounted under /study-sets with optional claims.
+func (c *ClozeController) Router(r chi.Router) {
+	r.Get("/{studySetID}/cloze", c.GetExercises)
+	r.Post("/{studySetID}/cloze/check", c.Check)
+}
+
+// GetExercises is an endpoint handler for getting fill-in-the-blank exercises generated from the study set.
+func (c *ClozeController) GetExercises(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	studySetID, err := strconv.ParseInt(chi.URLParam(r, "studySetID"), 10, 64)
+	if err != nil {
+		apiutil.Err(c.l, w, &apiutil.ApiError{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid study set ID",
+		})
+		return
+	}
+
+	exercises, err := c.clozeUseCase.GetExercises(ctx, c.userService.GetUserIDFromContext(ctx), studySetID)
+	if err != nil {
+		var errNotFound *usecase.ErrNotFound
+		if errors.As(err, &errNotFound) {
+			apiutil.Err(c.l, w, &apiutil.ApiError{
+				Status:  http.StatusNotFound,
+				Message: errNotFound.Error(),
+			})
+		} else {
+			apiutil.Err(c.l, w, err)
+		}
+		return
+	}
+
+	apiutil.Json(c.l, w, http.StatusOK, exercises)
+}
+
+// Check is an endpoint handler for grading answers to fill-in-the-blank exercises.
+func (c *ClozeController) Check(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	studySetID, err := strconv.ParseInt(chi.URLParam(r, "studySetID"), 10, 64)
+	if err != nil {
+		apiutil.Err(c.l, w, &apiutil.ApiError{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid study set ID",
+		})
+		return
+	}
+
+	var checkData domain.ClozeCheckData
+	if err := json.NewDecoder(r.Body).Decode(&checkData); err != nil {
+		apiutil.Err(c.l, w, &apiutil.ApiError{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid request body",
+			Cause:   err,
+		})
+		return
+	}
+
+	results, err := c.clozeUseCase.Check(ctx, c.userService.GetUserIDFromContext(ctx), studySetID, &checkData)
+	if err != nil {
+		var errNotFound *usecase.ErrNotFound
+		if errors.As(err, &errNotFound) {
+			apiutil.Err(c.l, w, &apiutil.ApiError{
+				Status:  http.StatusNotFound,
+				Message: errNotFound.Error(),
+			})
+		} else if errors.Is(err, usecase.ErrValidation) {
+			apiutil.Err(c.l, w, &apiutil.ApiError{
+				Status: http.StatusBadRequest,
+				Cause:  err,
+			})
+		} else {
+			apiutil.Err(c.l, w, err)
+		}
+		return
+	}
+
+	apiutil.Json(c.l, w, http.StatusOK, results)
+}
@@ -0,0 +1,262 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+
+	"ailingo/internal/domain"
+	"ailingo/internal/usecase"
+	"ailingo/pkg/apiutil"
+	"ailingo/pkg/auth"
+)
+
+type CollaboratorController struct {
+	l                   *slog.Logger
+	userService         *auth.UserService
+	collaboratorUseCase domain.CollaboratorUseCase
+}
+
+func NewCollaboratorController(l *slog.Logger, userService *auth.UserService, collaboratorUseCase domain.CollaboratorUseCase) *CollaboratorController {
+	return &CollaboratorController{
+		l:                   l,
+		userService:         userService,
+		collaboratorUseCase: collaboratorUseCase,
+	}
+}
+
+// Router registers study set collaborator endpoints. It is meant to be mounted under /study-sets.
+func (c *CollaboratorController) Router(r chi.Router) {
+	r.Get("/{studySetID}/collaborators", c.GetAll)
+	r.Post("/{studySetID}/collaborators", c.Add)
+	r.Put("/{studySetID}/collaborators/{userID}", c.Update)
+	r.Delete("/{studySetID}/collaborators/{userID}", c.Remove)
+}
+
+// GetAll is an endpoint handler for getting all the collaborators of the study set.
+func (c *CollaboratorController) GetAll(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, err := c.userService.GetUserFromContext(ctx)
+	if err != nil {
+		if errors.Is(err, auth.ErrNoClaims) {
+			apiutil.Err(c.l, w, &apiutil.ApiError{
+				Status: http.StatusUnauthorized,
+				Cause:  err,
+			})
+		} else {
+			apiutil.Err(c.l, w, err)
+		}
+		return
+	}
+
+	studySetID, err := strconv.ParseInt(chi.URLParam(r, "studySetID"), 10, 64)
+	if err != nil {
+		apiutil.Err(c.l, w, &apiutil.ApiError{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid study set ID",
+		})
+		return
+	}
+
+	collaborators, err := c.collaboratorUseCase.GetAll(ctx, user.ID, studySetID)
+	if err != nil {
+		var errNotFound *usecase.ErrNotFound
+		if errors.As(err, &errNotFound) {
+			apiutil.Err(c.l, w, &apiutil.ApiError{
+				Status:  http.StatusNotFound,
+				Message: errNotFound.Error(),
+			})
+		} else if errors.Is(err, usecase.ErrForbidden) {
+			apiutil.Err(c.l, w, &apiutil.ApiError{
+				Status: http.StatusForbidden,
+				Cause:  err,
+			})
+		} else {
+			apiutil.Err(c.l, w, err)
+		}
+		return
+	}
+
+	apiutil.Json(c.l, w, http.StatusOK, collaborators)
+}
+
+// Add is an endpoint handler for inviting a user to collaborate on the study set.
+func (c *CollaboratorController) Add(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, err := c.userService.GetUserFromContext(ctx)
+	if err != nil {
+		if errors.Is(err, auth.ErrNoClaims) {
+			apiutil.Err(c.l, w, &apiutil.ApiError{
+				Status: http.StatusUnauthorized,
+				Cause:  err,
+			})
+		} else {
+			apiutil.Err(c.l, w, err)
+		}
+		return
+	}
+
+	studySetID, err := strconv.ParseInt(chi.URLParam(r, "studySetID"), 10, 64)
+	if err != nil {
+		apiutil.Err(c.l, w, &apiutil.ApiError{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid study set ID",
+		})
+		return
+	}
+
+	var addData domain.AddCollaboratorData
+	if err := json.NewDecoder(r.Body).Decode(&addData); err != nil {
+		apiutil.Err(c.l, w, &apiutil.ApiError{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid request body",
+			Cause:   err,
+		})
+		return
+	}
+
+	if err := c.collaboratorUseCase.Add(ctx, user.ID, studySetID, &addData); err != nil {
+		var errNotFound *usecase.ErrNotFound
+		if errors.As(err, &errNotFound) {
+			apiutil.Err(c.l, w, &apiutil.ApiError{
+				Status:  http.StatusNotFound,
+				Message: errNotFound.Error(),
+			})
+		} else if errors.Is(err, usecase.ErrValidation) {
+			apiutil.Err(c.l, w, &apiutil.ApiError{
+				Status: http.StatusBadRequest,
+				Cause:  err,
+			})
+		} else if errors.Is(err, usecase.ErrForbidden) {
+			apiutil.Err(c.l, w, &apiutil.ApiError{
+				Status: http.StatusForbidden,
+				Cause:  err,
+			})
+		} else if errors.Is(err, usecase.ErrAlreadyCollaborator) {
+			apiutil.Err(c.l, w, &apiutil.ApiError{
+				Status:  http.StatusConflict,
+				Message: err.Error(),
+			})
+		} else {
+			apiutil.Err(c.l, w, err)
+		}
+		return
+	}
+
+	apiutil.Empty(w, http.StatusCreated)
+}
+
+// Update is an endpoint handler for changing the role of the collaborator.
+func (c *CollaboratorController) Update(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, err := c.userService.GetUserFromContext(ctx)
+	if err != nil {
+		if errors.Is(err, auth.ErrNoClaims) {
+			apiutil.Err(c.l, w, &apiutil.ApiError{
+				Status: http.StatusUnauthorized,
+				Cause:  err,
+			})
+		} else {
+			apiutil.Err(c.l, w, err)
+		}
+		return
+	}
+
+	studySetID, err := strconv.ParseInt(chi.URLParam(r, "studySetID"), 10, 64)
+	if err != nil {
+		apiutil.Err(c.l, w, &apiutil.ApiError{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid study set ID",
+		})
+		return
+	}
+
+	var updateData domain.UpdateCollaboratorData
+	if err := json.NewDecoder(r.Body).Decode(&updateData); err != nil {
+		apiutil.Err(c.l, w, &apiutil.ApiError{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid request body",
+			Cause:   err,
+		})
+		return
+	}
+
+	if err := c.collaboratorUseCase.Update(ctx, user.ID, studySetID, chi.URLParam(r, "userID"), &updateData); err != nil {
+		var errNotFound *usecase.ErrNotFound
+		if errors.As(err, &errNotFound) {
+			apiutil.Err(c.l, w, &apiutil.ApiError{
+				Status:  http.StatusNotFound,
+				Message: errNotFound.Error(),
+			})
+		} else if errors.Is(err, usecase.ErrValidation) {
+			apiutil.Err(c.l, w, &apiutil.ApiError{
+				Status: http.StatusBadRequest,
+				Cause:  err,
+			})
+		} else if errors.Is(err, usecase.ErrForbidden) {
+			apiutil.Err(c.l, w, &apiutil.ApiError{
+				Status: http.StatusForbidden,
+				Cause:  err,
+			})
+		} else {
+			apiutil.Err(c.l, w, err)
+		}
+		return
+	}
+
+	apiutil.Empty(w, http.StatusOK)
+}
+
+// Remove is an endpoint handler for removing the collaborator from the study set.
+func (c *CollaboratorController) Remove(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, err := c.userService.GetUserFromContext(ctx)
+	if err != nil {
+		if errors.Is(err, auth.ErrNoClaims) {
+			apiutil.Err(c.l, w, &apiutil.ApiError{
+				Status: http.StatusUnauthorized,
+				Cause:  err,
+			})
+		} else {
+			apiutil.Err(c.l, w, err)
+		}
+		return
+	}
+
+	studySetID, err := strconv.ParseInt(chi.URLParam(r, "studySetID"), 10, 64)
+	if err != nil {
+		apiutil.Err(c.l, w, &apiutil.ApiError{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid study set ID",
+		})
+		return
+	}
+
+	if err := c.collaboratorUseCase.Remove(ctx, user.ID, studySetID, chi.URLParam(r, "userID")); err != nil {
+		var errNotFound *usecase.ErrNotFound
+		if errors.As(err, &errNotFound) {
+			apiutil.Err(c.l, w, &apiutil.ApiError{
+				Status:  http.StatusNotFound,
+				Message: errNotFound.Error(),
+			})
+		} else if errors.Is(err, usecase.ErrForbidden) {
+			apiutil.Err(c.l, w, &apiutil.ApiError{
+				Status: http.StatusForbidden,
+				Cause:  err,
+			})
+		} else {
+			apiutil.Err(c.l, w, err)
+		}
+		return
+	}
+
+	apiutil.Empty(w, http.StatusOK)
+}
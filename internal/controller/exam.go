@@ -0,0 +1,309 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+
+	"ailingo/internal/domain"
+	"ailingo/internal/usecase"
+	"ailingo/pkg/apiutil"
+	"ailingo/pkg/auth"
+)
+
+type ExamController struct {
+	l           *slog.Logger
+	userService *auth.UserService
+	examUseCase domain.ExamUseCase
+}
+
+func NewExamController(l *slog.Logger, userService *auth.UserService, examUseCase domain.ExamUseCase) *ExamController {
+	return &ExamController{
+		l:           l,
+		userService: userService,
+		examUseCase: examUseCase,
+	}
+}
+
+// Router registers exam endpoints. It is meant to be mounted under /me.
+func (c *ExamController) Router(r chi.Router) {
+	r.Post("/study-sets/{studySetID}/exams", c.Create)
+	r.Get("/study-sets/{studySetID}/exams", c.GetAttempts)
+	r.Get("/exams/{examID}", c.Get)
+	r.Post("/exams/{examID}/submit", c.Submit)
+	r.Get("/exams/{examID}/report", c.GetReport)
+}
+
+// Create is an endpoint handler for generating a new timed exam from the study set.
+func (c *ExamController) Create(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, err := c.userService.GetUserFromContext(ctx)
+	if err != nil {
+		if errors.Is(err, auth.ErrNoClaims) {
+			apiutil.Err(c.l, w, &apiutil.ApiError{
+				Status: http.StatusUnauthorized,
+				Cause:  err,
+			})
+		} else {
+			apiutil.Err(c.l, w, err)
+		}
+		return
+	}
+
+	studySetID, err := strconv.ParseInt(chi.URLParam(r, "studySetID"), 10, 64)
+	if err != nil {
+		apiutil.Err(c.l, w, &apiutil.ApiError{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid study set ID",
+		})
+		return
+	}
+
+	var createData domain.CreateExamData
+	if err := json.NewDecoder(r.Body).Decode(&createData); err != nil {
+		apiutil.Err(c.l, w, &apiutil.ApiError{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid request body",
+			Cause:   err,
+		})
+		return
+	}
+
+	exam, err := c.examUseCase.Create(ctx, user.ID, studySetID, &createData)
+	if err != nil {
+		var errNotFound *usecase.ErrNotFound
+		if errors.As(err, &errNotFound) {
+			apiutil.Err(c.l, w, &apiutil.ApiError{
+				Status:  http.StatusNotFound,
+				Message: errNotFound.Error(),
+			})
+		} else if errors.Is(err, usecase.ErrValidation) {
+			apiutil.Err(c.l, w, &apiutil.ApiError{
+				Status: http.StatusBadRequest,
+				Cause:  err,
+			})
+		} else if errors.Is(err, usecase.ErrNotEnoughDefinitions) {
+			apiutil.Err(c.l, w, &apiutil.ApiError{
+				Status:  http.StatusUnprocessableEntity,
+				Message: "Study set does not have enough definitions",
+				Cause:   err,
+			})
+		} else {
+			apiutil.Err(c.l, w, err)
+		}
+		return
+	}
+
+	apiutil.Json(c.l, w, http.StatusCreated, exam)
+}
+
+// Get is an endpoint handler for getting the exam questions without the correct answers.
+func (c *ExamController) Get(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, err := c.userService.GetUserFromContext(ctx)
+	if err != nil {
+		if errors.Is(err, auth.ErrNoClaims) {
+			apiutil.Err(c.l, w, &apiutil.ApiError{
+				Status: http.StatusUnauthorized,
+				Cause:  err,
+			})
+		} else {
+			apiutil.Err(c.l, w, err)
+		}
+		return
+	}
+
+	examID, err := strconv.ParseInt(chi.URLParam(r, "examID"), 10, 64)
+	if err != nil {
+		apiutil.Err(c.l, w, &apiutil.ApiError{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid exam ID",
+		})
+		return
+	}
+
+	exam, err := c.examUseCase.Get(ctx, user.ID, examID)
+	if err != nil {
+		var errNotFound *usecase.ErrNotFound
+		if errors.As(err, &errNotFound) {
+			apiutil.Err(c.l, w, &apiutil.ApiError{
+				Status:  http.StatusNotFound,
+				Message: errNotFound.Error(),
+			})
+		} else if errors.Is(err, usecase.ErrForbidden) {
+			apiutil.Err(c.l, w, &apiutil.ApiError{
+				Status: http.StatusForbidden,
+				Cause:  err,
+			})
+		} else {
+			apiutil.Err(c.l, w, err)
+		}
+		return
+	}
+
+	apiutil.Json(c.l, w, http.StatusOK, exam)
+}
+
+// Submit is an endpoint handler for submitting exam answers. It responds with the exam report.
+func (c *ExamController) Submit(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, err := c.userService.GetUserFromContext(ctx)
+	if err != nil {
+		if errors.Is(err, auth.ErrNoClaims) {
+			apiutil.Err(c.l, w, &apiutil.ApiError{
+				Status: http.StatusUnauthorized,
+				Cause:  err,
+			})
+		} else {
+			apiutil.Err(c.l, w, err)
+		}
+		return
+	}
+
+	examID, err := strconv.ParseInt(chi.URLParam(r, "examID"), 10, 64)
+	if err != nil {
+		apiutil.Err(c.l, w, &apiutil.ApiError{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid exam ID",
+		})
+		return
+	}
+
+	var submitData domain.SubmitExamData
+	if err := json.NewDecoder(r.Body).Decode(&submitData); err != nil {
+		apiutil.Err(c.l, w, &apiutil.ApiError{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid request body",
+			Cause:   err,
+		})
+		return
+	}
+
+	report, err := c.examUseCase.Submit(ctx, user.ID, examID, &submitData)
+	if err != nil {
+		var errNotFound *usecase.ErrNotFound
+		if errors.As(err, &errNotFound) {
+			apiutil.Err(c.l, w, &apiutil.ApiError{
+				Status:  http.StatusNotFound,
+				Message: errNotFound.Error(),
+			})
+		} else if errors.Is(err, usecase.ErrValidation) {
+			apiutil.Err(c.l, w, &apiutil.ApiError{
+				Status: http.StatusBadRequest,
+				Cause:  err,
+			})
+		} else if errors.Is(err, usecase.ErrForbidden) {
+			apiutil.Err(c.l, w, &apiutil.ApiError{
+				Status: http.StatusForbidden,
+				Cause:  err,
+			})
+		} else if errors.Is(err, usecase.ErrExamAlreadySubmitted) {
+			apiutil.Err(c.l, w, &apiutil.ApiError{
+				Status:  http.StatusConflict,
+				Message: "Exam has already been submitted",
+				Cause:   err,
+			})
+		} else {
+			apiutil.Err(c.l, w, err)
+		}
+		return
+	}
+
+	apiutil.Json(c.l, w, http.StatusOK, report)
+}
+
+// GetAttempts is an endpoint handler for getting all exam attempts of the user for the study set.
+func (c *ExamController) GetAttempts(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, err := c.userService.GetUserFromContext(ctx)
+	if err != nil {
+		if errors.Is(err, auth.ErrNoClaims) {
+			apiutil.Err(c.l, w, &apiutil.ApiError{
+				Status: http.StatusUnauthorized,
+				Cause:  err,
+			})
+		} else {
+			apiutil.Err(c.l, w, err)
+		}
+		return
+	}
+
+	studySetID, err := strconv.ParseInt(chi.URLParam(r, "studySetID"), 10, 64)
+	if err != nil {
+		apiutil.Err(c.l, w, &apiutil.ApiError{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid study set ID",
+		})
+		return
+	}
+
+	attempts, err := c.examUseCase.GetAttempts(ctx, user.ID, studySetID)
+	if err != nil {
+		apiutil.Err(c.l, w, err)
+		return
+	}
+
+	apiutil.Json(c.l, w, http.StatusOK, attempts)
+}
+
+// GetReport is an endpoint handler for getting the report of a submitted exam.
+func (c *ExamController) GetReport(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, err := c.userService.GetUserFromContext(ctx)
+	if err != nil {
+		if errors.Is(err, auth.ErrNoClaims) {
+			apiutil.Err(c.l, w, &apiutil.ApiError{
+				Status: http.StatusUnauthorized,
+				Cause:  err,
+			})
+		} else {
+			apiutil.Err(c.l, w, err)
+		}
+		return
+	}
+
+	examID, err := strconv.ParseInt(chi.URLParam(r, "examID"), 10, 64)
+	if err != nil {
+		apiutil.Err(c.l, w, &apiutil.ApiError{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid exam ID",
+		})
+		return
+	}
+
+	report, err := c.examUseCase.GetReport(ctx, user.ID, examID)
+	if err != nil {
+		var errNotFound *usecase.ErrNotFound
+		if errors.As(err, &errNotFound) {
+			apiutil.Err(c.l, w, &apiutil.ApiError{
+				Status:  http.StatusNotFound,
+				Message: errNotFound.Error(),
+			})
+		} else if errors.Is(err, usecase.ErrForbidden) {
+			apiutil.Err(c.l, w, &apiutil.ApiError{
+				Status: http.StatusForbidden,
+				Cause:  err,
+			})
+		} else if errors.Is(err, usecase.ErrExamNotSubmitted) {
+			apiutil.Err(c.l, w, &apiutil.ApiError{
+				Status:  http.StatusConflict,
+				Message: "Exam has not been submitted yet",
+				Cause:   err,
+			})
+		} else {
+			apiutil.Err(c.l, w, err)
+		}
+		return
+	}
+
+	apiutil.Json(c.l, w, http.StatusOK, report)
+}
@@ -0,0 +1,198 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"mime"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+
+	"ailingo/internal/domain"
+	"ailingo/internal/usecase"
+	"ailingo/pkg/apiutil"
+	"ailingo/pkg/auth"
+)
+
+// exportFormats maps export formats to their media types and file extensions.
+var exportFormats = map[string]struct {
+	mediaType string
+	extension string
+}{
+	domain.ExportFormatCSV:  {"text/csv", "csv"},
+	domain.ExportFormatTSV:  {"text/tab-separated-values", "tsv"},
+	domain.ExportFormatJSON: {"application/json", "json"},
+	domain.ExportFormatHTML: {"text/html", "html"},
+}
+
+type ExportController struct {
+	l             *slog.Logger
+	userService   *auth.UserService
+	exportUseCase domain.ExportUseCase
+}
+
+func NewExportController(l *slog.Logger, userService *auth.UserService, exportUseCase domain.ExportUseCase) *ExportController {
+	return &ExportController{
+		l:             l,
+		userService:   userService,
+		exportUseCase: exportUseCase,
+	}
+}
+
+// Router registers export endpoints. It is meant to be mounted under /study-sets with optional claims.
+func (c *ExportController) Router(r chi.Router) {
+	r.Get("/{studySetID}/export", c.Export)
+}
+
+// Export is an endpoint handler for downloading the study set with its definitions.
+// The format is taken from the format query parameter, or negotiated with the Accept header if it is missing.
+func (c *ExportController) Export(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	studySetID, err := strconv.ParseInt(chi.URLParam(r, "studySetID"), 10, 64)
+	if err != nil {
+		apiutil.Err(c.l, w, &apiutil.ApiError{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid study set ID",
+		})
+		return
+	}
+
+	format, err := exportFormat(r)
+	if err != nil {
+		apiutil.Err(c.l, w, err)
+		return
+	}
+
+	exportWriter := &exportWriter{
+		w:           w,
+		contentType: exportFormats[format].mediaType + "; charset=utf-8",
+		filename:    fmt.Sprintf("study-set-%d.%s", studySetID, exportFormats[format].extension),
+	}
+	if err := c.exportUseCase.Export(ctx, c.userService.GetUserIDFromContext(ctx), studySetID, format, exportWriter); err != nil {
+		// Once the export has started the status cannot be changed anymore, so the error is only logged.
+		if exportWriter.started {
+			c.l.Error(fmt.Sprintf("failed to export the study set: %s", err))
+			return
+		}
+
+		var errNotFound *usecase.ErrNotFound
+		if errors.As(err, &errNotFound) {
+			apiutil.Err(c.l, w, &apiutil.ApiError{
+				Status:  http.StatusNotFound,
+				Message: errNotFound.Error(),
+			})
+		} else if errors.Is(err, usecase.ErrValidation) {
+			apiutil.Err(c.l, w, &apiutil.ApiError{
+				Status: http.StatusBadRequest,
+				Cause:  err,
+			})
+		} else {
+			apiutil.Err(c.l, w, err)
+		}
+		return
+	}
+
+	// Nothing is written when an empty study set is exported in a format without a header,
+	// but the response should still be a download.
+	if !exportWriter.started {
+		exportWriter.start()
+	}
+}
+
+// exportFormatPreference is the order in which formats are matched against a media range.
+// JSON comes first, as it is the only format that holds the whole study set.
+var exportFormatPreference = []string{domain.ExportFormatJSON, domain.ExportFormatCSV, domain.ExportFormatTSV, domain.ExportFormatHTML}
+
+// exportFormat picks the format from the query parameter or the most preferred supported media range of the Accept header.
+// Media ranges are tried from the highest quality, and media types with zero quality are never picked.
+func exportFormat(r *http.Request) (string, error) {
+	if format := strings.ToUpper(r.URL.Query().Get("format")); format != "" {
+		if _, ok := exportFormats[format]; !ok {
+			return "", &apiutil.ApiError{
+				Status:  http.StatusBadRequest,
+				Message: "Invalid export format",
+			}
+		}
+		return format, nil
+	}
+
+	accept := r.Header.Get("Accept")
+	if accept == "" {
+		return domain.ExportFormatJSON, nil
+	}
+
+	type mediaRange struct {
+		mediaType string
+		quality   float64
+	}
+	mediaRanges := make([]mediaRange, 0)
+	excluded := make(map[string]bool)
+	for _, value := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(value))
+		if err != nil {
+			continue
+		}
+		quality := 1.0
+		if q, ok := params["q"]; ok {
+			if quality, err = strconv.ParseFloat(q, 64); err != nil || quality < 0 || quality > 1 {
+				continue
+			}
+		}
+		if quality == 0 {
+			excluded[mediaType] = true
+			continue
+		}
+		mediaRanges = append(mediaRanges, mediaRange{mediaType, quality})
+	}
+	// The sort is stable, so that ranges of equal quality are tried in the listed order.
+	sort.SliceStable(mediaRanges, func(i, j int) bool {
+		return mediaRanges[i].quality > mediaRanges[j].quality
+	})
+
+	for _, accepted := range mediaRanges {
+		for _, format := range exportFormatPreference {
+			mediaType := exportFormats[format].mediaType
+			if excluded[mediaType] {
+				continue
+			}
+			if accepted.mediaType == mediaType || accepted.mediaType == "*/*" ||
+				(strings.HasSuffix(accepted.mediaType, "/*") && strings.HasPrefix(mediaType, strings.TrimSuffix(accepted.mediaType, "*"))) {
+				return format, nil
+			}
+		}
+	}
+
+	return "", &apiutil.ApiError{
+		Status:  http.StatusNotAcceptable,
+		Message: "None of the accepted media types can be exported",
+	}
+}
+
+// exportWriter sends the download headers right before the first write, so that errors
+// which happen before anything is exported can still be reported with a proper status.
+type exportWriter struct {
+	w           http.ResponseWriter
+	contentType string
+	filename    string
+	started     bool
+}
+
+func (e *exportWriter) Write(p []byte) (int, error) {
+	if !e.started {
+		e.start()
+	}
+	return e.w.Write(p)
+}
+
+// start sends the download headers.
+func (e *exportWriter) start() {
+	e.started = true
+	e.w.Header().Set("Content-Type", e.contentType)
+	e.w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": e.filename}))
+	e.w.WriteHeader(http.StatusOK)
+}
@@ -0,0 +1,537 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+
+	"ailingo/internal/domain"
+	"ailingo/internal/usecase"
+	"ailingo/pkg/apiutil"
+	"ailingo/pkg/auth"
+)
+
+type FolderController struct {
+	l             *slog.Logger
+	userService   *auth.UserService
+	folderUseCase domain.FolderUseCase
+}
+
+func NewFolderController(l *slog.Logger, userService *auth.UserService, folderUseCase domain.FolderUseCase) *FolderController {
+	return &FolderController{
+		l:             l,
+		userService:   userService,
+		folderUseCase: folderUseCase,
+	}
+}
+
+// Router registers folder endpoints. It is meant to be mounted under /me.
+func (c *FolderController) Router(r chi.Router) {
+	r.Get("/folders", c.GetAll)
+	r.Post("/folders", c.Create)
+	r.Get("/folders/{folderID}", c.Get)
+	r.Put("/folders/{folderID}", c.Update)
+	r.Delete("/folders/{folderID}", c.Delete)
+	r.Post("/folders/{folderID}/study-sets", c.AddStudySet)
+	r.Put("/folders/{folderID}/study-sets/{studySetID}", c.MoveStudySet)
+	r.Delete("/folders/{folderID}/study-sets/{studySetID}", c.RemoveStudySet)
+	r.Post("/folders/{folderID}/study-sessions", c.Study)
+}
+
+// GetAll is an endpoint handler for getting the folder tree of the user.
+func (c *FolderController) GetAll(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, err := c.userService.GetUserFromContext(ctx)
+	if err != nil {
+		if errors.Is(err, auth.ErrNoClaims) {
+			apiutil.Err(c.l, w, &apiutil.ApiError{
+				Status: http.StatusUnauthorized,
+				Cause:  err,
+			})
+		} else {
+			apiutil.Err(c.l, w, err)
+		}
+		return
+	}
+
+	folders, err := c.folderUseCase.GetAll(ctx, user.ID)
+	if err != nil {
+		apiutil.Err(c.l, w, err)
+		return
+	}
+
+	apiutil.Json(c.l, w, http.StatusOK, folders)
+}
+
+// Create is an endpoint handler for creating a new folder.
+func (c *FolderController) Create(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, err := c.userService.GetUserFromContext(ctx)
+	if err != nil {
+		if errors.Is(err, auth.ErrNoClaims) {
+			apiutil.Err(c.l, w, &apiutil.ApiError{
+				Status: http.StatusUnauthorized,
+				Cause:  err,
+			})
+		} else {
+			apiutil.Err(c.l, w, err)
+		}
+		return
+	}
+
+	var insertData domain.InsertFolderData
+	if err := json.NewDecoder(r.Body).Decode(&insertData); err != nil {
+		apiutil.Err(c.l, w, &apiutil.ApiError{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid request body",
+			Cause:   err,
+		})
+		return
+	}
+
+	createdID, err := c.folderUseCase.Create(ctx, user.ID, &insertData)
+	if err != nil {
+		var errNotFound *usecase.ErrNotFound
+		if errors.As(err, &errNotFound) {
+			apiutil.Err(c.l, w, &apiutil.ApiError{
+				Status:  http.StatusNotFound,
+				Message: errNotFound.Error(),
+			})
+		} else if errors.Is(err, usecase.ErrValidation) {
+			apiutil.Err(c.l, w, &apiutil.ApiError{
+				Status: http.StatusBadRequest,
+				Cause:  err,
+			})
+		} else if errors.Is(err, usecase.ErrForbidden) {
+			apiutil.Err(c.l, w, &apiutil.ApiError{
+				Status: http.StatusForbidden,
+				Cause:  err,
+			})
+		} else {
+			apiutil.Err(c.l, w, err)
+		}
+		return
+	}
+
+	apiutil.Json(c.l, w, http.StatusCreated, map[string]int64{"createdId": createdID})
+}
+
+// Get is an endpoint handler for getting the folder with its subfolders and study sets.
+func (c *FolderController) Get(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, err := c.userService.GetUserFromContext(ctx)
+	if err != nil {
+		if errors.Is(err, auth.ErrNoClaims) {
+			apiutil.Err(c.l, w, &apiutil.ApiError{
+				Status: http.StatusUnauthorized,
+				Cause:  err,
+			})
+		} else {
+			apiutil.Err(c.l, w, err)
+		}
+		return
+	}
+
+	folderID, err := strconv.ParseInt(chi.URLParam(r, "folderID"), 10, 64)
+	if err != nil {
+		apiutil.Err(c.l, w, &apiutil.ApiError{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid folder ID",
+		})
+		return
+	}
+
+	folder, err := c.folderUseCase.Get(ctx, user.ID, folderID)
+	if err != nil {
+		var errNotFound *usecase.ErrNotFound
+		if errors.As(err, &errNotFound) {
+			apiutil.Err(c.l, w, &apiutil.ApiError{
+				Status:  http.StatusNotFound,
+				Message: errNotFound.Error(),
+			})
+		} else if errors.Is(err, usecase.ErrForbidden) {
+			apiutil.Err(c.l, w, &apiutil.ApiError{
+				Status: http.StatusForbidden,
+				Cause:  err,
+			})
+		} else {
+			apiutil.Err(c.l, w, err)
+		}
+		return
+	}
+
+	apiutil.Json(c.l, w, http.StatusOK, folder)
+}
+
+// Update is an endpoint handler for renaming, moving and reordering the folder.
+func (c *FolderController) Update(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, err := c.userService.GetUserFromContext(ctx)
+	if err != nil {
+		if errors.Is(err, auth.ErrNoClaims) {
+			apiutil.Err(c.l, w, &apiutil.ApiError{
+				Status: http.StatusUnauthorized,
+				Cause:  err,
+			})
+		} else {
+			apiutil.Err(c.l, w, err)
+		}
+		return
+	}
+
+	folderID, err := strconv.ParseInt(chi.URLParam(r, "folderID"), 10, 64)
+	if err != nil {
+		apiutil.Err(c.l, w, &apiutil.ApiError{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid folder ID",
+		})
+		return
+	}
+
+	var updateData domain.UpdateFolderData
+	if err := json.NewDecoder(r.Body).Decode(&updateData); err != nil {
+		apiutil.Err(c.l, w, &apiutil.ApiError{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid request body",
+			Cause:   err,
+		})
+		return
+	}
+
+	if err := c.folderUseCase.Update(ctx, user.ID, folderID, &updateData); err != nil {
+		var errNotFound *usecase.ErrNotFound
+		if errors.As(err, &errNotFound) {
+			apiutil.Err(c.l, w, &apiutil.ApiError{
+				Status:  http.StatusNotFound,
+				Message: errNotFound.Error(),
+			})
+		} else if errors.Is(err, usecase.ErrValidation) {
+			apiutil.Err(c.l, w, &apiutil.ApiError{
+				Status: http.StatusBadRequest,
+				Cause:  err,
+			})
+		} else if errors.Is(err, usecase.ErrForbidden) {
+			apiutil.Err(c.l, w, &apiutil.ApiError{
+				Status: http.StatusForbidden,
+				Cause:  err,
+			})
+		} else {
+			apiutil.Err(c.l, w, err)
+		}
+		return
+	}
+
+	apiutil.Empty(w, http.StatusOK)
+}
+
+// Delete is an endpoint handler for deleting the folder with its subfolders. Study sets in them are kept.
+func (c *FolderController) Delete(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, err := c.userService.GetUserFromContext(ctx)
+	if err != nil {
+		if errors.Is(err, auth.ErrNoClaims) {
+			apiutil.Err(c.l, w, &apiutil.ApiError{
+				Status: http.StatusUnauthorized,
+				Cause:  err,
+			})
+		} else {
+			apiutil.Err(c.l, w, err)
+		}
+		return
+	}
+
+	folderID, err := strconv.ParseInt(chi.URLParam(r, "folderID"), 10, 64)
+	if err != nil {
+		apiutil.Err(c.l, w, &apiutil.ApiError{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid folder ID",
+		})
+		return
+	}
+
+	if err := c.folderUseCase.Delete(ctx, user.ID, folderID); err != nil {
+		var errNotFound *usecase.ErrNotFound
+		if errors.As(err, &errNotFound) {
+			apiutil.Err(c.l, w, &apiutil.ApiError{
+				Status:  http.StatusNotFound,
+				Message: errNotFound.Error(),
+			})
+		} else if errors.Is(err, usecase.ErrForbidden) {
+			apiutil.Err(c.l, w, &apiutil.ApiError{
+				Status: http.StatusForbidden,
+				Cause:  err,
+			})
+		} else {
+			apiutil.Err(c.l, w, err)
+		}
+		return
+	}
+
+	apiutil.Empty(w, http.StatusOK)
+}
+
+// AddStudySet is an endpoint handler for adding a study set to the end of the folder.
+func (c *FolderController) AddStudySet(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, err := c.userService.GetUserFromContext(ctx)
+	if err != nil {
+		if errors.Is(err, auth.ErrNoClaims) {
+			apiutil.Err(c.l, w, &apiutil.ApiError{
+				Status: http.StatusUnauthorized,
+				Cause:  err,
+			})
+		} else {
+			apiutil.Err(c.l, w, err)
+		}
+		return
+	}
+
+	folderID, err := strconv.ParseInt(chi.URLParam(r, "folderID"), 10, 64)
+	if err != nil {
+		apiutil.Err(c.l, w, &apiutil.ApiError{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid folder ID",
+		})
+		return
+	}
+
+	var addData domain.AddFolderStudySetData
+	if err := json.NewDecoder(r.Body).Decode(&addData); err != nil {
+		apiutil.Err(c.l, w, &apiutil.ApiError{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid request body",
+			Cause:   err,
+		})
+		return
+	}
+
+	if err := c.folderUseCase.AddStudySet(ctx, user.ID, folderID, &addData); err != nil {
+		var errNotFound *usecase.ErrNotFound
+		if errors.As(err, &errNotFound) {
+			apiutil.Err(c.l, w, &apiutil.ApiError{
+				Status:  http.StatusNotFound,
+				Message: errNotFound.Error(),
+			})
+		} else if errors.Is(err, usecase.ErrValidation) {
+			apiutil.Err(c.l, w, &apiutil.ApiError{
+				Status: http.StatusBadRequest,
+				Cause:  err,
+			})
+		} else if errors.Is(err, usecase.ErrForbidden) {
+			apiutil.Err(c.l, w, &apiutil.ApiError{
+				Status: http.StatusForbidden,
+				Cause:  err,
+			})
+		} else if errors.Is(err, usecase.ErrAlreadyInFolder) {
+			apiutil.Err(c.l, w, &apiutil.ApiError{
+				Status:  http.StatusConflict,
+				Message: err.Error(),
+			})
+		} else {
+			apiutil.Err(c.l, w, err)
+		}
+		return
+	}
+
+	apiutil.Empty(w, http.StatusCreated)
+}
+
+// MoveStudySet is an endpoint handler for changing the position of a study set in the folder.
+func (c *FolderController) MoveStudySet(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, err := c.userService.GetUserFromContext(ctx)
+	if err != nil {
+		if errors.Is(err, auth.ErrNoClaims) {
+			apiutil.Err(c.l, w, &apiutil.ApiError{
+				Status: http.StatusUnauthorized,
+				Cause:  err,
+			})
+		} else {
+			apiutil.Err(c.l, w, err)
+		}
+		return
+	}
+
+	folderID, err := strconv.ParseInt(chi.URLParam(r, "folderID"), 10, 64)
+	if err != nil {
+		apiutil.Err(c.l, w, &apiutil.ApiError{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid folder ID",
+		})
+		return
+	}
+
+	studySetID, err := strconv.ParseInt(chi.URLParam(r, "studySetID"), 10, 64)
+	if err != nil {
+		apiutil.Err(c.l, w, &apiutil.ApiError{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid study set ID",
+		})
+		return
+	}
+
+	var moveData domain.MoveFolderStudySetData
+	if err := json.NewDecoder(r.Body).Decode(&moveData); err != nil {
+		apiutil.Err(c.l, w, &apiutil.ApiError{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid request body",
+			Cause:   err,
+		})
+		return
+	}
+
+	if err := c.folderUseCase.MoveStudySet(ctx, user.ID, folderID, studySetID, &moveData); err != nil {
+		var errNotFound *usecase.ErrNotFound
+		if errors.As(err, &errNotFound) {
+			apiutil.Err(c.l, w, &apiutil.ApiError{
+				Status:  http.StatusNotFound,
+				Message: errNotFound.Error(),
+			})
+		} else if errors.Is(err, usecase.ErrValidation) {
+			apiutil.Err(c.l, w, &apiutil.ApiError{
+				Status: http.StatusBadRequest,
+				Cause:  err,
+			})
+		} else if errors.Is(err, usecase.ErrForbidden) {
+			apiutil.Err(c.l, w, &apiutil.ApiError{
+				Status: http.StatusForbidden,
+				Cause:  err,
+			})
+		} else {
+			apiutil.Err(c.l, w, err)
+		}
+		return
+	}
+
+	apiutil.Empty(w, http.StatusOK)
+}
+
+// RemoveStudySet is an endpoint handler for removing a study set from the folder.
+func (c *FolderController) RemoveStudySet(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, err := c.userService.GetUserFromContext(ctx)
+	if err != nil {
+		if errors.Is(err, auth.ErrNoClaims) {
+			apiutil.Err(c.l, w, &apiutil.ApiError{
+				Status: http.StatusUnauthorized,
+				Cause:  err,
+			})
+		} else {
+			apiutil.Err(c.l, w, err)
+		}
+		return
+	}
+
+	folderID, err := strconv.ParseInt(chi.URLParam(r, "folderID"), 10, 64)
+	if err != nil {
+		apiutil.Err(c.l, w, &apiutil.ApiError{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid folder ID",
+		})
+		return
+	}
+
+	studySetID, err := strconv.ParseInt(chi.URLParam(r, "studySetID"), 10, 64)
+	if err != nil {
+		apiutil.Err(c.l, w, &apiutil.ApiError{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid study set ID",
+		})
+		return
+	}
+
+	if err := c.folderUseCase.RemoveStudySet(ctx, user.ID, folderID, studySetID); err != nil {
+		var errNotFound *usecase.ErrNotFound
+		if errors.As(err, &errNotFound) {
+			apiutil.Err(c.l, w, &apiutil.ApiError{
+				Status:  http.StatusNotFound,
+				Message: errNotFound.Error(),
+			})
+		} else if errors.Is(err, usecase.ErrForbidden) {
+			apiutil.Err(c.l, w, &apiutil.ApiError{
+				Status: http.StatusForbidden,
+				Cause:  err,
+			})
+		} else {
+			apiutil.Err(c.l, w, err)
+		}
+		return
+	}
+
+	apiutil.Empty(w, http.StatusOK)
+}
+
+// Study is an endpoint handler for starting a study session with definitions of all study sets in the folder and its subfolders.
+func (c *FolderController) Study(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, err := c.userService.GetUserFromContext(ctx)
+	if err != nil {
+		if errors.Is(err, auth.ErrNoClaims) {
+			apiutil.Err(c.l, w, &apiutil.ApiError{
+				Status: http.StatusUnauthorized,
+				Cause:  err,
+			})
+		} else {
+			apiutil.Err(c.l, w, err)
+		}
+		return
+	}
+
+	folderID, err := strconv.ParseInt(chi.URLParam(r, "folderID"), 10, 64)
+	if err != nil {
+		apiutil.Err(c.l, w, &apiutil.ApiError{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid folder ID",
+		})
+		return
+	}
+
+	var startData domain.StartFolderStudySessionData
+	if err := json.NewDecoder(r.Body).Decode(&startData); err != nil {
+		apiutil.Err(c.l, w, &apiutil.ApiError{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid request body",
+			Cause:   err,
+		})
+		return
+	}
+
+	session, err := c.folderUseCase.Study(ctx, user.ID, folderID, &startData)
+	if err != nil {
+		var errNotFound *usecase.ErrNotFound
+		if errors.As(err, &errNotFound) {
+			apiutil.Err(c.l, w, &apiutil.ApiError{
+				Status:  http.StatusNotFound,
+				Message: errNotFound.Error(),
+			})
+		} else if errors.Is(err, usecase.ErrValidation) {
+			apiutil.Err(c.l, w, &apiutil.ApiError{
+				Status: http.StatusBadRequest,
+				Cause:  err,
+			})
+		} else if errors.Is(err, usecase.ErrForbidden) {
+			apiutil.Err(c.l, w, &apiutil.ApiError{
+				Status: http.StatusForbidden,
+				Cause:  err,
+			})
+		} else {
+			apiutil.Err(c.l, w, err)
+		}
+		return
+	}
+
+	apiutil.Json(c.l, w, http.StatusCreated, session)
+}
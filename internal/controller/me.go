@@ -34,15 +34,19 @@ func NewMeController(l *slog.Logger, accountUseCase domain.ProfileUseCase, study
 func (c *MeController) Router(r chi.Router) {
 	r.Get("/study-sets/created", c.GetCreated)
 	r.Get("/study-sets/starred", c.GetStarred)
+	r.Get("/study-sets/shared", c.GetShared)
 	r.Post("/study-sets/starred", c.Star)
 	r.Delete("/study-sets/starred/{studySetID}", c.Instar)
 
-	r.Get("/study-sessions", c.GetRecentStudySessions)
+	r.Get("/study-sessions", c.GetStudySessions)
+	r.Post("/study-sessions", c.StartStudySession)
 	r.Get("/study-sessions/{studySetID}", c.GetStudySessionForStudySet)
 	r.Patch("/study-sessions/{studySetID}", c.RefreshStudySession)
+	r.Post("/study-sessions/{studySessionID}/finish", c.FinishStudySession)
+	r.Get("/study-sessions/{studySessionID}/summary", c.GetStudySessionSummary)
 }
 
-// GetCreated is an endpoint handler for getting all created study sets.
+// GetCreated is an endpoint handler for getting a page of created study sets.
 func (c *MeController) GetCreated(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
@@ -59,16 +63,29 @@ func (c *MeController) GetCreated(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	studySets, err := c.profileUseCase.GetCreatedStudySets(ctx, user.ID)
+	filter, limit, err := studySetListQuery(r)
 	if err != nil {
 		apiutil.Err(c.l, w, err)
 		return
 	}
 
-	apiutil.Json(c.l, w, http.StatusOK, studySets)
+	page, err := c.profileUseCase.GetCreatedStudySets(ctx, user.ID, filter, r.URL.Query().Get("cursor"), limit)
+	if err != nil {
+		if errors.Is(err, usecase.ErrValidation) {
+			apiutil.Err(c.l, w, &apiutil.ApiError{
+				Status: http.StatusBadRequest,
+				Cause:  err,
+			})
+		} else {
+			apiutil.Err(c.l, w, err)
+		}
+		return
+	}
+
+	apiutil.Json(c.l, w, http.StatusOK, page)
 }
 
-// GetStarred is an endpoint handler for getting starred study sets.
+// GetStarred is an endpoint handler for getting a page of starred study sets.
 func (c *MeController) GetStarred(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
@@ -85,13 +102,65 @@ func (c *MeController) GetStarred(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	studySets, err := c.profileUseCase.GetStarredStudySets(ctx, user.ID)
+	filter, limit, err := studySetListQuery(r)
+	if err != nil {
+		apiutil.Err(c.l, w, err)
+		return
+	}
+
+	page, err := c.profileUseCase.GetStarredStudySets(ctx, user.ID, filter, r.URL.Query().Get("cursor"), limit)
+	if err != nil {
+		if errors.Is(err, usecase.ErrValidation) {
+			apiutil.Err(c.l, w, &apiutil.ApiError{
+				Status: http.StatusBadRequest,
+				Cause:  err,
+			})
+		} else {
+			apiutil.Err(c.l, w, err)
+		}
+		return
+	}
+
+	apiutil.Json(c.l, w, http.StatusOK, page)
+}
+
+// GetShared is an endpoint handler for getting a page of study sets shared with the user.
+func (c *MeController) GetShared(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, err := c.userService.GetUserFromContext(ctx)
+	if err != nil {
+		if errors.Is(err, auth.ErrNoClaims) {
+			apiutil.Err(c.l, w, &apiutil.ApiError{
+				Status: http.StatusUnauthorized,
+				Cause:  err,
+			})
+		} else {
+			apiutil.Err(c.l, w, err)
+		}
+		return
+	}
+
+	filter, limit, err := studySetListQuery(r)
 	if err != nil {
 		apiutil.Err(c.l, w, err)
 		return
 	}
 
-	apiutil.Json(c.l, w, http.StatusOK, studySets)
+	page, err := c.profileUseCase.GetSharedStudySets(ctx, user.ID, filter, r.URL.Query().Get("cursor"), limit)
+	if err != nil {
+		if errors.Is(err, usecase.ErrValidation) {
+			apiutil.Err(c.l, w, &apiutil.ApiError{
+				Status: http.StatusBadRequest,
+				Cause:  err,
+			})
+		} else {
+			apiutil.Err(c.l, w, err)
+		}
+		return
+	}
+
+	apiutil.Json(c.l, w, http.StatusOK, page)
 }
 
 type starPayload struct {
@@ -177,6 +246,63 @@ func (c *MeController) Instar(w http.ResponseWriter, r *http.Request) {
 	apiutil.Empty(w, http.StatusOK)
 }
 
+// GetStudySessions is an endpoint handler for getting paginated study session history of the authenticated user.
+// The ?view=recent query parameter returns recently studied study sets instead.
+func (c *MeController) GetStudySessions(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Query().Get("view") == "recent" {
+		c.GetRecentStudySessions(w, r)
+		return
+	}
+
+	ctx := r.Context()
+
+	user, err := c.userService.GetUserFromContext(ctx)
+	if err != nil {
+		if errors.Is(err, auth.ErrNoClaims) {
+			apiutil.Err(c.l, w, &apiutil.ApiError{
+				Status: http.StatusUnauthorized,
+				Cause:  err,
+			})
+		} else {
+			apiutil.Err(c.l, w, err)
+		}
+		return
+	}
+
+	cursor, err := apiutil.QueryInt(r, "cursor", 0)
+	if err != nil {
+		apiutil.Err(c.l, w, &apiutil.ApiError{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid cursor",
+		})
+		return
+	}
+
+	limit, err := apiutil.QueryInt(r, "limit", 0)
+	if err != nil {
+		apiutil.Err(c.l, w, &apiutil.ApiError{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid limit",
+		})
+		return
+	}
+
+	page, err := c.studySessionUseCase.GetHistory(ctx, user.ID, int64(cursor), limit)
+	if err != nil {
+		if errors.Is(err, usecase.ErrValidation) {
+			apiutil.Err(c.l, w, &apiutil.ApiError{
+				Status: http.StatusBadRequest,
+				Cause:  err,
+			})
+		} else {
+			apiutil.Err(c.l, w, err)
+		}
+		return
+	}
+
+	apiutil.Json(c.l, w, http.StatusOK, page)
+}
+
 // GetRecentStudySessions is an endpoint for getting recent study sessions for the authenticated user.
 func (c *MeController) GetRecentStudySessions(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
@@ -290,3 +416,168 @@ func (c *MeController) RefreshStudySession(w http.ResponseWriter, r *http.Reques
 
 	apiutil.Empty(w, http.StatusOK)
 }
+
+// StartStudySession is an endpoint handler for starting a new study session.
+func (c *MeController) StartStudySession(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, err := c.userService.GetUserFromContext(ctx)
+	if err != nil {
+		if errors.Is(err, auth.ErrNoClaims) {
+			apiutil.Err(c.l, w, &apiutil.ApiError{
+				Status: http.StatusUnauthorized,
+				Cause:  err,
+			})
+		} else {
+			apiutil.Err(c.l, w, err)
+		}
+		return
+	}
+
+	var body domain.StartStudySessionData
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		apiutil.Err(c.l, w, &apiutil.ApiError{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid request body",
+			Cause:   err,
+		})
+		return
+	}
+
+	createdID, err := c.studySessionUseCase.Start(ctx, user.ID, &body)
+	if err != nil {
+		var errNotFound *usecase.ErrNotFound
+		if errors.As(err, &errNotFound) {
+			apiutil.Err(c.l, w, &apiutil.ApiError{
+				Status:  http.StatusNotFound,
+				Message: errNotFound.Error(),
+			})
+		} else if errors.Is(err, usecase.ErrValidation) {
+			apiutil.Err(c.l, w, &apiutil.ApiError{
+				Status: http.StatusBadRequest,
+				Cause:  err,
+			})
+		} else {
+			apiutil.Err(c.l, w, err)
+		}
+		return
+	}
+
+	apiutil.Json(c.l, w, http.StatusCreated, map[string]int64{"createdId": createdID})
+}
+
+// FinishStudySession is an endpoint handler for finishing the study session with per card outcomes.
+func (c *MeController) FinishStudySession(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, err := c.userService.GetUserFromContext(ctx)
+	if err != nil {
+		if errors.Is(err, auth.ErrNoClaims) {
+			apiutil.Err(c.l, w, &apiutil.ApiError{
+				Status: http.StatusUnauthorized,
+				Cause:  err,
+			})
+		} else {
+			apiutil.Err(c.l, w, err)
+		}
+		return
+	}
+
+	studySessionID, err := strconv.ParseInt(chi.URLParam(r, "studySessionID"), 10, 64)
+	if err != nil {
+		apiutil.Err(c.l, w, &apiutil.ApiError{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid study session ID",
+		})
+		return
+	}
+
+	var body domain.FinishStudySessionData
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		apiutil.Err(c.l, w, &apiutil.ApiError{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid request body",
+			Cause:   err,
+		})
+		return
+	}
+
+	summary, err := c.studySessionUseCase.Finish(ctx, user.ID, studySessionID, &body)
+	if err != nil {
+		var errNotFound *usecase.ErrNotFound
+		if errors.As(err, &errNotFound) {
+			apiutil.Err(c.l, w, &apiutil.ApiError{
+				Status:  http.StatusNotFound,
+				Message: errNotFound.Error(),
+			})
+		} else if errors.Is(err, usecase.ErrValidation) {
+			apiutil.Err(c.l, w, &apiutil.ApiError{
+				Status: http.StatusBadRequest,
+				Cause:  err,
+			})
+		} else if errors.Is(err, usecase.ErrForbidden) {
+			apiutil.Err(c.l, w, &apiutil.ApiError{
+				Status: http.StatusForbidden,
+				Cause:  err,
+			})
+		} else if errors.Is(err, usecase.ErrStudySessionFinished) {
+			apiutil.Err(c.l, w, &apiutil.ApiError{
+				Status:  http.StatusConflict,
+				Message: "Study session has already been finished",
+			})
+		} else {
+			apiutil.Err(c.l, w, err)
+		}
+		return
+	}
+
+	apiutil.Json(c.l, w, http.StatusOK, summary)
+}
+
+// GetStudySessionSummary is an endpoint handler for getting the study session together with its per card outcomes.
+func (c *MeController) GetStudySessionSummary(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, err := c.userService.GetUserFromContext(ctx)
+	if err != nil {
+		if errors.Is(err, auth.ErrNoClaims) {
+			apiutil.Err(c.l, w, &apiutil.ApiError{
+				Status: http.StatusUnauthorized,
+				Cause:  err,
+			})
+		} else {
+			apiutil.Err(c.l, w, err)
+		}
+		return
+	}
+
+	studySessionID, err := strconv.ParseInt(chi.URLParam(r, "studySessionID"), 10, 64)
+	if err != nil {
+		apiutil.Err(c.l, w, &apiutil.ApiError{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid study session ID",
+		})
+		return
+	}
+
+	details, err := c.studySessionUseCase.GetDetails(ctx, user.ID, studySessionID)
+	if err != nil {
+		var errNotFound *usecase.ErrNotFound
+		if errors.As(err, &errNotFound) {
+			apiutil.Err(c.l, w, &apiutil.ApiError{
+				Status:  http.StatusNotFound,
+				Message: errNotFound.Error(),
+			})
+		} else if errors.Is(err, usecase.ErrForbidden) {
+			apiutil.Err(c.l, w, &apiutil.ApiError{
+				Status: http.StatusForbidden,
+				Cause:  err,
+			})
+		} else {
+			apiutil.Err(c.l, w, err)
+		}
+		return
+	}
+
+	apiutil.Json(c.l, w, http.StatusOK, details)
+}
@@ -0,0 +1,221 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+
+	"ailingo/internal/domain"
+	"ailingo/internal/usecase"
+	"ailingo/pkg/apiutil"
+	"ailingo/pkg/auth"
+)
+
+type PlacementController struct {
+	l                *slog.Logger
+	userService      *auth.UserService
+	placementUseCase domain.PlacementUseCase
+}
+
+func NewPlacementController(l *slog.Logger, userService *auth.UserService, placementUseCase domain.PlacementUseCase) *PlacementController {
+	return &PlacementController{
+		l:                l,
+		userService:      userService,
+		placementUseCase: placementUseCase,
+	}
+}
+
+// Router registers placement test endpoints. It is meant to be mounted under /me.
+func (c *PlacementController) Router(r chi.Router) {
+	r.Post("/placement-tests", c.Start)
+	r.Get("/placement-tests/{testID}", c.Get)
+	r.Post("/placement-tests/{testID}/answers", c.Answer)
+	r.Get("/study-sets/recommended", c.GetRecommended)
+}
+
+// Start is an endpoint handler for starting a new placement test.
+func (c *PlacementController) Start(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, err := c.userService.GetUserFromContext(ctx)
+	if err != nil {
+		if errors.Is(err, auth.ErrNoClaims) {
+			apiutil.Err(c.l, w, &apiutil.ApiError{
+				Status: http.StatusUnauthorized,
+				Cause:  err,
+			})
+		} else {
+			apiutil.Err(c.l, w, err)
+		}
+		return
+	}
+
+	step, err := c.placementUseCase.Start(ctx, user.ID)
+	if err != nil {
+		apiutil.Err(c.l, w, err)
+		return
+	}
+
+	apiutil.Json(c.l, w, http.StatusCreated, step)
+}
+
+// Get is an endpoint handler for getting the current state of the placement test.
+func (c *PlacementController) Get(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, err := c.userService.GetUserFromContext(ctx)
+	if err != nil {
+		if errors.Is(err, auth.ErrNoClaims) {
+			apiutil.Err(c.l, w, &apiutil.ApiError{
+				Status: http.StatusUnauthorized,
+				Cause:  err,
+			})
+		} else {
+			apiutil.Err(c.l, w, err)
+		}
+		return
+	}
+
+	testID, err := strconv.ParseInt(chi.URLParam(r, "testID"), 10, 64)
+	if err != nil {
+		apiutil.Err(c.l, w, &apiutil.ApiError{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid placement test ID",
+		})
+		return
+	}
+
+	step, err := c.placementUseCase.Get(ctx, user.ID, testID)
+	if err != nil {
+		var errNotFound *usecase.ErrNotFound
+		if errors.As(err, &errNotFound) {
+			apiutil.Err(c.l, w, &apiutil.ApiError{
+				Status:  http.StatusNotFound,
+				Message: errNotFound.Error(),
+			})
+		} else if errors.Is(err, usecase.ErrForbidden) {
+			apiutil.Err(c.l, w, &apiutil.ApiError{
+				Status: http.StatusForbidden,
+				Cause:  err,
+			})
+		} else {
+			apiutil.Err(c.l, w, err)
+		}
+		return
+	}
+
+	apiutil.Json(c.l, w, http.StatusOK, step)
+}
+
+// Answer is an endpoint handler for answering the current placement test question.
+func (c *PlacementController) Answer(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, err := c.userService.GetUserFromContext(ctx)
+	if err != nil {
+		if errors.Is(err, auth.ErrNoClaims) {
+			apiutil.Err(c.l, w, &apiutil.ApiError{
+				Status: http.StatusUnauthorized,
+				Cause:  err,
+			})
+		} else {
+			apiutil.Err(c.l, w, err)
+		}
+		return
+	}
+
+	testID, err := strconv.ParseInt(chi.URLParam(r, "testID"), 10, 64)
+	if err != nil {
+		apiutil.Err(c.l, w, &apiutil.ApiError{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid placement test ID",
+		})
+		return
+	}
+
+	var answerData domain.PlacementAnswerData
+	if err := json.NewDecoder(r.Body).Decode(&answerData); err != nil {
+		apiutil.Err(c.l, w, &apiutil.ApiError{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid request body",
+			Cause:   err,
+		})
+		return
+	}
+
+	step, err := c.placementUseCase.Answer(ctx, user.ID, testID, &answerData)
+	if err != nil {
+		var errNotFound *usecase.ErrNotFound
+		if errors.As(err, &errNotFound) {
+			apiutil.Err(c.l, w, &apiutil.ApiError{
+				Status:  http.StatusNotFound,
+				Message: errNotFound.Error(),
+			})
+		} else if errors.Is(err, usecase.ErrValidation) {
+			apiutil.Err(c.l, w, &apiutil.ApiError{
+				Status: http.StatusBadRequest,
+				Cause:  err,
+			})
+		} else if errors.Is(err, usecase.ErrForbidden) {
+			apiutil.Err(c.l, w, &apiutil.ApiError{
+				Status: http.StatusForbidden,
+				Cause:  err,
+			})
+		} else if errors.Is(err, usecase.ErrPlacementFinished) {
+			apiutil.Err(c.l, w, &apiutil.ApiError{
+				Status:  http.StatusConflict,
+				Message: "Placement test has already been finished",
+				Cause:   err,
+			})
+		} else if errors.Is(err, usecase.ErrPlacementNotCurrent) {
+			apiutil.Err(c.l, w, &apiutil.ApiError{
+				Status:  http.StatusConflict,
+				Message: "Question is not the current question of the placement test",
+				Cause:   err,
+			})
+		} else {
+			apiutil.Err(c.l, w, err)
+		}
+		return
+	}
+
+	apiutil.Json(c.l, w, http.StatusOK, step)
+}
+
+// GetRecommended is an endpoint handler for getting study sets matching the user's CEFR level.
+func (c *PlacementController) GetRecommended(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, err := c.userService.GetUserFromContext(ctx)
+	if err != nil {
+		if errors.Is(err, auth.ErrNoClaims) {
+			apiutil.Err(c.l, w, &apiutil.ApiError{
+				Status: http.StatusUnauthorized,
+				Cause:  err,
+			})
+		} else {
+			apiutil.Err(c.l, w, err)
+		}
+		return
+	}
+
+	studySets, err := c.placementUseCase.GetRecommended(ctx, user.ID)
+	if err != nil {
+		if errors.Is(err, usecase.ErrPlacementNotTaken) {
+			apiutil.Err(c.l, w, &apiutil.ApiError{
+				Status:  http.StatusConflict,
+				Message: "Placement test has not been taken yet",
+				Cause:   err,
+			})
+		} else {
+			apiutil.Err(c.l, w, err)
+		}
+		return
+	}
+
+	apiutil.Json(c.l, w, http.StatusOK, studySets)
+}
@@ -0,0 +1,306 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+
+	"ailingo/internal/domain"
+	"ailingo/internal/usecase"
+	"ailingo/pkg/apiutil"
+	"ailingo/pkg/auth"
+)
+
+type ReviewController struct {
+	l             *slog.Logger
+	userService   *auth.UserService
+	reviewUseCase domain.ReviewUseCase
+}
+
+func NewReviewController(l *slog.Logger, userService *auth.UserService, reviewUseCase domain.ReviewUseCase) *ReviewController {
+	return &ReviewController{
+		l:             l,
+		userService:   userService,
+		reviewUseCase: reviewUseCase,
+	}
+}
+
+// Router registers review endpoints. It is meant to be mounted under /me.
+func (c *ReviewController) Router(r chi.Router) {
+	r.Get("/study-sets/{studySetID}/reviews/next", c.GetNext)
+	r.Post("/study-sets/{studySetID}/reviews", c.Grade)
+	r.Get("/reviews/due", c.GetDueQueue)
+	r.Get("/study-sets/{studySetID}/progress", c.GetProgress)
+	r.Get("/weak-words/definitions", c.GetWeakDefinitions)
+}
+
+// GetNext is an endpoint handler for getting definitions from the study set that should be studied next.
+func (c *ReviewController) GetNext(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, err := c.userService.GetUserFromContext(ctx)
+	if err != nil {
+		if errors.Is(err, auth.ErrNoClaims) {
+			apiutil.Err(c.l, w, &apiutil.ApiError{
+				Status: http.StatusUnauthorized,
+				Cause:  err,
+			})
+		} else {
+			apiutil.Err(c.l, w, err)
+		}
+		return
+	}
+
+	studySetID, err := strconv.ParseInt(chi.URLParam(r, "studySetID"), 10, 64)
+	if err != nil {
+		apiutil.Err(c.l, w, &apiutil.ApiError{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid study set ID",
+		})
+		return
+	}
+
+	limit, err := apiutil.QueryInt(r, "limit", 0)
+	if err != nil {
+		apiutil.Err(c.l, w, &apiutil.ApiError{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid limit",
+		})
+		return
+	}
+
+	cards, err := c.reviewUseCase.GetNext(ctx, user.ID, studySetID, limit)
+	if err != nil {
+		var errNotFound *usecase.ErrNotFound
+		if errors.As(err, &errNotFound) {
+			apiutil.Err(c.l, w, &apiutil.ApiError{
+				Status:  http.StatusNotFound,
+				Message: errNotFound.Error(),
+			})
+		} else if errors.Is(err, usecase.ErrValidation) {
+			apiutil.Err(c.l, w, &apiutil.ApiError{
+				Status: http.StatusBadRequest,
+				Cause:  err,
+			})
+		} else {
+			apiutil.Err(c.l, w, err)
+		}
+		return
+	}
+
+	apiutil.Json(c.l, w, http.StatusOK, cards)
+}
+
+// Grade is an endpoint handler for submitting a batch of graded answers for definitions from the study set.
+func (c *ReviewController) Grade(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, err := c.userService.GetUserFromContext(ctx)
+	if err != nil {
+		if errors.Is(err, auth.ErrNoClaims) {
+			apiutil.Err(c.l, w, &apiutil.ApiError{
+				Status: http.StatusUnauthorized,
+				Cause:  err,
+			})
+		} else {
+			apiutil.Err(c.l, w, err)
+		}
+		return
+	}
+
+	studySetID, err := strconv.ParseInt(chi.URLParam(r, "studySetID"), 10, 64)
+	if err != nil {
+		apiutil.Err(c.l, w, &apiutil.ApiError{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid study set ID",
+		})
+		return
+	}
+
+	var batchData domain.ReviewBatchData
+	if err := json.NewDecoder(r.Body).Decode(&batchData); err != nil {
+		apiutil.Err(c.l, w, &apiutil.ApiError{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid request body",
+			Cause:   err,
+		})
+		return
+	}
+
+	if err := c.reviewUseCase.Grade(ctx, user.ID, studySetID, &batchData); err != nil {
+		var errNotFound *usecase.ErrNotFound
+		if errors.As(err, &errNotFound) {
+			apiutil.Err(c.l, w, &apiutil.ApiError{
+				Status:  http.StatusNotFound,
+				Message: errNotFound.Error(),
+			})
+		} else if errors.Is(err, usecase.ErrValidation) {
+			apiutil.Err(c.l, w, &apiutil.ApiError{
+				Status: http.StatusBadRequest,
+				Cause:  err,
+			})
+		} else {
+			apiutil.Err(c.l, w, err)
+		}
+		return
+	}
+
+	apiutil.Empty(w, http.StatusCreated)
+}
+
+// GetDueQueue is an endpoint handler for getting a single review queue built from all study sets the user has studied.
+func (c *ReviewController) GetDueQueue(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, err := c.userService.GetUserFromContext(ctx)
+	if err != nil {
+		if errors.Is(err, auth.ErrNoClaims) {
+			apiutil.Err(c.l, w, &apiutil.ApiError{
+				Status: http.StatusUnauthorized,
+				Cause:  err,
+			})
+		} else {
+			apiutil.Err(c.l, w, err)
+		}
+		return
+	}
+
+	var params domain.DueQueueParams
+	if params.Limit, err = apiutil.QueryInt(r, "limit", 0); err != nil {
+		apiutil.Err(c.l, w, &apiutil.ApiError{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid limit",
+		})
+		return
+	}
+	if r.URL.Query().Has("newLimit") {
+		newLimit, err := apiutil.QueryInt(r, "newLimit", 0)
+		if err != nil {
+			apiutil.Err(c.l, w, &apiutil.ApiError{
+				Status:  http.StatusBadRequest,
+				Message: "Invalid new cards limit",
+			})
+			return
+		}
+		params.NewLimit = &newLimit
+	}
+	if params.ReviewLimit, err = apiutil.QueryInt(r, "reviewLimit", 0); err != nil {
+		apiutil.Err(c.l, w, &apiutil.ApiError{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid review cards limit",
+		})
+		return
+	}
+	if params.Interleave, err = apiutil.QueryBool(r, "interleave", false); err != nil {
+		apiutil.Err(c.l, w, &apiutil.ApiError{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid interleave flag",
+		})
+		return
+	}
+
+	cards, err := c.reviewUseCase.GetDueQueue(ctx, user.ID, &params)
+	if err != nil {
+		if errors.Is(err, usecase.ErrValidation) {
+			apiutil.Err(c.l, w, &apiutil.ApiError{
+				Status: http.StatusBadRequest,
+				Cause:  err,
+			})
+		} else {
+			apiutil.Err(c.l, w, err)
+		}
+		return
+	}
+
+	apiutil.Json(c.l, w, http.StatusOK, cards)
+}
+
+// GetProgress is an endpoint handler for getting the user's progress on the study set.
+func (c *ReviewController) GetProgress(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, err := c.userService.GetUserFromContext(ctx)
+	if err != nil {
+		if errors.Is(err, auth.ErrNoClaims) {
+			apiutil.Err(c.l, w, &apiutil.ApiError{
+				Status: http.StatusUnauthorized,
+				Cause:  err,
+			})
+		} else {
+			apiutil.Err(c.l, w, err)
+		}
+		return
+	}
+
+	studySetID, err := strconv.ParseInt(chi.URLParam(r, "studySetID"), 10, 64)
+	if err != nil {
+		apiutil.Err(c.l, w, &apiutil.ApiError{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid study set ID",
+		})
+		return
+	}
+
+	progress, err := c.reviewUseCase.GetProgress(ctx, user.ID, studySetID)
+	if err != nil {
+		var errNotFound *usecase.ErrNotFound
+		if errors.As(err, &errNotFound) {
+			apiutil.Err(c.l, w, &apiutil.ApiError{
+				Status:  http.StatusNotFound,
+				Message: errNotFound.Error(),
+			})
+		} else {
+			apiutil.Err(c.l, w, err)
+		}
+		return
+	}
+
+	apiutil.Json(c.l, w, http.StatusOK, progress)
+}
+
+// GetWeakDefinitions is an endpoint handler for getting definitions the user keeps failing across all study sets.
+// The response uses the same shape as the study set definitions listing.
+func (c *ReviewController) GetWeakDefinitions(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, err := c.userService.GetUserFromContext(ctx)
+	if err != nil {
+		if errors.Is(err, auth.ErrNoClaims) {
+			apiutil.Err(c.l, w, &apiutil.ApiError{
+				Status: http.StatusUnauthorized,
+				Cause:  err,
+			})
+		} else {
+			apiutil.Err(c.l, w, err)
+		}
+		return
+	}
+
+	limit, err := apiutil.QueryInt(r, "limit", 0)
+	if err != nil {
+		apiutil.Err(c.l, w, &apiutil.ApiError{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid limit",
+		})
+		return
+	}
+
+	definitions, err := c.reviewUseCase.GetWeakDefinitions(ctx, user.ID, limit)
+	if err != nil {
+		if errors.Is(err, usecase.ErrValidation) {
+			apiutil.Err(c.l, w, &apiutil.ApiError{
+				Status: http.StatusBadRequest,
+				Cause:  err,
+			})
+		} else {
+			apiutil.Err(c.l, w, err)
+		}
+		return
+	}
+
+	apiutil.Json(c.l, w, http.StatusOK, definitions)
+}
@@ -0,0 +1,65 @@
+package controller
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"ailingo/internal/domain"
+	"ailingo/internal/usecase"
+	"ailingo/pkg/apiutil"
+	"ailingo/pkg/auth"
+)
+
+type SearchController struct {
+	l             *slog.Logger
+	userService   *auth.UserService
+	searchUseCase domain.SearchUseCase
+}
+
+func NewSearchController(l *slog.Logger, userService *auth.UserService, searchUseCase domain.SearchUseCase) *SearchController {
+	return &SearchController{
+		l:             l,
+		userService:   userService,
+		searchUseCase: searchUseCase,
+	}
+}
+
+// Router registers search endpoints. It is meant to be mounted under /search with optional claims.
+func (c *SearchController) Router(r chi.Router) {
+	r.Get("/", c.Search)
+}
+
+// Search is an endpoint handler for searching study sets and definitions, grouped by study set.
+func (c *SearchController) Search(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	limit, err := apiutil.QueryInt(r, "limit", 0)
+	if err != nil {
+		apiutil.Err(c.l, w, &apiutil.ApiError{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid limit",
+		})
+		return
+	}
+
+	results, err := c.searchUseCase.Search(ctx, c.userService.GetUserIDFromContext(ctx), &domain.SearchData{
+		Query: r.URL.Query().Get("q"),
+		Limit: limit,
+	})
+	if err != nil {
+		if errors.Is(err, usecase.ErrValidation) {
+			apiutil.Err(c.l, w, &apiutil.ApiError{
+				Status: http.StatusBadRequest,
+				Cause:  err,
+			})
+		} else {
+			apiutil.Err(c.l, w, err)
+		}
+		return
+	}
+
+	apiutil.Json(c.l, w, http.StatusOK, results)
+}
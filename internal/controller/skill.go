@@ -0,0 +1,58 @@
+package controller
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"ailingo/internal/domain"
+	"ailingo/pkg/apiutil"
+	"ailingo/pkg/auth"
+)
+
+type SkillController struct {
+	l            *slog.Logger
+	userService  *auth.UserService
+	skillUseCase domain.SkillUseCase
+}
+
+func NewSkillController(l *slog.Logger, userService *auth.UserService, skillUseCase domain.SkillUseCase) *SkillController {
+	return &SkillController{
+		l:            l,
+		userService:  userService,
+		skillUseCase: skillUseCase,
+	}
+}
+
+// Router registers skill endpoints. It is meant to be mounted under /me.
+func (c *SkillController) Router(r chi.Router) {
+	r.Get("/skill", c.GetAll)
+}
+
+// GetAll is an endpoint handler for getting the user's ability estimates in all languages they have studied.
+func (c *SkillController) GetAll(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, err := c.userService.GetUserFromContext(ctx)
+	if err != nil {
+		if errors.Is(err, auth.ErrNoClaims) {
+			apiutil.Err(c.l, w, &apiutil.ApiError{
+				Status: http.StatusUnauthorized,
+				Cause:  err,
+			})
+		} else {
+			apiutil.Err(c.l, w, err)
+		}
+		return
+	}
+
+	skills, err := c.skillUseCase.GetAll(ctx, user.ID)
+	if err != nil {
+		apiutil.Err(c.l, w, err)
+		return
+	}
+
+	apiutil.Json(c.l, w, http.StatusOK, skills)
+}
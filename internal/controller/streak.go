@@ -0,0 +1,130 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"ailingo/internal/domain"
+	"ailingo/internal/usecase"
+	"ailingo/pkg/apiutil"
+	"ailingo/pkg/auth"
+)
+
+type StreakController struct {
+	l             *slog.Logger
+	userService   *auth.UserService
+	streakUseCase domain.StreakUseCase
+}
+
+func NewStreakController(l *slog.Logger, userService *auth.UserService, streakUseCase domain.StreakUseCase) *StreakController {
+	return &StreakController{
+		l:             l,
+		userService:   userService,
+		streakUseCase: streakUseCase,
+	}
+}
+
+// Router registers streak and daily goal endpoints. It is meant to be mounted under /me.
+func (c *StreakController) Router(r chi.Router) {
+	r.Get("/streak", c.GetStreak)
+	r.Get("/goals", c.GetDailyGoal)
+	r.Put("/goals", c.UpdateDailyGoal)
+}
+
+// GetStreak is an endpoint handler for getting the user's study streak.
+func (c *StreakController) GetStreak(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, err := c.userService.GetUserFromContext(ctx)
+	if err != nil {
+		if errors.Is(err, auth.ErrNoClaims) {
+			apiutil.Err(c.l, w, &apiutil.ApiError{
+				Status: http.StatusUnauthorized,
+				Cause:  err,
+			})
+		} else {
+			apiutil.Err(c.l, w, err)
+		}
+		return
+	}
+
+	streak, err := c.streakUseCase.GetStreak(ctx, user.ID)
+	if err != nil {
+		apiutil.Err(c.l, w, err)
+		return
+	}
+
+	apiutil.Json(c.l, w, http.StatusOK, streak)
+}
+
+// GetDailyGoal is an endpoint handler for getting the user's daily goal together with today's progress.
+func (c *StreakController) GetDailyGoal(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, err := c.userService.GetUserFromContext(ctx)
+	if err != nil {
+		if errors.Is(err, auth.ErrNoClaims) {
+			apiutil.Err(c.l, w, &apiutil.ApiError{
+				Status: http.StatusUnauthorized,
+				Cause:  err,
+			})
+		} else {
+			apiutil.Err(c.l, w, err)
+		}
+		return
+	}
+
+	goal, err := c.streakUseCase.GetDailyGoal(ctx, user.ID)
+	if err != nil {
+		apiutil.Err(c.l, w, err)
+		return
+	}
+
+	apiutil.Json(c.l, w, http.StatusOK, goal)
+}
+
+// UpdateDailyGoal is an endpoint handler for changing the user's daily goal and timezone.
+func (c *StreakController) UpdateDailyGoal(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, err := c.userService.GetUserFromContext(ctx)
+	if err != nil {
+		if errors.Is(err, auth.ErrNoClaims) {
+			apiutil.Err(c.l, w, &apiutil.ApiError{
+				Status: http.StatusUnauthorized,
+				Cause:  err,
+			})
+		} else {
+			apiutil.Err(c.l, w, err)
+		}
+		return
+	}
+
+	var updateData domain.UpdateDailyGoalData
+	if err := json.NewDecoder(r.Body).Decode(&updateData); err != nil {
+		apiutil.Err(c.l, w, &apiutil.ApiError{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid request body",
+			Cause:   err,
+		})
+		return
+	}
+
+	if err := c.streakUseCase.UpdateDailyGoal(ctx, user.ID, &updateData); err != nil {
+		if errors.Is(err, usecase.ErrValidation) {
+			apiutil.Err(c.l, w, &apiutil.ApiError{
+				Status: http.StatusBadRequest,
+				Cause:  err,
+			})
+		} else {
+			apiutil.Err(c.l, w, err)
+		}
+		return
+	}
+
+	apiutil.Empty(w, http.StatusOK)
+}
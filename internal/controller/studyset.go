@@ -3,9 +3,12 @@ package controller
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
 	"log/slog"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/go-chi/chi/v5"
 
@@ -15,6 +18,9 @@ import (
 	"ailingo/pkg/auth"
 )
 
+// maxImportFileSize is the maximum size of an uploaded file with definitions in bytes.
+const maxImportFileSize = 1 << 20
+
 type StudySetController struct {
 	l                 *slog.Logger
 	userService       *auth.UserService
@@ -31,38 +37,59 @@ func NewStudySetController(l *slog.Logger, userService *auth.UserService, studyS
 	}
 }
 
-func (c *StudySetController) Router(withClaims func(next http.Handler) http.Handler) func(r chi.Router) {
+func (c *StudySetController) Router(withClaims func(next http.Handler) http.Handler, withOptionalClaims func(next http.Handler) http.Handler) func(r chi.Router) {
 	return func(r chi.Router) {
-		r.Get("/", c.GetAll)
-		r.Get("/{studySetID}", c.GetById)
-		r.Get("/{parentStudySetID}/definitions", c.GetDefinitions)
+		r.Group(func(r chi.Router) {
+			r.Use(withOptionalClaims)
+			r.Get("/", c.GetAll)
+			r.Get("/{studySetID}", c.GetById)
+			r.Get("/{parentStudySetID}/definitions", c.GetDefinitions)
+			r.Post("/{parentStudySetID}/definitions/{definitionID}/check", c.CheckAnswer)
+		})
 
 		r.Route("/", func(r chi.Router) {
 			r.Use(withClaims)
 			r.Post("/", c.Create)
+			r.Post("/{studySetID}/fork", c.Fork)
 			r.Put("/{studySetID}", c.Update)
 			r.Delete("/{studySetID}", c.Delete)
 
 			// TODO: We could make a separate controller for /definitions endpoints
 			r.Post("/{parentStudySetID}/definitions", c.CreateDefinition)
 			r.Post("/{parentStudySetID}/definitions/fill", c.AIFill)
+			r.Post("/{parentStudySetID}/definitions/batch", c.BatchDefinitions)
+			r.Post("/{parentStudySetID}/definitions/import/preview", c.PreviewImport)
+			r.Post("/{parentStudySetID}/definitions/import", c.Import)
 			r.Put("/{parentStudySetID}/definitions/{definitionID}", c.UpdateDefinition)
 			r.Delete("/{parentStudySetID}/definitions/{definitionID}", c.DeleteDefinition)
 		})
 	}
 }
 
-// GetAll is an endpoint handler for getting a summary for all existing study sets.
+// GetAll is an endpoint handler for getting a page of study sets matching the filters.
 func (c *StudySetController) GetAll(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
-	studySets, err := c.studySetUseCase.GetAll(ctx)
+	filter, limit, err := studySetListQuery(r)
 	if err != nil {
 		apiutil.Err(c.l, w, err)
 		return
 	}
 
-	apiutil.Json(c.l, w, http.StatusOK, studySets)
+	page, err := c.studySetUseCase.GetPage(ctx, filter, r.URL.Query().Get("cursor"), limit)
+	if err != nil {
+		if errors.Is(err, usecase.ErrValidation) {
+			apiutil.Err(c.l, w, &apiutil.ApiError{
+				Status: http.StatusBadRequest,
+				Cause:  err,
+			})
+		} else {
+			apiutil.Err(c.l, w, err)
+		}
+		return
+	}
+
+	apiutil.Json(c.l, w, http.StatusOK, page)
 }
 
 // GetById is an endpoint handler for getting full information about a specific study set.
@@ -78,7 +105,7 @@ func (c *StudySetController) GetById(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	studySet, err := c.studySetUseCase.GetById(ctx, studySetID)
+	studySet, err := c.studySetUseCase.GetById(ctx, c.userService.GetUserIDFromContext(ctx), studySetID)
 	if err != nil {
 		var errNotFound *usecase.ErrNotFound
 		if errors.As(err, &errNotFound) {
@@ -139,6 +166,49 @@ func (c *StudySetController) Create(w http.ResponseWriter, r *http.Request) {
 	apiutil.Json(c.l, w, http.StatusCreated, map[string]int64{"createdId": createdID})
 }
 
+// Fork is an endpoint handler for copying a study set with its definitions into the user's library.
+func (c *StudySetController) Fork(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, err := c.userService.GetUserFromContext(ctx)
+	if err != nil {
+		if errors.Is(err, auth.ErrNoClaims) {
+			apiutil.Err(c.l, w, &apiutil.ApiError{
+				Status: http.StatusUnauthorized,
+				Cause:  err,
+			})
+		} else {
+			apiutil.Err(c.l, w, err)
+		}
+		return
+	}
+
+	studySetID, err := strconv.ParseInt(chi.URLParam(r, "studySetID"), 10, 64)
+	if err != nil {
+		apiutil.Err(c.l, w, &apiutil.ApiError{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid study set ID",
+		})
+		return
+	}
+
+	createdID, err := c.studySetUseCase.Fork(ctx, user.ID, studySetID)
+	if err != nil {
+		var errNotFound *usecase.ErrNotFound
+		if errors.As(err, &errNotFound) {
+			apiutil.Err(c.l, w, &apiutil.ApiError{
+				Status:  http.StatusNotFound,
+				Message: errNotFound.Error(),
+			})
+		} else {
+			apiutil.Err(c.l, w, err)
+		}
+		return
+	}
+
+	apiutil.Json(c.l, w, http.StatusCreated, map[string]int64{"createdId": createdID})
+}
+
 // Update is an endpoint for replacing data of existing study set.
 func (c *StudySetController) Update(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
@@ -261,7 +331,7 @@ func (c *StudySetController) GetDefinitions(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
-	definitions, err := c.definitionUseCase.GetAllFor(ctx, parentStudySetID)
+	definitions, err := c.definitionUseCase.GetAllFor(ctx, c.userService.GetUserIDFromContext(ctx), parentStudySetID)
 	if err != nil {
 		var errNotFound *usecase.ErrNotFound
 		if errors.As(err, &errNotFound) {
@@ -310,6 +380,11 @@ func (c *StudySetController) AIFill(w http.ResponseWriter, r *http.Request) {
 				Status:  http.StatusNotFound,
 				Message: errNotFound.Error(),
 			})
+		} else if errors.Is(err, usecase.ErrForbidden) {
+			apiutil.Err(c.l, w, &apiutil.ApiError{
+				Status: http.StatusForbidden,
+				Cause:  err,
+			})
 		} else {
 			apiutil.Err(c.l, w, err)
 		}
@@ -362,6 +437,11 @@ func (c *StudySetController) CreateDefinition(w http.ResponseWriter, r *http.Req
 				Status:  http.StatusNotFound,
 				Message: errNotFound.Error(),
 			})
+		} else if errors.Is(err, usecase.ErrForbidden) {
+			apiutil.Err(c.l, w, &apiutil.ApiError{
+				Status: http.StatusForbidden,
+				Cause:  err,
+			})
 		} else {
 			apiutil.Err(c.l, w, err)
 		}
@@ -371,6 +451,195 @@ func (c *StudySetController) CreateDefinition(w http.ResponseWriter, r *http.Req
 	apiutil.Empty(w, http.StatusCreated)
 }
 
+// BatchDefinitions is an endpoint handler for creating, updating, deleting and reordering definitions at once.
+// If any of the operations is invalid, none of them is applied and the reasons are returned for each invalid operation.
+func (c *StudySetController) BatchDefinitions(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, err := c.userService.GetUserFromContext(ctx)
+	if err != nil {
+		if errors.Is(err, auth.ErrNoClaims) {
+			apiutil.Err(c.l, w, &apiutil.ApiError{
+				Status: http.StatusUnauthorized,
+				Cause:  err,
+			})
+		} else {
+			apiutil.Err(c.l, w, err)
+		}
+		return
+	}
+
+	parentStudySetID, err := strconv.ParseInt(chi.URLParam(r, "parentStudySetID"), 10, 64)
+	if err != nil {
+		apiutil.Err(c.l, w, &apiutil.ApiError{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid study set ID",
+		})
+		return
+	}
+
+	var batchData domain.BatchDefinitionData
+	if err := json.NewDecoder(r.Body).Decode(&batchData); err != nil {
+		apiutil.Err(c.l, w, &apiutil.ApiError{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid request body",
+			Cause:   err,
+		})
+		return
+	}
+
+	result, err := c.definitionUseCase.Batch(ctx, user.ID, parentStudySetID, &batchData)
+	if err != nil {
+		var errNotFound *usecase.ErrNotFound
+		var errInvalidOperations *usecase.ErrInvalidOperations
+		if errors.As(err, &errNotFound) {
+			apiutil.Err(c.l, w, &apiutil.ApiError{
+				Status:  http.StatusNotFound,
+				Message: errNotFound.Error(),
+			})
+		} else if errors.As(err, &errInvalidOperations) {
+			apiutil.Json(c.l, w, http.StatusBadRequest, map[string]any{
+				"error":      "Invalid operations",
+				"operations": errInvalidOperations.Errors,
+			})
+		} else if errors.Is(err, usecase.ErrValidation) {
+			apiutil.Err(c.l, w, &apiutil.ApiError{
+				Status: http.StatusBadRequest,
+				Cause:  err,
+			})
+		} else if errors.Is(err, usecase.ErrForbidden) {
+			apiutil.Err(c.l, w, &apiutil.ApiError{
+				Status: http.StatusForbidden,
+				Cause:  err,
+			})
+		} else {
+			apiutil.Err(c.l, w, err)
+		}
+		return
+	}
+
+	apiutil.Json(c.l, w, http.StatusOK, result)
+}
+
+// PreviewImport is an endpoint handler for parsing definitions exported from other tools without saving them.
+// The text is sent either as JSON or as a file field of a multipart form.
+func (c *StudySetController) PreviewImport(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, err := c.userService.GetUserFromContext(ctx)
+	if err != nil {
+		if errors.Is(err, auth.ErrNoClaims) {
+			apiutil.Err(c.l, w, &apiutil.ApiError{
+				Status: http.StatusUnauthorized,
+				Cause:  err,
+			})
+		} else {
+			apiutil.Err(c.l, w, err)
+		}
+		return
+	}
+
+	parentStudySetID, err := strconv.ParseInt(chi.URLParam(r, "parentStudySetID"), 10, 64)
+	if err != nil {
+		apiutil.Err(c.l, w, &apiutil.ApiError{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid study set ID",
+		})
+		return
+	}
+
+	importData, err := importDefinitionsRequest(r)
+	if err != nil {
+		apiutil.Err(c.l, w, err)
+		return
+	}
+
+	preview, err := c.definitionUseCase.PreviewImport(ctx, user.ID, parentStudySetID, importData)
+	if err != nil {
+		var errNotFound *usecase.ErrNotFound
+		if errors.As(err, &errNotFound) {
+			apiutil.Err(c.l, w, &apiutil.ApiError{
+				Status:  http.StatusNotFound,
+				Message: errNotFound.Error(),
+			})
+		} else if errors.Is(err, usecase.ErrValidation) {
+			apiutil.Err(c.l, w, &apiutil.ApiError{
+				Status: http.StatusBadRequest,
+				Cause:  err,
+			})
+		} else if errors.Is(err, usecase.ErrForbidden) {
+			apiutil.Err(c.l, w, &apiutil.ApiError{
+				Status: http.StatusForbidden,
+				Cause:  err,
+			})
+		} else {
+			apiutil.Err(c.l, w, err)
+		}
+		return
+	}
+
+	apiutil.Json(c.l, w, http.StatusOK, preview)
+}
+
+// Import is an endpoint handler for adding definitions exported from other tools to the study set.
+func (c *StudySetController) Import(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, err := c.userService.GetUserFromContext(ctx)
+	if err != nil {
+		if errors.Is(err, auth.ErrNoClaims) {
+			apiutil.Err(c.l, w, &apiutil.ApiError{
+				Status: http.StatusUnauthorized,
+				Cause:  err,
+			})
+		} else {
+			apiutil.Err(c.l, w, err)
+		}
+		return
+	}
+
+	parentStudySetID, err := strconv.ParseInt(chi.URLParam(r, "parentStudySetID"), 10, 64)
+	if err != nil {
+		apiutil.Err(c.l, w, &apiutil.ApiError{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid study set ID",
+		})
+		return
+	}
+
+	importData, err := importDefinitionsRequest(r)
+	if err != nil {
+		apiutil.Err(c.l, w, err)
+		return
+	}
+
+	result, err := c.definitionUseCase.Import(ctx, user.ID, parentStudySetID, importData)
+	if err != nil {
+		var errNotFound *usecase.ErrNotFound
+		if errors.As(err, &errNotFound) {
+			apiutil.Err(c.l, w, &apiutil.ApiError{
+				Status:  http.StatusNotFound,
+				Message: errNotFound.Error(),
+			})
+		} else if errors.Is(err, usecase.ErrValidation) {
+			apiutil.Err(c.l, w, &apiutil.ApiError{
+				Status: http.StatusBadRequest,
+				Cause:  err,
+			})
+		} else if errors.Is(err, usecase.ErrForbidden) {
+			apiutil.Err(c.l, w, &apiutil.ApiError{
+				Status: http.StatusForbidden,
+				Cause:  err,
+			})
+		} else {
+			apiutil.Err(c.l, w, err)
+		}
+		return
+	}
+
+	apiutil.Json(c.l, w, http.StatusCreated, result)
+}
+
 // UpdateDefinition is an endpoint handler for updating definitions.
 func (c *StudySetController) UpdateDefinition(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
@@ -422,6 +691,11 @@ func (c *StudySetController) UpdateDefinition(w http.ResponseWriter, r *http.Req
 				Status:  http.StatusNotFound,
 				Message: errNotFound.Error(),
 			})
+		} else if errors.Is(err, usecase.ErrForbidden) {
+			apiutil.Err(c.l, w, &apiutil.ApiError{
+				Status: http.StatusForbidden,
+				Cause:  err,
+			})
 		} else {
 			apiutil.Err(c.l, w, err)
 		}
@@ -472,6 +746,11 @@ func (c *StudySetController) DeleteDefinition(w http.ResponseWriter, r *http.Req
 				Status:  http.StatusNotFound,
 				Message: errNotFound.Error(),
 			})
+		} else if errors.Is(err, usecase.ErrForbidden) {
+			apiutil.Err(c.l, w, &apiutil.ApiError{
+				Status: http.StatusForbidden,
+				Cause:  err,
+			})
 		} else {
 			apiutil.Err(c.l, w, err)
 		}
@@ -480,3 +759,155 @@ func (c *StudySetController) DeleteDefinition(w http.ResponseWriter, r *http.Req
 
 	apiutil.Empty(w, http.StatusOK)
 }
+
+// CheckAnswer is an endpoint handler for grading an answer typed by the learner against the definition.
+func (c *StudySetController) CheckAnswer(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	parentStudySetID, err := strconv.ParseInt(chi.URLParam(r, "parentStudySetID"), 10, 64)
+	if err != nil {
+		apiutil.Err(c.l, w, &apiutil.ApiError{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid study set ID",
+		})
+		return
+	}
+
+	definitionID, err := strconv.ParseInt(chi.URLParam(r, "definitionID"), 10, 64)
+	if err != nil {
+		apiutil.Err(c.l, w, &apiutil.ApiError{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid definition ID",
+		})
+		return
+	}
+
+	var answerData domain.CheckAnswerData
+	if err := json.NewDecoder(r.Body).Decode(&answerData); err != nil {
+		apiutil.Err(c.l, w, &apiutil.ApiError{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid request body",
+			Cause:   err,
+		})
+		return
+	}
+
+	result, err := c.definitionUseCase.CheckAnswer(ctx, c.userService.GetUserIDFromContext(ctx), parentStudySetID, definitionID, &answerData)
+	if err != nil {
+		var errNotFound *usecase.ErrNotFound
+		if errors.As(err, &errNotFound) {
+			apiutil.Err(c.l, w, &apiutil.ApiError{
+				Status:  http.StatusNotFound,
+				Message: errNotFound.Error(),
+			})
+		} else if errors.Is(err, usecase.ErrValidation) {
+			apiutil.Err(c.l, w, &apiutil.ApiError{
+				Status: http.StatusBadRequest,
+				Cause:  err,
+			})
+		} else {
+			apiutil.Err(c.l, w, err)
+		}
+		return
+	}
+
+	apiutil.Json(c.l, w, http.StatusOK, result)
+}
+
+// studySetListQuery reads study set list filters and the page limit from the query parameters.
+func studySetListQuery(r *http.Request) (*domain.StudySetFilter, int, error) {
+	query := r.URL.Query()
+
+	filter := &domain.StudySetFilter{
+		Sort:               query.Get("sort"),
+		PhraseLanguage:     query.Get("phraseLanguage"),
+		DefinitionLanguage: query.Get("definitionLanguage"),
+		AuthorId:           query.Get("author"),
+		CefrLevel:          query.Get("cefrLevel"),
+		Category:           query.Get("category"),
+		Tag:                query.Get("tag"),
+	}
+
+	if value := query.Get("hasDefinitions"); value != "" {
+		hasDefinitions, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, 0, &apiutil.ApiError{
+				Status:  http.StatusBadRequest,
+				Message: "Invalid has definitions flag",
+			}
+		}
+		filter.HasDefinitions = &hasDefinitions
+	}
+
+	limit, err := apiutil.QueryInt(r, "limit", 0)
+	if err != nil {
+		return nil, 0, &apiutil.ApiError{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid limit",
+		}
+	}
+
+	return filter, limit, nil
+}
+
+// importDefinitionsRequest reads import data either from a JSON body or from a multipart form with the text in the file field.
+func importDefinitionsRequest(r *http.Request) (*domain.ImportDefinitionsData, error) {
+	var importData domain.ImportDefinitionsData
+
+	if !strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		if err := json.NewDecoder(r.Body).Decode(&importData); err != nil {
+			return nil, &apiutil.ApiError{
+				Status:  http.StatusBadRequest,
+				Message: "Invalid request body",
+				Cause:   err,
+			}
+		}
+		return &importData, nil
+	}
+
+	if err := r.ParseMultipartForm(maxImportFileSize); err != nil {
+		return nil, &apiutil.ApiError{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid form",
+			Cause:   err,
+		}
+	}
+
+	file, _, err := r.FormFile("file")
+	if err != nil {
+		return nil, &apiutil.ApiError{
+			Status:  http.StatusBadRequest,
+			Message: "Missing file",
+			Cause:   err,
+		}
+	}
+	defer file.Close()
+
+	text, err := io.ReadAll(io.LimitReader(file, maxImportFileSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the file: %w", err)
+	}
+	if len(text) > maxImportFileSize {
+		return nil, &apiutil.ApiError{
+			Status:  http.StatusRequestEntityTooLarge,
+			Message: "File is too large",
+		}
+	}
+
+	importData.Text = string(text)
+	importData.Format = r.FormValue("format")
+	importData.TermSeparator = r.FormValue("termSeparator")
+	importData.RowSeparator = r.FormValue("rowSeparator")
+	for name, flag := range map[string]*bool{"skipHeader": &importData.SkipHeader, "skipInvalid": &importData.SkipInvalid} {
+		if value := r.FormValue(name); value != "" {
+			if *flag, err = strconv.ParseBool(value); err != nil {
+				return nil, &apiutil.ApiError{
+					Status:  http.StatusBadRequest,
+					Message: fmt.Sprintf("Invalid %s flag", name),
+				}
+			}
+		}
+	}
+
+	return &importData, nil
+}
@@ -0,0 +1,77 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"ailingo/internal/domain"
+	"ailingo/internal/usecase"
+	"ailingo/pkg/apiutil"
+	"ailingo/pkg/auth"
+)
+
+type SyncController struct {
+	l           *slog.Logger
+	userService *auth.UserService
+	syncUseCase domain.SyncUseCase
+}
+
+func NewSyncController(l *slog.Logger, userService *auth.UserService, syncUseCase domain.SyncUseCase) *SyncController {
+	return &SyncController{
+		l:           l,
+		userService: userService,
+		syncUseCase: syncUseCase,
+	}
+}
+
+// Router registers offline sync endpoints. It is meant to be mounted under /me.
+func (c *SyncController) Router(r chi.Router) {
+	r.Post("/sync", c.Sync)
+}
+
+// Sync is an endpoint handler for uploading reviews made offline. It responds with changes since the client's last sync.
+func (c *SyncController) Sync(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	user, err := c.userService.GetUserFromContext(ctx)
+	if err != nil {
+		if errors.Is(err, auth.ErrNoClaims) {
+			apiutil.Err(c.l, w, &apiutil.ApiError{
+				Status: http.StatusUnauthorized,
+				Cause:  err,
+			})
+		} else {
+			apiutil.Err(c.l, w, err)
+		}
+		return
+	}
+
+	var syncData domain.SyncData
+	if err := json.NewDecoder(r.Body).Decode(&syncData); err != nil {
+		apiutil.Err(c.l, w, &apiutil.ApiError{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid request body",
+			Cause:   err,
+		})
+		return
+	}
+
+	result, err := c.syncUseCase.Sync(ctx, user.ID, &syncData)
+	if err != nil {
+		if errors.Is(err, usecase.ErrValidation) {
+			apiutil.Err(c.l, w, &apiutil.ApiError{
+				Status: http.StatusBadRequest,
+				Cause:  err,
+			})
+		} else {
+			apiutil.Err(c.l, w, err)
+		}
+		return
+	}
+
+	apiutil.Json(c.l, w, http.StatusOK, result)
+}
@@ -0,0 +1,102 @@
+package controller
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"ailingo/internal/domain"
+	"ailingo/internal/usecase"
+	"ailingo/pkg/apiutil"
+)
+
+type TagController struct {
+	l          *slog.Logger
+	tagUseCase domain.TagUseCase
+}
+
+func NewTagController(l *slog.Logger, tagUseCase domain.TagUseCase) *TagController {
+	return &TagController{
+		l:          l,
+		tagUseCase: tagUseCase,
+	}
+}
+
+// Router registers tag endpoints. It is meant to be mounted under /tags.
+func (c *TagController) Router(r chi.Router) {
+	r.Get("/", c.Autocomplete)
+	r.Get("/{tag}/study-sets", c.GetStudySets)
+}
+
+// CategoryRouter registers category endpoints. It is meant to be mounted under /categories.
+func (c *TagController) CategoryRouter(r chi.Router) {
+	r.Get("/", c.GetCategories)
+}
+
+// Autocomplete is an endpoint handler for getting the most used tags starting with the given prefix.
+func (c *TagController) Autocomplete(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	limit, err := apiutil.QueryInt(r, "limit", 0)
+	if err != nil {
+		apiutil.Err(c.l, w, &apiutil.ApiError{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid limit",
+		})
+		return
+	}
+
+	tags, err := c.tagUseCase.Autocomplete(ctx, r.URL.Query().Get("prefix"), limit)
+	if err != nil {
+		if errors.Is(err, usecase.ErrValidation) {
+			apiutil.Err(c.l, w, &apiutil.ApiError{
+				Status: http.StatusBadRequest,
+				Cause:  err,
+			})
+		} else {
+			apiutil.Err(c.l, w, err)
+		}
+		return
+	}
+
+	apiutil.Json(c.l, w, http.StatusOK, tags)
+}
+
+// GetStudySets is an endpoint handler for browsing a page of public study sets with the given tag.
+func (c *TagController) GetStudySets(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	filter, limit, err := studySetListQuery(r)
+	if err != nil {
+		apiutil.Err(c.l, w, err)
+		return
+	}
+
+	page, err := c.tagUseCase.GetStudySets(ctx, chi.URLParam(r, "tag"), filter, r.URL.Query().Get("cursor"), limit)
+	if err != nil {
+		if errors.Is(err, usecase.ErrValidation) {
+			apiutil.Err(c.l, w, &apiutil.ApiError{
+				Status: http.StatusBadRequest,
+				Cause:  err,
+			})
+		} else {
+			apiutil.Err(c.l, w, err)
+		}
+		return
+	}
+
+	apiutil.Json(c.l, w, http.StatusOK, page)
+}
+
+// GetCategories is an endpoint handler for getting all the categories with the number of public study sets in them.
+func (c *TagController) GetCategories(w http.ResponseWriter, r *http.Request) {
+	categories, err := c.tagUseCase.GetCategories(r.Context())
+	if err != nil {
+		apiutil.Err(c.l, w, err)
+		return
+	}
+
+	apiutil.Json(c.l, w, http.StatusOK, categories)
+}
@@ -0,0 +1,58 @@
+package domain
+
+import (
+	"context"
+
+	"ailingo/pkg/grader"
+)
+
+// ClozeHint helps the learner guess the blanked out word.
+type ClozeHint struct {
+	FirstLetter string `json:"firstLetter"`
+	Length      int    `json:"length"`
+}
+
+// ClozeExercise represents an example sentence with the definition's phrase blanked out.
+type ClozeExercise struct {
+	DefinitionId  int64     `json:"definitionId"`
+	SentenceIndex int       `json:"sentenceIndex"`
+	Text          string    `json:"text"`
+	Hint          ClozeHint `json:"hint"`
+}
+
+// UnlocatedSentence represents an example sentence in which the phrase could not be found.
+type UnlocatedSentence struct {
+	DefinitionId  int64  `json:"definitionId"`
+	SentenceIndex int    `json:"sentenceIndex"`
+	Sentence      string `json:"sentence"`
+}
+
+// ClozeExercises represents all cloze exercises generated from a study set.
+type ClozeExercises struct {
+	Exercises []*ClozeExercise     `json:"exercises"`
+	Unlocated []*UnlocatedSentence `json:"unlocated"`
+}
+
+type ClozeAnswerData struct {
+	DefinitionId  int64  `json:"definitionId" validate:"required"`
+	SentenceIndex int    `json:"sentenceIndex" validate:"min=0"`
+	Answer        string `json:"answer" validate:"max=256"`
+}
+
+type ClozeCheckData struct {
+	Answers []*ClozeAnswerData `json:"answers" validate:"required,max=500,dive,required"`
+}
+
+type ClozeAnswerResult struct {
+	DefinitionId  int64          `json:"definitionId"`
+	SentenceIndex int            `json:"sentenceIndex"`
+	Result        *grader.Result `json:"result"`
+}
+
+// ClozeUseCase describes methods required by ClozeUseCase implementation.
+type ClozeUseCase interface {
+	// GetExercises generates cloze exercises from example sentences of all definitions in the study set.
+	// Empty user id means an anonymous user.
+	GetExercises(ctx context.Context, userID string, studySetID int64) (*ClozeExercises, error)
+	Check(ctx context.Context, userID string, studySetID int64, checkData *ClozeCheckData) ([]*ClozeAnswerResult, error)
+}
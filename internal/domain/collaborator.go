@@ -0,0 +1,50 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	// CollaboratorRoleEditor means that the collaborator can change the study set and its definitions.
+	CollaboratorRoleEditor = "EDITOR"
+	// CollaboratorRoleViewer means that the collaborator can see the study set even if it is private.
+	CollaboratorRoleViewer = "VIEWER"
+)
+
+// Collaborator represents a user invited to the study set by its author.
+type Collaborator struct {
+	UserId   string     `json:"userId"`
+	Username string     `json:"username"`
+	ImageURL string     `json:"imageUrl"`
+	Role     string     `json:"role"`
+	AddedAt  *time.Time `json:"addedAt"`
+}
+
+type AddCollaboratorData struct {
+	UserId string `json:"userId" validate:"required,max=32"`
+	Role   string `json:"role" validate:"required,oneof=EDITOR VIEWER"`
+}
+
+type UpdateCollaboratorData struct {
+	Role string `json:"role" validate:"required,oneof=EDITOR VIEWER"`
+}
+
+// CollaboratorRepo describes methods required by CollaboratorRepo implementation.
+type CollaboratorRepo interface {
+	GetAll(ctx context.Context, studySetID int64) ([]*Collaborator, error)
+	// GetRole returns the role of the user in the study set or an empty string if the user is not a collaborator.
+	GetRole(ctx context.Context, studySetID int64, userID string) (string, error)
+	Insert(ctx context.Context, studySetID int64, userID string, role string) error
+	UpdateRole(ctx context.Context, studySetID int64, userID string, role string) error
+	Delete(ctx context.Context, studySetID int64, userID string) error
+}
+
+// CollaboratorUseCase describes methods required by CollaboratorUseCase implementation.
+// Only the author can manage collaborators, but collaborators can always leave on their own.
+type CollaboratorUseCase interface {
+	GetAll(ctx context.Context, userID string, studySetID int64) ([]*Collaborator, error)
+	Add(ctx context.Context, userID string, studySetID int64, addData *AddCollaboratorData) error
+	Update(ctx context.Context, userID string, studySetID int64, collaboratorID string, updateData *UpdateCollaboratorData) error
+	Remove(ctx context.Context, userID string, studySetID int64, collaboratorID string) error
+}
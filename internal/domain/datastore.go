@@ -12,4 +12,17 @@ type DataStore interface {
 	GetUserRepo() UserRepo
 	GetStudySessionRepo() StudySessionRepo
 	GetTaskRepo() TaskRepo
+	GetReviewRepo() ReviewRepo
+	GetQuizRepo() QuizRepo
+	GetExamRepo() ExamRepo
+	GetPreferencesRepo() PreferencesRepo
+	GetStreakRepo() StreakRepo
+	GetSyncRepo() SyncRepo
+	GetSkillRepo() SkillRepo
+	GetPlacementRepo() PlacementRepo
+	GetSearchRepo() SearchRepo
+	GetCollaboratorRepo() CollaboratorRepo
+	GetRevisionRepo() RevisionRepo
+	GetTagRepo() TagRepo
+	GetFolderRepo() FolderRepo
 }
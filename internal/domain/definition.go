@@ -1,6 +1,10 @@
 package domain
 
-import "context"
+import (
+	"context"
+
+	"ailingo/pkg/grader"
+)
 
 type Definition struct {
 	Id        int64    `json:"id"`
@@ -30,19 +34,87 @@ type InsertDefinitionData struct {
 
 type UpdateDefinitionData InsertDefinitionData
 
+const (
+	DefinitionOperationCreate = "CREATE"
+	DefinitionOperationUpdate = "UPDATE"
+	DefinitionOperationDelete = "DELETE"
+	// DefinitionOperationMove changes the position of the definition without changing its content.
+	DefinitionOperationMove = "MOVE"
+)
+
+// DefinitionOperation represents a single operation of a batch. Definition id is ignored by create operations.
+// Definition data is required by create and update operations. Position is required by move operations,
+// while definitions created without a position are put last.
+type DefinitionOperation struct {
+	Type         string                `json:"type"`
+	DefinitionId int64                 `json:"definitionId"`
+	Definition   *InsertDefinitionData `json:"definition"`
+	Position     *int                  `json:"position"`
+}
+
+// BatchDefinitionData represents operations applied to definitions of a study set in the given order.
+type BatchDefinitionData struct {
+	Operations []*DefinitionOperation `json:"operations" validate:"required,min=1,max=500"`
+}
+
+// DefinitionOperationError explains why the operation with the given index in the batch is invalid.
+type DefinitionOperationError struct {
+	Index   int    `json:"index"`
+	Message string `json:"message"`
+}
+
+// BatchDefinitionResult represents definitions of the study set after a batch was applied.
+// CreatedIds holds ids of the created definitions in the order of create operations.
+type BatchDefinitionResult struct {
+	CreatedIds  []int64       `json:"createdIds"`
+	Definitions []*Definition `json:"definitions"`
+}
+
+const (
+	// CheckFieldPhrase means that the answer is checked against the definition's phrase.
+	CheckFieldPhrase = "PHRASE"
+	// CheckFieldMeaning means that the answer is checked against the definition's meaning.
+	CheckFieldMeaning = "MEANING"
+)
+
+// CheckAnswerData represents an answer typed by the learner.
+type CheckAnswerData struct {
+	Answer string `json:"answer" validate:"required,max=256"`
+	Field  string `json:"field" validate:"required,oneof=PHRASE MEANING"`
+}
+
 // DefinitionRepo describes methods required by DefinitionRepo implementation.
 type DefinitionRepo interface {
+	// GetAllFor returns definitions of the study set ordered by position.
 	GetAllFor(ctx context.Context, parentStudySetID int64) ([]*DefinitionRow, error)
-	Insert(ctx context.Context, parentStudySetID int64, insertData *InsertDefinitionData) error
+	// ForEach calls the function with each definition of the study set in order without loading all of them at once.
+	ForEach(ctx context.Context, parentStudySetID int64, fn func(definition *DefinitionRow) error) error
+	Get(ctx context.Context, parentStudySetID int64, definitionID int64) (*DefinitionRow, error)
+	// GetSample returns random definitions from public study sets with the given languages, excluding the given study set.
+	GetSample(ctx context.Context, phraseLanguage string, definitionLanguage string, excludedStudySetID int64, limit int) ([]*DefinitionRow, error)
+	// Insert puts the new definition after all the other definitions of the study set.
+	Insert(ctx context.Context, parentStudySetID int64, insertData *InsertDefinitionData) (int64, error)
+	// CopyAll copies all definitions of one study set into another.
+	CopyAll(ctx context.Context, fromStudySetID int64, toStudySetID int64) error
 	Update(ctx context.Context, definitionID int64, updateData *UpdateDefinitionData) error
+	SetPosition(ctx context.Context, definitionID int64, position int) error
 	Delete(ctx context.Context, definitionID int64) error
 }
 
 // DefinitionUseCase describes methods required by DefinitionUseCase implementation.
 type DefinitionUseCase interface {
-	GetAllFor(ctx context.Context, parentStudySetID int64) ([]*Definition, error)
+	// GetAllFor returns definitions of the study set if the user can see it. Empty user id means an anonymous user.
+	GetAllFor(ctx context.Context, userID string, parentStudySetID int64) ([]*Definition, error)
 	Create(ctx context.Context, userID string, parentStudySetID int64, insertData *InsertDefinitionData) error
 	Update(ctx context.Context, userID string, parentStudySetID int64, definitionID int64, updateData *UpdateDefinitionData) error
 	Delete(ctx context.Context, userID string, parentStudySetID int64, definitionID int64) error
+	// Batch applies all the operations in a single transaction. If any of them is invalid, none of them is applied.
+	Batch(ctx context.Context, userID string, parentStudySetID int64, batchData *BatchDefinitionData) (*BatchDefinitionResult, error)
+	// PreviewImport parses the text and validates each row without saving anything.
+	PreviewImport(ctx context.Context, userID string, parentStudySetID int64, importData *ImportDefinitionsData) (*ImportPreview, error)
+	// Import adds definitions from all the rows of the text in a single transaction.
+	Import(ctx context.Context, userID string, parentStudySetID int64, importData *ImportDefinitionsData) (*ImportResult, error)
 	AiFill(ctx context.Context, userID string, parentStudySetID int64) (int64, error)
+	// CheckAnswer grades the answer typed by the learner against the definition.
+	CheckAnswer(ctx context.Context, userID string, parentStudySetID int64, definitionID int64, answerData *CheckAnswerData) (*grader.Result, error)
 }
@@ -0,0 +1,153 @@
+package domain
+
+import (
+	"context"
+	"time"
+
+	"ailingo/pkg/grader"
+)
+
+const (
+	// ExamQuestionMultipleChoice means that the learner is shown a phrase and picks its meaning.
+	ExamQuestionMultipleChoice = "MULTIPLE_CHOICE"
+	// ExamQuestionTyped means that the learner is shown a meaning and types its phrase.
+	ExamQuestionTyped = "TYPED"
+	// ExamQuestionCloze means that the learner fills the blank in an example sentence.
+	ExamQuestionCloze = "CLOZE"
+)
+
+// ExamQuestion represents a single exam question. It never contains the correct answer.
+// Choices are only present in multiple choice questions.
+type ExamQuestion struct {
+	Id      int64    `json:"id"`
+	Type    string   `json:"type"`
+	Prompt  string   `json:"prompt"`
+	Choices []string `json:"choices,omitempty"`
+}
+
+// Exam represents a timed exam generated from a study set.
+type Exam struct {
+	Id               int64           `json:"id"`
+	StudySetId       int64           `json:"studySetId"`
+	TimeLimitSeconds int             `json:"timeLimitSeconds"`
+	StartedAt        *time.Time      `json:"startedAt"`
+	DeadlineAt       *time.Time      `json:"deadlineAt"`
+	SubmittedAt      *time.Time      `json:"submittedAt"`
+	Questions        []*ExamQuestion `json:"questions"`
+}
+
+// ExamRow represents data stored in exam table.
+type ExamRow struct {
+	Id               int64
+	UserId           string
+	StudySetId       int64
+	TimeLimitSeconds int
+	Score            *int
+	Total            int
+	TimedOut         bool
+	StartedAt        *time.Time
+	DeadlineAt       *time.Time
+	SubmittedAt      *time.Time
+}
+
+// ExamQuestionRow represents data stored in exam question table.
+// Answer is the expected answer, Response is what the learner submitted.
+type ExamQuestionRow struct {
+	Id           int64
+	DefinitionId int64
+	Type         string
+	Prompt       string
+	Choices      []string
+	Answer       string
+	Response     *string
+	Correct      *bool
+}
+
+func (r *ExamQuestionRow) Populate() *ExamQuestion {
+	return &ExamQuestion{
+		Id:      r.Id,
+		Type:    r.Type,
+		Prompt:  r.Prompt,
+		Choices: r.Choices,
+	}
+}
+
+type CreateExamData struct {
+	QuestionCount    int      `json:"questionCount" validate:"min=0,max=50"`
+	TimeLimitSeconds int      `json:"timeLimitSeconds" validate:"omitempty,min=60,max=7200"`
+	QuestionTypes    []string `json:"questionTypes" validate:"max=3,dive,oneof=MULTIPLE_CHOICE TYPED CLOZE"`
+}
+
+// ExamAnswerData represents an answer to a single question.
+// ChoiceIndex is used by multiple choice questions, Answer by all the others.
+type ExamAnswerData struct {
+	QuestionId  int64  `json:"questionId" validate:"required"`
+	ChoiceIndex *int   `json:"choiceIndex" validate:"omitempty,min=0"`
+	Answer      string `json:"answer" validate:"max=256"`
+}
+
+type SubmitExamData struct {
+	Answers []*ExamAnswerData `json:"answers" validate:"required,max=50,dive,required"`
+}
+
+// ExamQuestionFeedback represents the outcome of a single question.
+// Response is nil if the question was left unanswered, Grading is only present in graded typed and cloze questions.
+// Graded is false for responses submitted after the deadline, which are kept but not scored.
+type ExamQuestionFeedback struct {
+	QuestionId   int64          `json:"questionId"`
+	DefinitionId int64          `json:"definitionId"`
+	Type         string         `json:"type"`
+	Prompt       string         `json:"prompt"`
+	Expected     string         `json:"expected"`
+	Response     *string        `json:"response"`
+	Correct      bool           `json:"correct"`
+	Graded       bool           `json:"graded"`
+	Grading      *grader.Result `json:"grading,omitempty"`
+}
+
+// ExamReport represents a scored exam.
+// TimedOut means that the exam was submitted after the deadline and its answers were not graded.
+type ExamReport struct {
+	ExamId      int64                   `json:"examId"`
+	StudySetId  int64                   `json:"studySetId"`
+	Score       int                     `json:"score"`
+	Total       int                     `json:"total"`
+	TimedOut    bool                    `json:"timedOut"`
+	StartedAt   *time.Time              `json:"startedAt"`
+	SubmittedAt *time.Time              `json:"submittedAt"`
+	Questions   []*ExamQuestionFeedback `json:"questions"`
+}
+
+// ExamAttempt represents a single exam attempt. Score is nil until the exam is submitted.
+type ExamAttempt struct {
+	Id          int64      `json:"id"`
+	Score       *int       `json:"score"`
+	Total       int        `json:"total"`
+	TimedOut    bool       `json:"timedOut"`
+	StartedAt   *time.Time `json:"startedAt"`
+	SubmittedAt *time.Time `json:"submittedAt"`
+}
+
+// ExamRepo describes methods required by ExamRepo implementation.
+type ExamRepo interface {
+	Get(ctx context.Context, examID int64) (*ExamRow, error)
+	GetQuestions(ctx context.Context, examID int64) ([]*ExamQuestionRow, error)
+	GetAttempts(ctx context.Context, userID string, studySetID int64) ([]*ExamAttempt, error)
+	Insert(ctx context.Context, exam *ExamRow) (int64, error)
+	InsertQuestion(ctx context.Context, examID int64, position int, question *ExamQuestionRow) (int64, error)
+	// SaveResponse stores the response with its outcome. Correct is nil if the response was not graded.
+	SaveResponse(ctx context.Context, questionID int64, response *string, correct *bool) error
+	// Submit marks the exam as submitted. It returns false if the exam has already been submitted.
+	Submit(ctx context.Context, examID int64, score int, timedOut bool, submittedAt time.Time) (bool, error)
+}
+
+// ExamUseCase describes methods required by ExamUseCase implementation.
+type ExamUseCase interface {
+	Create(ctx context.Context, userID string, studySetID int64, createData *CreateExamData) (*Exam, error)
+	Get(ctx context.Context, userID string, examID int64) (*Exam, error)
+	// GetAttempts returns all exam attempts of the user for the study set, the most recent first.
+	GetAttempts(ctx context.Context, userID string, studySetID int64) ([]*ExamAttempt, error)
+	// Submit scores the exam. Answers submitted after the deadline are discarded.
+	Submit(ctx context.Context, userID string, examID int64, submitData *SubmitExamData) (*ExamReport, error)
+	GetReport(ctx context.Context, userID string, examID int64) (*ExamReport, error)
+}
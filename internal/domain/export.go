@@ -0,0 +1,47 @@
+package domain
+
+import (
+	"context"
+	"io"
+	"time"
+)
+
+const (
+	ExportFormatCSV  = "CSV"
+	ExportFormatTSV  = "TSV"
+	ExportFormatJSON = "JSON"
+	// ExportFormatHTML is a printable page with a table of definitions.
+	ExportFormatHTML = "HTML"
+)
+
+// ExportBundleVersion is the version of the JSON bundle written by exports.
+// It is increased whenever the bundle changes in a way older importers cannot read.
+const ExportBundleVersion = 1
+
+// ExportedStudySet represents metadata of an exported study set.
+type ExportedStudySet struct {
+	Name               string   `json:"name"`
+	Description        string   `json:"description"`
+	PhraseLanguage     string   `json:"phraseLanguage"`
+	DefinitionLanguage string   `json:"definitionLanguage"`
+	Icon               string   `json:"icon"`
+	Color              string   `json:"color"`
+	CefrLevel          *string  `json:"cefrLevel"`
+	Category           *string  `json:"category"`
+	Tags               []string `json:"tags"`
+}
+
+// ExportBundle represents a study set exported as JSON. Its definitions can be imported into any study set.
+type ExportBundle struct {
+	Version     int                     `json:"version"`
+	ExportedAt  time.Time               `json:"exportedAt"`
+	StudySet    *ExportedStudySet       `json:"studySet"`
+	Definitions []*InsertDefinitionData `json:"definitions"`
+}
+
+// ExportUseCase describes methods required by ExportUseCase implementation.
+type ExportUseCase interface {
+	// Export writes the study set in the given format. Definitions are streamed, so that large study sets are not held in memory.
+	// Nothing is written if the user cannot see the study set. Empty user id means an anonymous user.
+	Export(ctx context.Context, userID string, studySetID int64, format string, w io.Writer) error
+}
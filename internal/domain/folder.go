@@ -0,0 +1,120 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// Folder represents a folder of the user with its subfolders.
+// Folders can hold study sets of the user as well as study sets of other users.
+type Folder struct {
+	Id            int64      `json:"id"`
+	ParentId      *int64     `json:"parentId"`
+	Name          string     `json:"name"`
+	Position      int        `json:"position"`
+	StudySetCount int64      `json:"studySetCount"`
+	Children      []*Folder  `json:"children"`
+	CreatedAt     *time.Time `json:"createdAt"`
+}
+
+// FolderRow represents data stored in folder table together with the number of study sets in the folder.
+type FolderRow struct {
+	Id            int64
+	UserId        string
+	ParentId      *int64
+	Name          string
+	Position      int
+	StudySetCount int64
+	CreatedAt     *time.Time
+}
+
+func (r *FolderRow) Populate() *Folder {
+	return &Folder{
+		Id:            r.Id,
+		ParentId:      r.ParentId,
+		Name:          r.Name,
+		Position:      r.Position,
+		StudySetCount: r.StudySetCount,
+		Children:      make([]*Folder, 0),
+		CreatedAt:     r.CreatedAt,
+	}
+}
+
+// FolderDetails represents a folder with its direct subfolders and the study sets it holds in order.
+// Study sets the user cannot see anymore are left out.
+type FolderDetails struct {
+	Folder
+	StudySets []*StudySetWithAuthor `json:"studySets"`
+}
+
+// FolderCard represents a definition fed into a folder study session, together with the study set it comes from.
+type FolderCard struct {
+	StudySetId int64       `json:"studySetId"`
+	Definition *Definition `json:"definition"`
+}
+
+// FolderStudySession represents a study session started for a whole folder.
+// Cards contain definitions of all study sets in the folder and its subfolders.
+type FolderStudySession struct {
+	StudySessionId int64         `json:"studySessionId"`
+	Cards          []*FolderCard `json:"cards"`
+}
+
+type InsertFolderData struct {
+	Name     string `json:"name" validate:"required,max=128"`
+	ParentId *int64 `json:"parentId"`
+}
+
+// UpdateFolderData renames and moves the folder. Nil parent id moves the folder to the top level.
+// Nil position keeps the folder in place if the parent does not change and puts it last otherwise.
+type UpdateFolderData struct {
+	Name     string `json:"name" validate:"required,max=128"`
+	ParentId *int64 `json:"parentId"`
+	Position *int   `json:"position" validate:"omitempty,min=0"`
+}
+
+type AddFolderStudySetData struct {
+	StudySetId int64 `json:"studySetId" validate:"required"`
+}
+
+type MoveFolderStudySetData struct {
+	Position int `json:"position" validate:"min=0"`
+}
+
+type StartFolderStudySessionData struct {
+	Mode      string `json:"mode" validate:"required,oneof=FLASHCARDS WRITE QUIZ CLOZE"`
+	Direction string `json:"direction" validate:"required,oneof=PHRASE_TO_MEANING MEANING_TO_PHRASE"`
+}
+
+// FolderRepo describes methods required by FolderRepo implementation.
+type FolderRepo interface {
+	// GetAll returns all folders of the user ordered by position.
+	GetAll(ctx context.Context, userID string) ([]*FolderRow, error)
+	Get(ctx context.Context, folderID int64) (*FolderRow, error)
+	Insert(ctx context.Context, userID string, parentID *int64, name string, position int) (int64, error)
+	Update(ctx context.Context, folderID int64, parentID *int64, name string, position int) error
+	SetPosition(ctx context.Context, folderID int64, position int) error
+	// Delete deletes the folder along with its study set entries, but not its subfolders.
+	Delete(ctx context.Context, folderID int64) error
+	// GetStudySetIds returns ids of the study sets in the folder ordered by position.
+	GetStudySetIds(ctx context.Context, folderID int64) ([]int64, error)
+	AddStudySet(ctx context.Context, folderID int64, studySetID int64, position int) error
+	SetStudySetPosition(ctx context.Context, folderID int64, studySetID int64, position int) error
+	RemoveStudySet(ctx context.Context, folderID int64, studySetID int64) error
+}
+
+// FolderUseCase describes methods required by FolderUseCase implementation.
+type FolderUseCase interface {
+	// GetAll returns the folder tree of the user.
+	GetAll(ctx context.Context, userID string) ([]*Folder, error)
+	Get(ctx context.Context, userID string, folderID int64) (*FolderDetails, error)
+	Create(ctx context.Context, userID string, insertData *InsertFolderData) (int64, error)
+	Update(ctx context.Context, userID string, folderID int64, updateData *UpdateFolderData) error
+	// Delete deletes the folder with all its subfolders. Study sets themselves are kept.
+	Delete(ctx context.Context, userID string, folderID int64) error
+	AddStudySet(ctx context.Context, userID string, folderID int64, addData *AddFolderStudySetData) error
+	MoveStudySet(ctx context.Context, userID string, folderID int64, studySetID int64, moveData *MoveFolderStudySetData) error
+	RemoveStudySet(ctx context.Context, userID string, folderID int64, studySetID int64) error
+	// Study starts a single study session fed with definitions of all study sets in the folder and its subfolders.
+	Study(ctx context.Context, userID string, folderID int64, startData *StartFolderStudySessionData) (*FolderStudySession, error)
+}
@@ -0,0 +1,38 @@
+package domain
+
+// ImportDefinitionsData represents text exported from another tool with one definition per row.
+// Each row holds the phrase, the meaning and optionally example sentences, one per remaining term.
+// JSON format means an export bundle, whose definitions are used as rows. Empty format means that the format is detected from the text.
+type ImportDefinitionsData struct {
+	Text          string `json:"text" validate:"required,max=1000000"`
+	Format        string `json:"format" validate:"omitempty,oneof=AUTO CSV TSV CUSTOM JSON"`
+	TermSeparator string `json:"termSeparator" validate:"max=16"`
+	RowSeparator  string `json:"rowSeparator" validate:"max=16"`
+	// SkipHeader leaves out the first row, which holds column names in many spreadsheets.
+	SkipHeader bool `json:"skipHeader"`
+	// SkipInvalid imports valid rows even if some rows are invalid. Otherwise nothing is imported.
+	SkipInvalid bool `json:"skipInvalid"`
+}
+
+// ImportRow represents a single parsed row. Errors are empty if the definition can be imported.
+type ImportRow struct {
+	Number     int                   `json:"number"`
+	Definition *InsertDefinitionData `json:"definition"`
+	Errors     []string              `json:"errors"`
+}
+
+// ImportPreview represents the parsed text together with the format and separators that were used.
+type ImportPreview struct {
+	Format        string       `json:"format"`
+	TermSeparator string       `json:"termSeparator"`
+	RowSeparator  string       `json:"rowSeparator"`
+	Rows          []*ImportRow `json:"rows"`
+	ValidRows     int          `json:"validRows"`
+	InvalidRows   int          `json:"invalidRows"`
+}
+
+// ImportResult represents the imported rows. CreatedIds holds ids of the created definitions in the order of valid rows.
+type ImportResult struct {
+	ImportPreview
+	CreatedIds []int64 `json:"createdIds"`
+}
@@ -0,0 +1,89 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// PlacementQuestion represents a single placement test question. It never contains the correct answer.
+type PlacementQuestion struct {
+	Id      int64    `json:"id"`
+	Word    string   `json:"word"`
+	Choices []string `json:"choices"`
+}
+
+// PlacementResult represents the estimated CEFR level.
+// Ability and StdErr describe the underlying estimate on the logit scale.
+type PlacementResult struct {
+	Level      string     `json:"level"`
+	Ability    float64    `json:"ability"`
+	StdErr     float64    `json:"stdErr"`
+	FinishedAt *time.Time `json:"finishedAt"`
+}
+
+// PlacementStep represents the current state of a placement test.
+// Question is the next question to answer and it is nil once the test is finished.
+type PlacementStep struct {
+	TestId   int64              `json:"testId"`
+	Answered int                `json:"answered"`
+	Question *PlacementQuestion `json:"question"`
+	Result   *PlacementResult   `json:"result"`
+}
+
+// PlacementTestRow represents data stored in placement test table.
+// Result columns are nil until the test is finished.
+type PlacementTestRow struct {
+	Id         int64
+	UserId     string
+	Ability    *float64
+	StdErr     *float64
+	Level      *string
+	StartedAt  *time.Time
+	FinishedAt *time.Time
+}
+
+// PlacementQuestionRow represents data stored in placement question table.
+// Word and level are copied from the word bank, so that results do not change when the bank is updated.
+type PlacementQuestionRow struct {
+	Id           int64
+	ItemId       int
+	Word         string
+	Level        string
+	Choices      []string
+	CorrectIndex int
+	AnswerIndex  *int
+}
+
+func (r *PlacementQuestionRow) Populate() *PlacementQuestion {
+	return &PlacementQuestion{
+		Id:      r.Id,
+		Word:    r.Word,
+		Choices: r.Choices,
+	}
+}
+
+type PlacementAnswerData struct {
+	QuestionId  int64 `json:"questionId" validate:"required"`
+	ChoiceIndex int   `json:"choiceIndex" validate:"min=0"`
+}
+
+// PlacementRepo describes methods required by PlacementRepo implementation.
+type PlacementRepo interface {
+	Get(ctx context.Context, testID int64) (*PlacementTestRow, error)
+	GetQuestions(ctx context.Context, testID int64) ([]*PlacementQuestionRow, error)
+	Insert(ctx context.Context, userID string) (int64, error)
+	InsertQuestion(ctx context.Context, testID int64, position int, question *PlacementQuestionRow) (int64, error)
+	SaveAnswer(ctx context.Context, questionID int64, answerIndex int) error
+	Finish(ctx context.Context, testID int64, result *PlacementResult) error
+}
+
+// PlacementUseCase describes methods required by PlacementUseCase implementation.
+type PlacementUseCase interface {
+	Start(ctx context.Context, userID string) (*PlacementStep, error)
+	Get(ctx context.Context, userID string, testID int64) (*PlacementStep, error)
+	// Answer answers the current question and either asks the next one or finishes the test.
+	// The estimated level is saved in the user's preferences once the test is finished.
+	Answer(ctx context.Context, userID string, testID int64, answerData *PlacementAnswerData) (*PlacementStep, error)
+	// GetRecommended returns study sets matching the level estimated by the placement test.
+	GetRecommended(ctx context.Context, userID string) ([]*StudySetWithAuthor, error)
+}
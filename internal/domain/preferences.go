@@ -0,0 +1,26 @@
+package domain
+
+import "context"
+
+const (
+	// DailyGoalReviews means that the daily goal is measured in reviewed definitions.
+	DailyGoalReviews = "REVIEWS"
+	// DailyGoalMinutes means that the daily goal is measured in minutes spent on answering.
+	DailyGoalMinutes = "MINUTES"
+)
+
+// UserPreferences represents learner's settings.
+// CefrLevel is nil until the user takes the placement test.
+type UserPreferences struct {
+	Timezone        string  `json:"timezone"`
+	DailyGoalType   string  `json:"dailyGoalType"`
+	DailyGoalTarget int     `json:"dailyGoalTarget"`
+	CefrLevel       *string `json:"cefrLevel"`
+}
+
+// PreferencesRepo describes methods required by PreferencesRepo implementation.
+type PreferencesRepo interface {
+	// Get returns preferences of the given user or nil if the user has never saved them.
+	Get(ctx context.Context, userID string) (*UserPreferences, error)
+	Upsert(ctx context.Context, userID string, preferences *UserPreferences) error
+}
@@ -3,8 +3,10 @@ package domain
 import "context"
 
 type ProfileUseCase interface {
-	GetCreatedStudySets(ctx context.Context, userID string) ([]*StudySet, error)
-	GetStarredStudySets(ctx context.Context, userID string) ([]*StudySetWithAuthor, error)
+	GetCreatedStudySets(ctx context.Context, userID string, filter *StudySetFilter, cursor string, limit int) (*StudySetPage, error)
+	GetStarredStudySets(ctx context.Context, userID string, filter *StudySetFilter, cursor string, limit int) (*StudySetPage, error)
+	// GetSharedStudySets returns study sets the user has been invited to as a collaborator.
+	GetSharedStudySets(ctx context.Context, userID string, filter *StudySetFilter, cursor string, limit int) (*StudySetPage, error)
 	StarStudySet(ctx context.Context, userID string, studySetID int64) error
 	InstarStudySet(ctx context.Context, userID string, studySetID int64) error
 }
@@ -0,0 +1,112 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	// QuizDirectionPhraseToMeaning means that the learner is shown a phrase and picks its meaning.
+	QuizDirectionPhraseToMeaning = "PHRASE_TO_MEANING"
+	// QuizDirectionMeaningToPhrase means that the learner is shown a meaning and picks its phrase.
+	QuizDirectionMeaningToPhrase = "MEANING_TO_PHRASE"
+)
+
+// QuizQuestion represents a multiple choice question. It never contains the correct answer.
+type QuizQuestion struct {
+	Id           int64    `json:"id"`
+	DefinitionId int64    `json:"definitionId"`
+	Prompt       string   `json:"prompt"`
+	Choices      []string `json:"choices"`
+}
+
+// Quiz represents a multiple choice quiz generated from a study set.
+type Quiz struct {
+	Id          int64           `json:"id"`
+	StudySetId  int64           `json:"studySetId"`
+	Direction   string          `json:"direction"`
+	Questions   []*QuizQuestion `json:"questions"`
+	CreatedAt   *time.Time      `json:"createdAt"`
+	SubmittedAt *time.Time      `json:"submittedAt"`
+}
+
+// QuizRow represents data stored in quiz table.
+type QuizRow struct {
+	Id          int64
+	UserId      string
+	StudySetId  int64
+	Direction   string
+	Score       *int
+	CreatedAt   *time.Time
+	SubmittedAt *time.Time
+}
+
+// QuizQuestionRow represents data stored in quiz question table.
+// CorrectIndex and AnswerIndex point at elements of Choices.
+type QuizQuestionRow struct {
+	Id           int64
+	DefinitionId int64
+	Prompt       string
+	Choices      []string
+	CorrectIndex int
+	AnswerIndex  *int
+}
+
+func (r *QuizQuestionRow) Populate() *QuizQuestion {
+	return &QuizQuestion{
+		Id:           r.Id,
+		DefinitionId: r.DefinitionId,
+		Prompt:       r.Prompt,
+		Choices:      r.Choices,
+	}
+}
+
+type CreateQuizData struct {
+	Direction     string `json:"direction" validate:"required,oneof=PHRASE_TO_MEANING MEANING_TO_PHRASE"`
+	QuestionCount int    `json:"questionCount" validate:"min=0,max=50"`
+}
+
+type QuizAnswerData struct {
+	QuestionId  int64 `json:"questionId" validate:"required"`
+	ChoiceIndex int   `json:"choiceIndex" validate:"min=0"`
+}
+
+type SubmitQuizData struct {
+	Answers []*QuizAnswerData `json:"answers" validate:"required,dive,required"`
+}
+
+// QuizQuestionResult represents the outcome of a single question.
+// ChoiceIndex is nil if the question was left unanswered.
+type QuizQuestionResult struct {
+	QuestionId   int64 `json:"questionId"`
+	ChoiceIndex  *int  `json:"choiceIndex"`
+	CorrectIndex int   `json:"correctIndex"`
+	Correct      bool  `json:"correct"`
+}
+
+// QuizResult represents a scored quiz.
+type QuizResult struct {
+	QuizId    int64                 `json:"quizId"`
+	Score     int                   `json:"score"`
+	Total     int                   `json:"total"`
+	Questions []*QuizQuestionResult `json:"questions"`
+}
+
+// QuizRepo describes methods required by QuizRepo implementation.
+type QuizRepo interface {
+	Get(ctx context.Context, quizID int64) (*QuizRow, error)
+	GetQuestions(ctx context.Context, quizID int64) ([]*QuizQuestionRow, error)
+	Insert(ctx context.Context, userID string, studySetID int64, direction string) (int64, error)
+	InsertQuestion(ctx context.Context, quizID int64, position int, question *QuizQuestionRow) (int64, error)
+	SaveAnswer(ctx context.Context, questionID int64, choiceIndex int) error
+	// Submit marks the quiz as submitted. It returns false if the quiz has already been submitted.
+	Submit(ctx context.Context, quizID int64, score int) (bool, error)
+}
+
+// QuizUseCase describes methods required by QuizUseCase implementation.
+type QuizUseCase interface {
+	Create(ctx context.Context, userID string, studySetID int64, createData *CreateQuizData) (*Quiz, error)
+	Get(ctx context.Context, userID string, quizID int64) (*Quiz, error)
+	// Submit scores the quiz and feeds the answers into the user's review history.
+	Submit(ctx context.Context, userID string, quizID int64, submitData *SubmitQuizData) (*QuizResult, error)
+}
@@ -0,0 +1,146 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// ReviewState represents spaced repetition scheduling state of a definition for a specific user.
+type ReviewState struct {
+	DefinitionId   int64      `json:"definitionId"`
+	Ease           float64    `json:"ease"`
+	IntervalDays   int        `json:"intervalDays"`
+	Repetitions    int        `json:"repetitions"`
+	Lapses         int        `json:"lapses"`
+	DueAt          *time.Time `json:"dueAt"`
+	LastReviewedAt *time.Time `json:"lastReviewedAt"`
+}
+
+// ReviewCard represents a definition that should be studied next.
+// State is nil if the definition has never been reviewed by the user.
+type ReviewCard struct {
+	Definition Definition   `json:"definition"`
+	State      *ReviewState `json:"state"`
+}
+
+// QueueCard represents a definition in the cross study set review queue.
+// State is nil if the definition has never been reviewed by the user.
+type QueueCard struct {
+	Definition Definition         `json:"definition"`
+	State      *ReviewState       `json:"state"`
+	StudySet   StudySetWithAuthor `json:"studySet"`
+}
+
+// DueQueueParams configures the cross study set review queue.
+// NewLimit and ReviewLimit are quotas of never reviewed and due definitions respectively.
+// NewLimit is nil if the default quota should be used, zero leaves new definitions out.
+type DueQueueParams struct {
+	Limit       int
+	NewLimit    *int
+	ReviewLimit int
+	Interleave  bool
+}
+
+// ReviewGradeData represents a grade given by the user to their recall of a definition.
+// Grade uses SM-2 scale, where 0 means complete blackout and 5 means perfect response.
+type ReviewGradeData struct {
+	DefinitionId   int64      `json:"definitionId" validate:"required"`
+	Grade          int        `json:"grade" validate:"min=0,max=5"`
+	ResponseTimeMs int        `json:"responseTimeMs" validate:"min=0"`
+	AnsweredAt     *time.Time `json:"answeredAt" validate:"required"`
+}
+
+// ReviewBatchData represents a batch of graded answers submitted by the user.
+type ReviewBatchData struct {
+	Reviews []*ReviewGradeData `json:"reviews" validate:"required,min=1,max=500,dive,required"`
+}
+
+// WeakDefinition represents a definition the user keeps failing.
+// It extends the regular definition listing shape with the data needed to review it.
+// Accuracy is nil if the definition has never been answered.
+type WeakDefinition struct {
+	Definition
+	StudySetId int64    `json:"studySetId"`
+	Lapses     int      `json:"lapses"`
+	Accuracy   *float64 `json:"accuracy"`
+}
+
+// WeakDefinitionCriteria decides which definitions are weak.
+// A definition is weak if it has not matured yet and it either lapsed too often or is answered correctly too rarely.
+type WeakDefinitionCriteria struct {
+	MaxIntervalDays int
+	MinLapses       int
+	MaxAccuracy     float64
+	// MinAnswers is the number of answers needed before accuracy is taken into account.
+	MinAnswers int
+}
+
+// ReviewLogEntry represents a single graded answer stored in the review log.
+// Entries are never updated nor deleted, so they can be used to recompute scheduling state.
+type ReviewLogEntry struct {
+	Id             int64     `json:"id"`
+	DefinitionId   int64     `json:"definitionId"`
+	StudySetId     int64     `json:"studySetId"`
+	Grade          int       `json:"grade"`
+	ResponseTimeMs int       `json:"responseTimeMs"`
+	AnsweredAt     time.Time `json:"answeredAt"`
+}
+
+// DailyAccuracy represents answers given on a single day in the user's timezone.
+type DailyAccuracy struct {
+	Date     string  `json:"date"`
+	Reviews  int     `json:"reviews"`
+	Correct  int     `json:"correct"`
+	Accuracy float64 `json:"accuracy"`
+}
+
+// StudySetProgress represents learner's progress on a study set.
+// Definitions are new if they have never been reviewed, lapsed if they were forgotten after being learned,
+// mature once their review interval is long enough and learning otherwise.
+type StudySetProgress struct {
+	Total                    int              `json:"total"`
+	New                      int              `json:"new"`
+	Learning                 int              `json:"learning"`
+	Mature                   int              `json:"mature"`
+	Lapsed                   int              `json:"lapsed"`
+	Mastery                  float64          `json:"mastery"`
+	Accuracy                 []*DailyAccuracy `json:"accuracy"`
+	EstimatedReviewsToFinish int              `json:"estimatedReviewsToFinish"`
+	EstimatedSecondsToFinish int              `json:"estimatedSecondsToFinish"`
+}
+
+// ReviewRepo describes methods required by ReviewRepo implementation.
+type ReviewRepo interface {
+	GetStatesFor(ctx context.Context, userID string, studySetID int64) ([]*ReviewState, error)
+	// GetDue returns definitions due at the given time across all study sets, the most overdue first.
+	GetDue(ctx context.Context, userID string, now time.Time, limit int) ([]*QueueCard, error)
+	// GetNew returns never reviewed definitions from study sets the user has studied, the most recently studied first.
+	GetNew(ctx context.Context, userID string, limit int) ([]*QueueCard, error)
+	UpsertState(ctx context.Context, userID string, studySetID int64, state *ReviewState) error
+	InsertLog(ctx context.Context, userID string, studySetID int64, gradeData *ReviewGradeData) error
+	// InsertEvent appends a review made by an offline client to the log.
+	// It returns false if an event with the same id has already been stored.
+	InsertEvent(ctx context.Context, userID string, studySetID int64, eventID string, gradeData *ReviewGradeData) (bool, error)
+	// GetDefinitionLog returns all review log entries of the definition in the order they should be replayed.
+	GetDefinitionLog(ctx context.Context, userID string, definitionID int64) ([]*ReviewLogEntry, error)
+	// GetWeak returns weak definitions across all study sets, the weakest first.
+	GetWeak(ctx context.Context, userID string, criteria *WeakDefinitionCriteria, limit int) ([]*WeakDefinition, error)
+	// GetLogFor returns review log entries for the given study set answered since the given time, oldest first.
+	GetLogFor(ctx context.Context, userID string, studySetID int64, since time.Time) ([]*ReviewLogEntry, error)
+}
+
+// ReviewUseCase describes methods required by ReviewUseCase implementation.
+type ReviewUseCase interface {
+	// GetNext returns definitions from the given study set that should be studied next.
+	// Due definitions come first (the most overdue first) followed by definitions that have never been reviewed.
+	GetNext(ctx context.Context, userID string, studySetID int64, limit int) ([]*ReviewCard, error)
+	// GetDueQueue returns a single review queue built from all study sets the user has studied.
+	GetDueQueue(ctx context.Context, userID string, params *DueQueueParams) ([]*QueueCard, error)
+	GetProgress(ctx context.Context, userID string, studySetID int64) (*StudySetProgress, error)
+	// GetWeakDefinitions returns a virtual study set made of definitions the user keeps failing across all study sets.
+	// Definitions leave the set once they mature.
+	GetWeakDefinitions(ctx context.Context, userID string, limit int) ([]*WeakDefinition, error)
+	// Grade appends the graded answers to the review log and updates scheduling state of the graded definitions.
+	// Answers are applied in the order they were given.
+	Grade(ctx context.Context, userID string, studySetID int64, batchData *ReviewBatchData) error
+}
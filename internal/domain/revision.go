@@ -0,0 +1,116 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+const (
+	// RevisionTargetStudySet means that the revision changed fields of the study set itself.
+	RevisionTargetStudySet = "STUDY_SET"
+	// RevisionTargetDefinition means that the revision created, changed or deleted a definition.
+	RevisionTargetDefinition = "DEFINITION"
+)
+
+const (
+	RevisionActionCreate = "CREATE"
+	RevisionActionUpdate = "UPDATE"
+	RevisionActionDelete = "DELETE"
+)
+
+// StudySetSnapshot represents versioned fields of a study set.
+// Visibility is not versioned, as it controls access rather than content.
+type StudySetSnapshot struct {
+	Name               string   `json:"name"`
+	Description        string   `json:"description"`
+	PhraseLanguage     string   `json:"phraseLanguage"`
+	DefinitionLanguage string   `json:"definitionLanguage"`
+	Icon               string   `json:"icon"`
+	Color              string   `json:"color"`
+	CefrLevel          *string  `json:"cefrLevel"`
+	Category           *string  `json:"category"`
+	Tags               []string `json:"tags"`
+}
+
+// DefinitionSnapshot represents versioned fields of a definition.
+type DefinitionSnapshot struct {
+	Phrase    string   `json:"phrase"`
+	Meaning   string   `json:"meaning"`
+	Sentences []string `json:"sentences"`
+}
+
+// RevisionChange describes a single change of a study set or one of its definitions.
+// Only the snapshots of the target are set. Nil before snapshot means a creation and nil after snapshot means a deletion.
+type RevisionChange struct {
+	Target           string
+	DefinitionId     *int64
+	StudySetBefore   *StudySetSnapshot
+	StudySetAfter    *StudySetSnapshot
+	DefinitionBefore *DefinitionSnapshot
+	DefinitionAfter  *DefinitionSnapshot
+}
+
+// RevisionRow represents data stored in revision table.
+type RevisionRow struct {
+	RevisionChange
+	Id         int64
+	StudySetId int64
+	Author     Author
+	CreatedAt  *time.Time
+}
+
+// FieldChange represents a change of a single field. Before is nil for creations and after is nil for deletions.
+type FieldChange struct {
+	Field  string `json:"field"`
+	Before any    `json:"before"`
+	After  any    `json:"after"`
+}
+
+// Revision represents a change made to a study set with a field level diff.
+type Revision struct {
+	Id           int64          `json:"id"`
+	Author       Author         `json:"author"`
+	Target       string         `json:"target"`
+	DefinitionId *int64         `json:"definitionId"`
+	Action       string         `json:"action"`
+	Changes      []*FieldChange `json:"changes"`
+	CreatedAt    *time.Time     `json:"createdAt"`
+}
+
+// RevisionPage represents a single page of the revision history.
+// NextCursor is nil if there are no more revisions.
+type RevisionPage struct {
+	Revisions  []*Revision `json:"revisions"`
+	NextCursor *int64      `json:"nextCursor"`
+}
+
+// StudySetAtRevision represents the study set as it was right after the revision.
+type StudySetAtRevision struct {
+	Revision    *Revision         `json:"revision"`
+	StudySet    *StudySetSnapshot `json:"studySet"`
+	Definitions []*Definition     `json:"definitions"`
+}
+
+// RevisionRepo describes methods required by RevisionRepo implementation.
+type RevisionRepo interface {
+	// GetPage returns revisions of the study set older than the cursor, the most recent first.
+	GetPage(ctx context.Context, studySetID int64, cursor int64, limit int) ([]*RevisionRow, error)
+	Get(ctx context.Context, studySetID int64, revisionID int64) (*RevisionRow, error)
+	// GetNewerThan returns all revisions of the study set made after the given one, the most recent first.
+	GetNewerThan(ctx context.Context, studySetID int64, revisionID int64) ([]*RevisionRow, error)
+	Insert(ctx context.Context, studySetID int64, authorID string, change *RevisionChange) error
+}
+
+// RevisionUseCase describes methods required by RevisionUseCase implementation.
+// Only users who can edit the study set can see its history.
+type RevisionUseCase interface {
+	// GetPage returns revisions of the study set, the most recent first.
+	// Cursor is the id of the last revision from the previous page or 0 for the first page.
+	GetPage(ctx context.Context, userID string, studySetID int64, cursor int64, limit int) (*RevisionPage, error)
+	GetStudySetAt(ctx context.Context, userID string, studySetID int64, revisionID int64) (*StudySetAtRevision, error)
+	// Revert brings the study set and all its definitions back to the state right after the revision.
+	// Reverting is recorded as new revisions, so that it can be reverted too.
+	Revert(ctx context.Context, userID string, studySetID int64, revisionID int64) error
+	// RevertDefinition brings a single definition back to the state right after the revision.
+	RevertDefinition(ctx context.Context, userID string, studySetID int64, revisionID int64, definitionID int64) error
+}
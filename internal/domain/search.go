@@ -0,0 +1,60 @@
+package domain
+
+import (
+	"context"
+
+	"ailingo/pkg/highlight"
+)
+
+// StudySetHit represents a study set whose name or description matches the search query.
+type StudySetHit struct {
+	StudySetWithAuthor
+	Score float64
+}
+
+// DefinitionHit represents a definition whose phrase or meaning matches the search query.
+type DefinitionHit struct {
+	Id         int64
+	StudySetId int64
+	Phrase     string
+	Meaning    string
+	Score      float64
+}
+
+// SearchDefinition represents a matching definition with the query words highlighted.
+type SearchDefinition struct {
+	Id      int64            `json:"id"`
+	Phrase  []highlight.Part `json:"phrase"`
+	Meaning []highlight.Part `json:"meaning"`
+}
+
+// SearchResult represents a study set matching the search query together with its matching definitions.
+// Description is a highlighted snippet, Definitions contains only the best matches out of MatchedDefinitions.
+type SearchResult struct {
+	StudySet           *StudySetWithAuthor `json:"studySet"`
+	Score              float64             `json:"score"`
+	Name               []highlight.Part    `json:"name"`
+	Description        []highlight.Part    `json:"description"`
+	Definitions        []*SearchDefinition `json:"definitions"`
+	MatchedDefinitions int                 `json:"matchedDefinitions"`
+}
+
+type SearchData struct {
+	Query string `validate:"required,max=256"`
+	Limit int    `validate:"min=0,max=50"`
+}
+
+// SearchRepo describes methods required by SearchRepo implementation.
+// Hits are sorted by relevance, the most relevant first. Only study sets visible to the viewer are searched,
+// the viewer id is empty for anonymous users.
+type SearchRepo interface {
+	SearchStudySets(ctx context.Context, viewerID string, query string, limit int) ([]*StudySetHit, error)
+	SearchDefinitions(ctx context.Context, viewerID string, query string, limit int) ([]*DefinitionHit, error)
+}
+
+// SearchUseCase describes methods required by SearchUseCase implementation.
+type SearchUseCase interface {
+	// Search matches study set names and descriptions as well as definition phrases and meanings.
+	// Matching definitions are grouped by their study sets.
+	Search(ctx context.Context, userID string, searchData *SearchData) ([]*SearchResult, error)
+}
@@ -0,0 +1,40 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// Skill represents the user's ability estimate in the given language.
+// Ability is on the logit scale, where 0 means an average learner.
+// ExpectedAccuracy is the chance of answering a definition of average difficulty correctly.
+type Skill struct {
+	Language         string     `json:"language"`
+	Ability          float64    `json:"ability"`
+	ExpectedAccuracy float64    `json:"expectedAccuracy"`
+	Answers          int        `json:"answers"`
+	UpdatedAt        *time.Time `json:"updatedAt"`
+}
+
+// DefinitionDifficulty represents the difficulty estimate of a definition shared by all users.
+type DefinitionDifficulty struct {
+	DefinitionId int64
+	Difficulty   float64
+	Answers      int
+}
+
+// SkillRepo describes methods required by SkillRepo implementation.
+type SkillRepo interface {
+	GetAll(ctx context.Context, userID string) ([]*Skill, error)
+	// Get returns the user's skill in the language or nil if the user has never answered in it.
+	Get(ctx context.Context, userID string, language string) (*Skill, error)
+	Upsert(ctx context.Context, userID string, skill *Skill) error
+	// GetDifficultiesFor returns difficulty estimates of definitions from the study set that have ever been answered.
+	GetDifficultiesFor(ctx context.Context, studySetID int64) ([]*DefinitionDifficulty, error)
+	UpsertDifficulty(ctx context.Context, difficulty *DefinitionDifficulty) error
+}
+
+// SkillUseCase describes methods required by SkillUseCase implementation.
+type SkillUseCase interface {
+	GetAll(ctx context.Context, userID string) ([]*Skill, error)
+}
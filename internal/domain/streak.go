@@ -0,0 +1,60 @@
+package domain
+
+import "context"
+
+// Streak represents learner's streak of consecutive study days.
+// Freezes are earned while keeping the streak and cover days without any study activity.
+type Streak struct {
+	Current          int     `json:"current"`
+	Longest          int     `json:"longest"`
+	FreezesAvailable int     `json:"freezesAvailable"`
+	LastActiveDate   *string `json:"lastActiveDate"`
+	ActiveToday      bool    `json:"activeToday"`
+}
+
+// DailyGoal represents learner's daily goal and today's progress towards it.
+type DailyGoal struct {
+	Type      string `json:"type"`
+	Target    int    `json:"target"`
+	Progress  int    `json:"progress"`
+	Completed bool   `json:"completed"`
+	Date      string `json:"date"`
+	Timezone  string `json:"timezone"`
+}
+
+type UpdateDailyGoalData struct {
+	Type     string `json:"type" validate:"required,oneof=REVIEWS MINUTES"`
+	Target   int    `json:"target" validate:"required,min=1,max=1440"`
+	Timezone string `json:"timezone" validate:"required,timezone"`
+}
+
+// StreakRow represents data stored in streak table.
+type StreakRow struct {
+	Current          int
+	Longest          int
+	FreezesAvailable int
+	LastActiveDate   *string
+}
+
+// DailyActivityRow represents study activity of a user during a single day in their timezone.
+type DailyActivityRow struct {
+	Reviews        int
+	ResponseTimeMs int64
+}
+
+// StreakRepo describes methods required by StreakRepo implementation.
+// Dates are formatted as YYYY-MM-DD in the user's timezone.
+type StreakRepo interface {
+	// Get returns the streak of the given user or nil if the user has never studied.
+	Get(ctx context.Context, userID string) (*StreakRow, error)
+	Upsert(ctx context.Context, userID string, streak *StreakRow) error
+	GetDailyActivity(ctx context.Context, userID string, date string) (*DailyActivityRow, error)
+	AddDailyActivity(ctx context.Context, userID string, date string, activity *DailyActivityRow) error
+}
+
+// StreakUseCase describes methods required by StreakUseCase implementation.
+type StreakUseCase interface {
+	GetStreak(ctx context.Context, userID string) (*Streak, error)
+	GetDailyGoal(ctx context.Context, userID string) (*DailyGoal, error)
+	UpdateDailyGoal(ctx context.Context, userID string, updateData *UpdateDailyGoalData) error
+}
@@ -5,6 +5,15 @@ import (
 	"time"
 )
 
+const (
+	StudyModeFlashcards = "FLASHCARDS"
+	StudyModeWrite      = "WRITE"
+	StudyModeQuiz       = "QUIZ"
+	StudyModeCloze      = "CLOZE"
+	// StudyModeReview is used for sessions started implicitly by submitting reviews outside of any session.
+	StudyModeReview = "REVIEW"
+)
+
 type StudySession struct {
 	LastSessionAt *time.Time `json:"lastSessionAt"`
 }
@@ -14,18 +23,95 @@ type StudySessionWithStudySet struct {
 	StudySet      StudySetWithAuthor `json:"studySet"`
 }
 
+// StudySessionSummary represents a single study session.
+// Duration and counters are nil until the session is finished.
+// Sessions of a whole folder have zero study set id and the name of the folder as the study set name.
+type StudySessionSummary struct {
+	Id             int64      `json:"id"`
+	StudySetId     int64      `json:"studySetId"`
+	FolderId       *int64     `json:"folderId"`
+	StudySetName   string     `json:"studySetName"`
+	Mode           string     `json:"mode"`
+	Direction      string     `json:"direction"`
+	StartedAt      *time.Time `json:"startedAt"`
+	FinishedAt     *time.Time `json:"finishedAt"`
+	DurationMs     *int64     `json:"durationMs"`
+	CardsSeen      *int       `json:"cardsSeen"`
+	CorrectCount   *int       `json:"correctCount"`
+	IncorrectCount *int       `json:"incorrectCount"`
+}
+
+// StudySessionRow represents data stored in study session table.
+type StudySessionRow struct {
+	StudySessionSummary
+	UserId string
+}
+
+// StudySessionOutcome represents the result of a single card shown during a study session.
+type StudySessionOutcome struct {
+	DefinitionId   int64 `json:"definitionId" validate:"required"`
+	Correct        bool  `json:"correct"`
+	ResponseTimeMs int   `json:"responseTimeMs" validate:"min=0"`
+}
+
+// StudySessionDetails represents a study session together with its per card outcomes.
+type StudySessionDetails struct {
+	StudySessionSummary
+	Outcomes []*StudySessionOutcome `json:"outcomes"`
+}
+
+// StudySessionPage represents a page of study session history.
+// NextCursor is nil if there are no more sessions.
+type StudySessionPage struct {
+	Sessions   []*StudySessionSummary `json:"sessions"`
+	NextCursor *int64                 `json:"nextCursor"`
+}
+
+type StartStudySessionData struct {
+	StudySetId int64  `json:"studySetId" validate:"required"`
+	Mode       string `json:"mode" validate:"required,oneof=FLASHCARDS WRITE QUIZ CLOZE REVIEW"`
+	Direction  string `json:"direction" validate:"required,oneof=PHRASE_TO_MEANING MEANING_TO_PHRASE"`
+}
+
+type FinishStudySessionData struct {
+	Outcomes []*StudySessionOutcome `json:"outcomes" validate:"max=1000,dive,required"`
+}
+
+// FinishStudySessionRow represents the summary stored when a study session is finished.
+type FinishStudySessionRow struct {
+	DurationMs     int64
+	CardsSeen      int
+	CorrectCount   int
+	IncorrectCount int
+}
+
 type StudySessionUseCase interface {
 	GetRecent(ctx context.Context, userID string) ([]*StudySessionWithStudySet, error)
+	// GetHistory returns study sessions of the user, the most recent first.
+	// Cursor is the id of the last session from the previous page or 0 for the first page.
+	GetHistory(ctx context.Context, userID string, cursor int64, limit int) (*StudySessionPage, error)
 	GetForStudySet(ctx context.Context, userID string, studySetID int64) (*StudySession, error)
+	GetDetails(ctx context.Context, userID string, studySessionID int64) (*StudySessionDetails, error)
+	Start(ctx context.Context, userID string, startData *StartStudySessionData) (int64, error)
+	Finish(ctx context.Context, userID string, studySessionID int64, finishData *FinishStudySessionData) (*StudySessionSummary, error)
 	// Refresh is responsible for refreshing the given study session.
-	// Refreshing means updating last session timestamp. If the session does not exist a new session is created.
+	// Refreshing means updating last session timestamp of the latest unfinished session for the study set.
+	// If there is no such session a new session is created.
 	Refresh(ctx context.Context, userID string, studySetID int64) error
 }
 
 type StudySessionRepo interface {
 	GetRecent(ctx context.Context, userID string) ([]*StudySessionWithStudySet, error)
+	GetHistory(ctx context.Context, userID string, cursor int64, limit int) ([]*StudySessionSummary, error)
 	GetForStudySet(ctx context.Context, userID string, studySetID int64) (*StudySession, error)
+	Get(ctx context.Context, studySessionID int64) (*StudySessionRow, error)
+	GetOutcomes(ctx context.Context, studySessionID int64) ([]*StudySessionOutcome, error)
+	Start(ctx context.Context, userID string, studySetID int64, mode string, direction string) (int64, error)
+	StartForFolder(ctx context.Context, userID string, folderID int64, mode string, direction string) (int64, error)
+	Finish(ctx context.Context, studySessionID int64, finishData *FinishStudySessionRow) error
+	InsertOutcome(ctx context.Context, studySessionID int64, position int, outcome *StudySessionOutcome) error
 	Create(ctx context.Context, userID string, studySetID int64) error
 	Refresh(ctx context.Context, userID string, studySetID int64) error
+	// Exists checks if the user has an unfinished session for the study set.
 	Exists(ctx context.Context, userID string, studySetID int64) (bool, error)
 }
@@ -13,52 +13,152 @@ type Author struct {
 
 // StudySetWithAuthor represents final form of study set information.
 type StudySetWithAuthor struct {
-	Id                 int64  `json:"id"`
-	Author             Author `json:"author"`
-	Name               string `json:"name"`
-	Description        string `json:"description"`
-	PhraseLanguage     string `json:"phraseLanguage"`
-	DefinitionLanguage string `json:"definitionLanguage"`
-	Icon               string `json:"icon"`
-	Color              string `json:"color"`
+	Id                 int64   `json:"id"`
+	Author             Author  `json:"author"`
+	Name               string  `json:"name"`
+	Description        string  `json:"description"`
+	PhraseLanguage     string  `json:"phraseLanguage"`
+	DefinitionLanguage string  `json:"definitionLanguage"`
+	Icon               string  `json:"icon"`
+	Color              string  `json:"color"`
+	CefrLevel          *string `json:"cefrLevel"`
+	Visibility         string  `json:"visibility"`
+	Category           *string `json:"category"`
+}
+
+// ForkOrigin tells which study set a fork was copied from.
+// StudySetId is nil if the original study set has been deleted since.
+type ForkOrigin struct {
+	StudySetId *int64 `json:"studySetId"`
+	Author     Author `json:"author"`
+}
+
+// StudySetDetails represents study set information shown on its own page.
+// ForkedFrom is nil if the study set is not a fork.
+type StudySetDetails struct {
+	StudySetWithAuthor
+	Tags       []string    `json:"tags"`
+	ForkedFrom *ForkOrigin `json:"forkedFrom"`
+	Forks      int64       `json:"forks"`
 }
 
 // StudySet represents data stored in study set table.
 type StudySet struct {
-	Id                 int64  `json:"id"`
-	Name               string `json:"name"`
-	Description        string `json:"description"`
-	PhraseLanguage     string `json:"phraseLanguage"`
-	DefinitionLanguage string `json:"definitionLanguage"`
-	Icon               string `json:"icon"`
-	Color              string `json:"color"`
+	Id                 int64   `json:"id"`
+	Name               string  `json:"name"`
+	Description        string  `json:"description"`
+	PhraseLanguage     string  `json:"phraseLanguage"`
+	DefinitionLanguage string  `json:"definitionLanguage"`
+	Icon               string  `json:"icon"`
+	Color              string  `json:"color"`
+	CefrLevel          *string `json:"cefrLevel"`
+	Visibility         string  `json:"visibility"`
+	Category           *string `json:"category"`
+}
+
+const (
+	// VisibilityPrivate means that only the author can see the study set.
+	VisibilityPrivate = "PRIVATE"
+	// VisibilityUnlisted means that anyone with the link can see the study set, but it is not listed anywhere.
+	VisibilityUnlisted = "UNLISTED"
+	// VisibilityPublic means that the study set is listed and can be found by anyone.
+	VisibilityPublic = "PUBLIC"
+)
+
+const (
+	StudySetSortNewest  = "newest"
+	StudySetSortStars   = "stars"
+	StudySetSortStudied = "studied"
+	StudySetSortName    = "name"
+)
+
+// StudySetListing represents a study set shown on study set lists.
+// Learners is the number of users who have studied the study set.
+type StudySetListing struct {
+	StudySetWithAuthor
+	Stars       int64 `json:"stars"`
+	Learners    int64 `json:"learners"`
+	Definitions int64 `json:"definitions"`
+}
+
+// StudySetFilter narrows down and orders study set lists. Empty fields do not filter anything.
+type StudySetFilter struct {
+	Sort               string `validate:"omitempty,oneof=newest stars studied name"`
+	PhraseLanguage     string `validate:"max=16"`
+	DefinitionLanguage string `validate:"max=16"`
+	AuthorId           string `validate:"max=32"`
+	CefrLevel          string `validate:"omitempty,oneof=A1 A2 B1 B2 C1 C2"`
+	Category           string `validate:"omitempty,oneof=TRAVEL BUSINESS EXAMS EVERYDAY FOOD HEALTH SCIENCE TECHNOLOGY CULTURE SCHOOL"`
+	// Tag is normalized before filtering.
+	Tag            string
+	HasDefinitions *bool
+	// StarredBy limits the list to study sets starred by the given user.
+	StarredBy string `validate:"max=32"`
+	// SharedWith limits the list to study sets the given user collaborates on.
+	SharedWith string `validate:"max=32"`
+	// Only public study sets are listed, apart from study sets the viewer authors or collaborates on
+	// and unlisted study sets when IncludeUnlisted is set.
+	ViewerId        string `validate:"max=32"`
+	IncludeUnlisted bool
+}
+
+// StudySetCursor points at the last study set of a page.
+// Only the value of the key the list is sorted by is set, the id breaks ties.
+type StudySetCursor struct {
+	Sort  string `json:"s"`
+	Count int64  `json:"c,omitempty"`
+	Name  string `json:"n,omitempty"`
+	Id    int64  `json:"i"`
+}
+
+// StudySetPage represents a single page of a study set list.
+// NextCursor is nil if there are no more study sets.
+type StudySetPage struct {
+	StudySets  []*StudySetListing `json:"studySets"`
+	NextCursor *string            `json:"nextCursor"`
 }
 
 type InsertStudySetData struct {
-	AuthorId           string `json:"-" validate:"required"`
-	Name               string `json:"name" validate:"required,max=128"`
-	Description        string `json:"description" validate:"required,max=512"`
-	PhraseLanguage     string `json:"phraseLanguage" validate:"required,max=16"`
-	DefinitionLanguage string `json:"definitionLanguage" validate:"required,max=16"`
-	Icon               string `json:"icon" validate:"required,max=32"`
-	Color              string `json:"color" validate:"required,max=32"`
+	AuthorId           string  `json:"-" validate:"required"`
+	Name               string  `json:"name" validate:"required,max=128"`
+	Description        string  `json:"description" validate:"required,max=512"`
+	PhraseLanguage     string  `json:"phraseLanguage" validate:"required,max=16"`
+	DefinitionLanguage string  `json:"definitionLanguage" validate:"required,max=16"`
+	Icon               string  `json:"icon" validate:"required,max=32"`
+	Color              string  `json:"color" validate:"required,max=32"`
+	CefrLevel          *string `json:"cefrLevel" validate:"omitempty,oneof=A1 A2 B1 B2 C1 C2"`
+	Visibility         string  `json:"visibility" validate:"omitempty,oneof=PRIVATE UNLISTED PUBLIC"`
+	Category           *string `json:"category" validate:"omitempty,oneof=TRAVEL BUSINESS EXAMS EVERYDAY FOOD HEALTH SCIENCE TECHNOLOGY CULTURE SCHOOL"`
+	// Tags are normalized before saving.
+	Tags []string `json:"tags" validate:"max=10"`
+	// ForkedFromId and ForkedFromAuthorId record the provenance of forks and are never set by clients.
+	ForkedFromId       *int64  `json:"-"`
+	ForkedFromAuthorId *string `json:"-"`
 }
 
 type UpdateStudySetData struct {
-	Name               string `json:"name" validate:"required,max=128"`
-	Description        string `json:"description" validate:"required,max=512"`
-	PhraseLanguage     string `json:"phraseLanguage" validate:"required,max=16"`
-	DefinitionLanguage string `json:"definitionLanguage" validate:"required,max=16"`
-	Icon               string `json:"icon" validate:"required,max=32"`
-	Color              string `json:"color" validate:"required,max=32"`
+	Name               string  `json:"name" validate:"required,max=128"`
+	Description        string  `json:"description" validate:"required,max=512"`
+	PhraseLanguage     string  `json:"phraseLanguage" validate:"required,max=16"`
+	DefinitionLanguage string  `json:"definitionLanguage" validate:"required,max=16"`
+	Icon               string  `json:"icon" validate:"required,max=32"`
+	Color              string  `json:"color" validate:"required,max=32"`
+	CefrLevel          *string `json:"cefrLevel" validate:"omitempty,oneof=A1 A2 B1 B2 C1 C2"`
+	Visibility         string  `json:"visibility" validate:"omitempty,oneof=PRIVATE UNLISTED PUBLIC"`
+	Category           *string `json:"category" validate:"omitempty,oneof=TRAVEL BUSINESS EXAMS EVERYDAY FOOD HEALTH SCIENCE TECHNOLOGY CULTURE SCHOOL"`
+	// Tags are normalized before saving. Nil tags keep the current ones.
+	Tags []string `json:"tags" validate:"max=10"`
 }
 
 // StudySetRepo describes methods required by StudySetRepo implementation.
 type StudySetRepo interface {
-	GetAll(ctx context.Context) ([]*StudySetWithAuthor, error)
+	// GetPage returns up to limit study sets matching the filter that come after the cursor.
+	// Nil cursor means the first page.
+	GetPage(ctx context.Context, filter *StudySetFilter, after *StudySetCursor, limit int) ([]*StudySetListing, error)
 	GetById(ctx context.Context, studySetID int64) (*StudySetWithAuthor, error)
-	GetCreatedBy(ctx context.Context, userID string) ([]*StudySet, error)
-	GetStarredBy(ctx context.Context, userID string) ([]*StudySetWithAuthor, error)
+	// GetDetailsById returns the study set together with its fork provenance and fork count.
+	GetDetailsById(ctx context.Context, studySetID int64) (*StudySetDetails, error)
+	GetByCefrLevel(ctx context.Context, phraseLanguage string, level string) ([]*StudySetWithAuthor, error)
 	Insert(ctx context.Context, insertData *InsertStudySetData) (int64, error)
 	Update(ctx context.Context, studySetID int64, updateData *UpdateStudySetData) error
 	Delete(ctx context.Context, studySetID int64) error
@@ -67,9 +167,14 @@ type StudySetRepo interface {
 
 // StudySetUseCase describes methods required by StudySetUseCase implementation.
 type StudySetUseCase interface {
-	GetAll(ctx context.Context) ([]*StudySetWithAuthor, error)
-	GetById(ctx context.Context, studySetID int64) (*StudySetWithAuthor, error)
+	// GetPage returns a page of study sets matching the filter.
+	// Cursor is the next cursor returned with the previous page or an empty string for the first page.
+	GetPage(ctx context.Context, filter *StudySetFilter, cursor string, limit int) (*StudySetPage, error)
+	// GetById returns the study set if the user can see it. Empty user id means an anonymous user.
+	GetById(ctx context.Context, userID string, studySetID int64) (*StudySetDetails, error)
 	Create(ctx context.Context, createData *InsertStudySetData) (int64, error)
+	// Fork copies the study set and its definitions into a new private study set of the user.
+	Fork(ctx context.Context, userID string, studySetID int64) (int64, error)
 	Update(ctx context.Context, userID string, studySetID int64, updateData *UpdateStudySetData) error
 	Delete(ctx context.Context, userID string, studySetID int64) error
 }
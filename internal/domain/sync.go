@@ -0,0 +1,75 @@
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+// SyncEventData represents a review made by an offline client.
+// EventId is generated by the client and makes uploading the same event more than once harmless.
+type SyncEventData struct {
+	EventId        string     `json:"eventId" validate:"required,max=64"`
+	StudySetId     int64      `json:"studySetId" validate:"required"`
+	DefinitionId   int64      `json:"definitionId" validate:"required"`
+	Grade          int        `json:"grade" validate:"min=0,max=5"`
+	ResponseTimeMs int        `json:"responseTimeMs" validate:"min=0"`
+	AnsweredAt     *time.Time `json:"answeredAt" validate:"required"`
+}
+
+// SyncData represents a single sync request.
+// Cursor is the cursor returned by the previous sync or nil if the client has never synced.
+type SyncData struct {
+	Cursor *time.Time       `json:"cursor"`
+	Events []*SyncEventData `json:"events" validate:"max=1000,dive,required"`
+}
+
+// SyncRejection explains why an event was not applied. Rejected events are not stored.
+type SyncRejection struct {
+	EventId string `json:"eventId"`
+	Reason  string `json:"reason"`
+}
+
+// SyncedReviewState represents a review state changed since the client's cursor.
+type SyncedReviewState struct {
+	ReviewState
+	StudySetId int64 `json:"studySetId"`
+}
+
+// SyncedDefinition represents a definition changed since the client's cursor.
+type SyncedDefinition struct {
+	Definition
+	StudySetId int64 `json:"studySetId"`
+}
+
+// SyncResult represents the outcome of applying the events together with the changes since the client's cursor.
+// Cursor should be sent with the next sync.
+type SyncResult struct {
+	Cursor               time.Time            `json:"cursor"`
+	Accepted             []string             `json:"accepted"`
+	Duplicates           []string             `json:"duplicates"`
+	Rejected             []*SyncRejection     `json:"rejected"`
+	States               []*SyncedReviewState `json:"states"`
+	Definitions          []*SyncedDefinition  `json:"definitions"`
+	DeletedDefinitionIds []int64              `json:"deletedDefinitionIds"`
+}
+
+// SyncRepo describes methods required by SyncRepo implementation.
+// Nil since means that everything should be returned.
+type SyncRepo interface {
+	// Now returns current time of the database clock, which is used for change timestamps.
+	Now(ctx context.Context) (time.Time, error)
+	GetChangedStates(ctx context.Context, userID string, since *time.Time) ([]*SyncedReviewState, error)
+	// GetChangedDefinitions returns changed definitions from study sets the user has studied.
+	GetChangedDefinitions(ctx context.Context, userID string, since *time.Time) ([]*SyncedDefinition, error)
+	// GetDeletedDefinitions returns ids of definitions deleted from study sets the user has studied, directly or through a folder,
+	// including study sets that have been deleted as a whole.
+	GetDeletedDefinitions(ctx context.Context, userID string, since time.Time) ([]int64, error)
+}
+
+// SyncUseCase describes methods required by SyncUseCase implementation.
+type SyncUseCase interface {
+	// Sync merges the events into the review history and returns changes since the client's cursor.
+	// Review states of the affected definitions are recomputed from the whole review history,
+	// so the result does not depend on the order in which devices upload their events.
+	Sync(ctx context.Context, userID string, syncData *SyncData) (*SyncResult, error)
+}
@@ -0,0 +1,66 @@
+package domain
+
+import (
+	"context"
+)
+
+// Categories form a curated taxonomy of study sets. A study set belongs to at most one category.
+const (
+	CategoryTravel     = "TRAVEL"
+	CategoryBusiness   = "BUSINESS"
+	CategoryExams      = "EXAMS"
+	CategoryEveryday   = "EVERYDAY"
+	CategoryFood       = "FOOD"
+	CategoryHealth     = "HEALTH"
+	CategoryScience    = "SCIENCE"
+	CategoryTechnology = "TECHNOLOGY"
+	CategoryCulture    = "CULTURE"
+	CategorySchool     = "SCHOOL"
+)
+
+// Categories lists all the categories in the order they are shown to users.
+var Categories = []string{
+	CategoryTravel,
+	CategoryBusiness,
+	CategoryExams,
+	CategoryEveryday,
+	CategoryFood,
+	CategoryHealth,
+	CategoryScience,
+	CategoryTechnology,
+	CategoryCulture,
+	CategorySchool,
+}
+
+// Tag represents a normalized tag with the number of public study sets using it.
+type Tag struct {
+	Name string `json:"name"`
+	Uses int64  `json:"uses"`
+}
+
+// CategoryUsage represents a category with the number of public study sets in it.
+type CategoryUsage struct {
+	Category  string `json:"category"`
+	StudySets int64  `json:"studySets"`
+}
+
+// TagRepo describes methods required by TagRepo implementation.
+type TagRepo interface {
+	// GetByPrefix returns tags of public study sets starting with the prefix, the most used first.
+	GetByPrefix(ctx context.Context, prefix string, limit int) ([]*Tag, error)
+	GetForStudySet(ctx context.Context, studySetID int64) ([]string, error)
+	// SetForStudySet replaces tags of the study set. Tags that do not exist yet are created.
+	SetForStudySet(ctx context.Context, studySetID int64, tags []string) error
+	// GetCategoryUsage returns categories that have at least one public study set.
+	GetCategoryUsage(ctx context.Context) ([]*CategoryUsage, error)
+}
+
+// TagUseCase describes methods required by TagUseCase implementation.
+type TagUseCase interface {
+	// Autocomplete returns tags starting with the normalized prefix, the most used first.
+	Autocomplete(ctx context.Context, prefix string, limit int) ([]*Tag, error)
+	// GetCategories returns all the categories with their usage.
+	GetCategories(ctx context.Context) ([]*CategoryUsage, error)
+	// GetStudySets returns a page of public study sets with the tag.
+	GetStudySets(ctx context.Context, tag string, filter *StudySetFilter, cursor string, limit int) (*StudySetPage, error)
+}
@@ -0,0 +1,120 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/go-sql-driver/mysql"
+
+	"ailingo/internal/domain"
+)
+
+// getCollaborators queries for all collaborators of the given study set.
+const getCollaborators = `
+SELECT user.id, user.username, user.image_url, collaborator.role, collaborator.added_at
+FROM collaborator
+         INNER JOIN user ON user.id = collaborator.user_id
+WHERE collaborator.study_set_id = ?
+ORDER BY collaborator.added_at, user.id
+`
+
+// getCollaboratorRole queries for the role of the user in the given study set.
+const getCollaboratorRole = `
+SELECT role
+FROM collaborator
+WHERE study_set_id = ?
+  AND user_id = ?
+`
+
+// insertCollaborator adds a new collaborator to the study set.
+const insertCollaborator = `
+INSERT INTO collaborator (study_set_id, user_id, role)
+VALUES (?, ?, ?)
+`
+
+// updateCollaboratorRole changes the role of the collaborator.
+const updateCollaboratorRole = `
+UPDATE collaborator
+SET role = ?
+WHERE study_set_id = ?
+  AND user_id = ?
+`
+
+// deleteCollaborator removes the collaborator from the study set.
+const deleteCollaborator = `
+DELETE
+FROM collaborator
+WHERE study_set_id = ?
+  AND user_id = ?
+`
+
+type collaboratorRepo struct {
+	db DBTX
+}
+
+func NewCollaboratorRepo(db DBTX) domain.CollaboratorRepo {
+	return &collaboratorRepo{
+		db: db,
+	}
+}
+
+func (r *collaboratorRepo) GetAll(ctx context.Context, studySetID int64) ([]*domain.Collaborator, error) {
+	collaborators := make([]*domain.Collaborator, 0)
+
+	rows, err := r.db.QueryContext(ctx, getCollaborators, studySetID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var collaborator domain.Collaborator
+		if err := rows.Scan(&collaborator.UserId, &collaborator.Username, &collaborator.ImageURL, &collaborator.Role, &collaborator.AddedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan: %w", err)
+		}
+		collaborators = append(collaborators, &collaborator)
+	}
+
+	return collaborators, nil
+}
+
+func (r *collaboratorRepo) GetRole(ctx context.Context, studySetID int64, userID string) (string, error) {
+	var role string
+	if err := r.db.QueryRowContext(ctx, getCollaboratorRole, studySetID, userID).Scan(&role); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to scan: %w", err)
+	}
+	return role, nil
+}
+
+func (r *collaboratorRepo) Insert(ctx context.Context, studySetID int64, userID string, role string) error {
+	if _, err := r.db.ExecContext(ctx, insertCollaborator, studySetID, userID, role); err != nil {
+		var mysqlerr *mysql.MySQLError
+		if errors.As(err, &mysqlerr) {
+			// 1062 error number stands for duplicate entry code
+			if mysqlerr.Number == 1062 {
+				return ErrDuplicateRow
+			}
+		}
+		return fmt.Errorf("failed to exec: %w", err)
+	}
+	return nil
+}
+
+func (r *collaboratorRepo) UpdateRole(ctx context.Context, studySetID int64, userID string, role string) error {
+	if _, err := r.db.ExecContext(ctx, updateCollaboratorRole, role, studySetID, userID); err != nil {
+		return fmt.Errorf("failed to exec: %w", err)
+	}
+	return nil
+}
+
+func (r *collaboratorRepo) Delete(ctx context.Context, studySetID int64, userID string) error {
+	if _, err := r.db.ExecContext(ctx, deleteCollaborator, studySetID, userID); err != nil {
+		return fmt.Errorf("failed to exec: %w", err)
+	}
+	return nil
+}
@@ -46,18 +46,79 @@ func (ds *dataStore) GetTaskRepo() domain.TaskRepo {
 	return NewTaskRepo(ds.db)
 }
 
-func (ds *dataStore) Atomic(ctx context.Context, cb func(ds domain.DataStore) error) error {
-	var err error
+func (ds *dataStore) GetReviewRepo() domain.ReviewRepo {
+	return NewReviewRepo(ds.db)
+}
+
+func (ds *dataStore) GetQuizRepo() domain.QuizRepo {
+	return NewQuizRepo(ds.db)
+}
+
+func (ds *dataStore) GetExamRepo() domain.ExamRepo {
+	return NewExamRepo(ds.db)
+}
+
+func (ds *dataStore) GetPreferencesRepo() domain.PreferencesRepo {
+	return NewPreferencesRepo(ds.db)
+}
+
+func (ds *dataStore) GetStreakRepo() domain.StreakRepo {
+	return NewStreakRepo(ds.db)
+}
+
+func (ds *dataStore) GetSyncRepo() domain.SyncRepo {
+	return NewSyncRepo(ds.db)
+}
+
+func (ds *dataStore) GetSkillRepo() domain.SkillRepo {
+	return NewSkillRepo(ds.db)
+}
+
+func (ds *dataStore) GetPlacementRepo() domain.PlacementRepo {
+	return NewPlacementRepo(ds.db)
+}
+
+func (ds *dataStore) GetSearchRepo() domain.SearchRepo {
+	return NewSearchRepo(ds.db)
+}
+
+func (ds *dataStore) GetCollaboratorRepo() domain.CollaboratorRepo {
+	return NewCollaboratorRepo(ds.db)
+}
+
+func (ds *dataStore) GetRevisionRepo() domain.RevisionRepo {
+	return NewRevisionRepo(ds.db)
+}
+
+func (ds *dataStore) GetTagRepo() domain.TagRepo {
+	return NewTagRepo(ds.db)
+}
+
+func (ds *dataStore) GetFolderRepo() domain.FolderRepo {
+	return NewFolderRepo(ds.db)
+}
 
+func (ds *dataStore) Atomic(ctx context.Context, cb func(ds domain.DataStore) error) (err error) {
 	tx, err := ds.conn.BeginTx(ctx, nil)
 	if err != nil {
 		return fmt.Errorf("failed to begin a tx: %w", err)
 	}
 
+	// Changes must not be committed if the callback fails, otherwise batch operations could be applied partially.
 	defer func() {
-		// TODO: In the given article there is something more in this function
-		// https://blog.devgenius.io/go-golang-clean-architecture-repositories-vs-transactions-9b3b7c953463
-		err = tx.Commit()
+		if p := recover(); p != nil {
+			_ = tx.Rollback()
+			panic(p)
+		}
+		if err != nil {
+			if rbErr := tx.Rollback(); rbErr != nil {
+				err = fmt.Errorf("failed to rollback the tx: %w (cause: %w)", rbErr, err)
+			}
+			return
+		}
+		if commitErr := tx.Commit(); commitErr != nil {
+			err = fmt.Errorf("failed to commit the tx: %w", commitErr)
+		}
 	}()
 
 	newStore := &dataStore{
@@ -10,17 +10,50 @@ import (
 	"ailingo/internal/domain"
 )
 
-// getDefinitionsForStudySet queries for all definitions connected with the given study set.
+// getDefinitionsForStudySet queries for all definitions connected with the given study set in order.
 const getDefinitionsForStudySet = `
 SELECT id, phrase, meaning, sentences
 FROM definition
 WHERE study_set_id = ?
+ORDER BY position, id
 `
 
-// insertDefinition inserts a new definitions.
+// getDefinition queries for the given definition from the given study set.
+const getDefinition = `
+SELECT id, phrase, meaning, sentences
+FROM definition
+WHERE study_set_id = ?
+  AND id = ?
+`
+
+// getDefinitionSample queries for random definitions from public study sets with the given languages.
+const getDefinitionSample = `
+SELECT definition.id, definition.phrase, definition.meaning, definition.sentences
+FROM definition
+         INNER JOIN study_set ON study_set.id = definition.study_set_id
+WHERE study_set.visibility = 'PUBLIC'
+  AND study_set.phrase_language = ?
+  AND study_set.definition_language = ?
+  AND study_set.id <> ?
+ORDER BY RAND()
+LIMIT ?
+`
+
+// insertDefinition inserts a new definition after all the other definitions of the study set.
 const insertDefinition = `
-INSERT INTO definition (study_set_id, phrase, meaning, sentences)
-VALUES (?, ?, ?, ?)
+INSERT INTO definition (study_set_id, phrase, meaning, sentences, position)
+SELECT ?, ?, ?, ?, COALESCE(MAX(position) + 1, 0)
+FROM definition
+WHERE study_set_id = ?
+`
+
+// copyDefinitions copies all definitions of one study set into another.
+const copyDefinitions = `
+INSERT INTO definition (study_set_id, phrase, meaning, sentences, position)
+SELECT ?, phrase, meaning, sentences, position
+FROM definition
+WHERE study_set_id = ?
+ORDER BY position, id
 `
 
 // updateDefinitionById updates the specified definition.
@@ -32,6 +65,56 @@ SET phrase    = ?,
 WHERE id = ?
 `
 
+const updateDefinitionPosition = `
+UPDATE definition
+SET position = ?
+WHERE id = ?
+`
+
+// insertDefinitionTombstone remembers the specified definition as deleted, so that offline clients can forget it.
+const insertDefinitionTombstone = `
+INSERT INTO definition_tombstone (definition_id, study_set_id)
+SELECT id, study_set_id
+FROM definition
+WHERE id = ?
+`
+
+const deleteDefinitionReviewStates = `
+DELETE
+FROM review_state
+WHERE definition_id = ?
+`
+
+const deleteDefinitionReviewLog = `
+DELETE
+FROM review_log
+WHERE definition_id = ?
+`
+
+const deleteDefinitionDifficulty = `
+DELETE
+FROM definition_difficulty
+WHERE definition_id = ?
+`
+
+const deleteDefinitionQuizQuestions = `
+DELETE
+FROM quiz_question
+WHERE definition_id = ?
+`
+
+const deleteDefinitionExamQuestions = `
+DELETE
+FROM exam_question
+WHERE definition_id = ?
+`
+
+const deleteDefinitionStudySessionOutcomes = `
+DELETE
+FROM study_session_outcome
+WHERE definition_id = ?
+`
+
 // deleteDefinitionById deletes the specified definition.
 const deleteDefinitionById = `
 DELETE
@@ -59,6 +142,7 @@ func (r *DefinitionRepo) GetAllFor(ctx context.Context, parentStudySetID int64)
 		}
 		return nil, fmt.Errorf("failed to query: %w", err)
 	}
+	defer rows.Close()
 
 	for rows.Next() {
 		var definition domain.DefinitionRow
@@ -78,13 +162,105 @@ func (r *DefinitionRepo) GetAllFor(ctx context.Context, parentStudySetID int64)
 	return definitions, nil
 }
 
-func (r *DefinitionRepo) Insert(ctx context.Context, parentStudySetID int64, insertData *domain.InsertDefinitionData) error {
+func (r *DefinitionRepo) ForEach(ctx context.Context, parentStudySetID int64, fn func(definition *domain.DefinitionRow) error) error {
+	rows, err := r.db.QueryContext(ctx, getDefinitionsForStudySet, parentStudySetID)
+	if err != nil {
+		return fmt.Errorf("failed to query: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var definition domain.DefinitionRow
+		var sentencesRaw json.RawMessage
+
+		if err := rows.Scan(&definition.Id, &definition.Phrase, &definition.Meaning, &sentencesRaw); err != nil {
+			return fmt.Errorf("failed to scan: %w", err)
+		}
+
+		if err := json.Unmarshal(sentencesRaw, &definition.Sentences); err != nil {
+			return fmt.Errorf("failed to unmarshal sentences: %w", err)
+		}
+
+		if err := fn(&definition); err != nil {
+			return err
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("failed to iterate: %w", err)
+	}
+
+	return nil
+}
+
+func (r *DefinitionRepo) Get(ctx context.Context, parentStudySetID int64, definitionID int64) (*domain.DefinitionRow, error) {
+	var definition domain.DefinitionRow
+	var sentencesRaw json.RawMessage
+
+	if err := r.db.QueryRowContext(ctx, getDefinition, parentStudySetID, definitionID).Scan(
+		&definition.Id, &definition.Phrase, &definition.Meaning, &sentencesRaw,
+	); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to scan: %w", err)
+	}
+
+	if err := json.Unmarshal(sentencesRaw, &definition.Sentences); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal sentences: %w", err)
+	}
+
+	return &definition, nil
+}
+
+func (r *DefinitionRepo) GetSample(ctx context.Context, phraseLanguage string, definitionLanguage string, excludedStudySetID int64, limit int) ([]*domain.DefinitionRow, error) {
+	definitions := make([]*domain.DefinitionRow, 0)
+
+	rows, err := r.db.QueryContext(ctx, getDefinitionSample, phraseLanguage, definitionLanguage, excludedStudySetID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var definition domain.DefinitionRow
+		var sentencesRaw json.RawMessage
+
+		if err := rows.Scan(&definition.Id, &definition.Phrase, &definition.Meaning, &sentencesRaw); err != nil {
+			return nil, fmt.Errorf("failed to scan: %w", err)
+		}
+
+		if err := json.Unmarshal(sentencesRaw, &definition.Sentences); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal sentences: %w", err)
+		}
+
+		definitions = append(definitions, &definition)
+	}
+
+	return definitions, nil
+}
+
+func (r *DefinitionRepo) Insert(ctx context.Context, parentStudySetID int64, insertData *domain.InsertDefinitionData) (int64, error) {
 	sentencesJson, err := json.Marshal(insertData.Sentences)
 	if err != nil {
-		return fmt.Errorf("failed to marshal sentences array")
+		return 0, fmt.Errorf("failed to marshal sentences array")
+	}
+
+	res, err := r.db.ExecContext(ctx, insertDefinition, parentStudySetID, insertData.Phrase, insertData.Meaning, string(sentencesJson), parentStudySetID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to exec: %w", err)
+	}
+
+	lastInsertId, err := res.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get last insert id: %w", err)
 	}
 
-	if _, err = r.db.ExecContext(ctx, insertDefinition, parentStudySetID, insertData.Phrase, insertData.Meaning, string(sentencesJson)); err != nil {
+	return lastInsertId, nil
+}
+
+func (r *DefinitionRepo) CopyAll(ctx context.Context, fromStudySetID int64, toStudySetID int64) error {
+	if _, err := r.db.ExecContext(ctx, copyDefinitions, toStudySetID, fromStudySetID); err != nil {
 		return fmt.Errorf("failed to exec: %w", err)
 	}
 
@@ -104,7 +280,33 @@ func (r *DefinitionRepo) Update(ctx context.Context, definitionID int64, updateD
 	return nil
 }
 
+func (r *DefinitionRepo) SetPosition(ctx context.Context, definitionID int64, position int) error {
+	if _, err := r.db.ExecContext(ctx, updateDefinitionPosition, position, definitionID); err != nil {
+		return fmt.Errorf("failed to exec: %w", err)
+	}
+
+	return nil
+}
+
 func (r *DefinitionRepo) Delete(ctx context.Context, definitionID int64) error {
+	if _, err := r.db.ExecContext(ctx, insertDefinitionTombstone, definitionID); err != nil {
+		return fmt.Errorf("failed to execute insert tombstone query: %w", err)
+	}
+
+	// State kept for the definition has no meaning once it is gone, and there are no foreign keys to clean it up.
+	for _, query := range []string{
+		deleteDefinitionReviewStates,
+		deleteDefinitionReviewLog,
+		deleteDefinitionDifficulty,
+		deleteDefinitionQuizQuestions,
+		deleteDefinitionExamQuestions,
+		deleteDefinitionStudySessionOutcomes,
+	} {
+		if _, err := r.db.ExecContext(ctx, query, definitionID); err != nil {
+			return fmt.Errorf("failed to execute delete query: %w", err)
+		}
+	}
+
 	// TODO: We could inform if any rows were removed or not.
 	if _, err := r.db.ExecContext(ctx, deleteDefinitionById, definitionID); err != nil {
 		return fmt.Errorf("failed to execute delete query: %w", err)
@@ -0,0 +1,201 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"ailingo/internal/domain"
+)
+
+// getExam queries for an exam with the given id.
+const getExam = `
+SELECT id, user_id, study_set_id, time_limit_seconds, score, total, timed_out, started_at, deadline_at, submitted_at
+FROM exam
+WHERE id = ?
+`
+
+// getExamQuestions queries for all questions of the given exam in their original order.
+const getExamQuestions = `
+SELECT id, definition_id, type, prompt, choices, answer, response, correct
+FROM exam_question
+WHERE exam_id = ?
+ORDER BY position
+`
+
+// getExamAttempts queries for all exams taken by the user for the given study set.
+const getExamAttempts = `
+SELECT id, score, total, timed_out, started_at, submitted_at
+FROM exam
+WHERE user_id = ?
+  AND study_set_id = ?
+ORDER BY id DESC
+`
+
+// insertExam inserts a new exam.
+const insertExam = `
+INSERT INTO exam (user_id, study_set_id, time_limit_seconds, total, started_at, deadline_at)
+VALUES (?, ?, ?, ?, ?, ?)
+`
+
+// insertExamQuestion inserts a new question into the given exam.
+const insertExamQuestion = `
+INSERT INTO exam_question (exam_id, definition_id, position, type, prompt, choices, answer)
+VALUES (?, ?, ?, ?, ?, ?, ?)
+`
+
+// saveExamResponse stores the answer given by the user together with its outcome, which is null if it was not graded.
+const saveExamResponse = `
+UPDATE exam_question
+SET response = ?,
+    correct  = ?
+WHERE id = ?
+`
+
+// submitExam marks the exam as submitted, unless it has already been submitted.
+const submitExam = `
+UPDATE exam
+SET score        = ?,
+    timed_out    = ?,
+    submitted_at = ?
+WHERE id = ?
+  AND submitted_at IS NULL
+`
+
+type examRepo struct {
+	db DBTX
+}
+
+func NewExamRepo(db DBTX) domain.ExamRepo {
+	return &examRepo{
+		db: db,
+	}
+}
+
+func (r *examRepo) Get(ctx context.Context, examID int64) (*domain.ExamRow, error) {
+	var exam domain.ExamRow
+	if err := r.db.QueryRowContext(ctx, getExam, examID).Scan(
+		&exam.Id, &exam.UserId, &exam.StudySetId, &exam.TimeLimitSeconds, &exam.Score, &exam.Total, &exam.TimedOut,
+		&exam.StartedAt, &exam.DeadlineAt, &exam.SubmittedAt,
+	); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to scan: %w", err)
+	}
+	return &exam, nil
+}
+
+func (r *examRepo) GetQuestions(ctx context.Context, examID int64) ([]*domain.ExamQuestionRow, error) {
+	questions := make([]*domain.ExamQuestionRow, 0)
+
+	rows, err := r.db.QueryContext(ctx, getExamQuestions, examID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var question domain.ExamQuestionRow
+		var choicesRaw []byte
+
+		if err := rows.Scan(
+			&question.Id, &question.DefinitionId, &question.Type, &question.Prompt, &choicesRaw,
+			&question.Answer, &question.Response, &question.Correct,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan: %w", err)
+		}
+
+		if choicesRaw != nil {
+			if err := json.Unmarshal(choicesRaw, &question.Choices); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal choices: %w", err)
+			}
+		}
+
+		questions = append(questions, &question)
+	}
+
+	return questions, nil
+}
+
+func (r *examRepo) GetAttempts(ctx context.Context, userID string, studySetID int64) ([]*domain.ExamAttempt, error) {
+	attempts := make([]*domain.ExamAttempt, 0)
+
+	rows, err := r.db.QueryContext(ctx, getExamAttempts, userID, studySetID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var attempt domain.ExamAttempt
+		if err := rows.Scan(&attempt.Id, &attempt.Score, &attempt.Total, &attempt.TimedOut, &attempt.StartedAt, &attempt.SubmittedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan: %w", err)
+		}
+		attempts = append(attempts, &attempt)
+	}
+
+	return attempts, nil
+}
+
+func (r *examRepo) Insert(ctx context.Context, exam *domain.ExamRow) (int64, error) {
+	res, err := r.db.ExecContext(ctx, insertExam, exam.UserId, exam.StudySetId, exam.TimeLimitSeconds, exam.Total, exam.StartedAt, exam.DeadlineAt)
+	if err != nil {
+		return 0, fmt.Errorf("failed to exec: %w", err)
+	}
+
+	lastInsertId, err := res.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get last insert id: %w", err)
+	}
+
+	return lastInsertId, nil
+}
+
+func (r *examRepo) InsertQuestion(ctx context.Context, examID int64, position int, question *domain.ExamQuestionRow) (int64, error) {
+	var choices *string
+	if question.Choices != nil {
+		choicesJson, err := json.Marshal(question.Choices)
+		if err != nil {
+			return 0, fmt.Errorf("failed to marshal choices array")
+		}
+		choicesStr := string(choicesJson)
+		choices = &choicesStr
+	}
+
+	res, err := r.db.ExecContext(ctx, insertExamQuestion, examID, question.DefinitionId, position, question.Type, question.Prompt, choices, question.Answer)
+	if err != nil {
+		return 0, fmt.Errorf("failed to exec: %w", err)
+	}
+
+	lastInsertId, err := res.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get last insert id: %w", err)
+	}
+
+	return lastInsertId, nil
+}
+
+func (r *examRepo) SaveResponse(ctx context.Context, questionID int64, response *string, correct *bool) error {
+	if _, err := r.db.ExecContext(ctx, saveExamResponse, response, correct, questionID); err != nil {
+		return fmt.Errorf("failed to exec: %w", err)
+	}
+	return nil
+}
+
+func (r *examRepo) Submit(ctx context.Context, examID int64, score int, timedOut bool, submittedAt time.Time) (bool, error) {
+	res, err := r.db.ExecContext(ctx, submitExam, score, timedOut, submittedAt, examID)
+	if err != nil {
+		return false, fmt.Errorf("failed to exec: %w", err)
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rowsAffected == 1, nil
+}
@@ -0,0 +1,232 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/go-sql-driver/mysql"
+
+	"ailingo/internal/domain"
+)
+
+// getFolders queries for all folders of the given user with the number of study sets in each of them.
+const getFolders = `
+SELECT folder.id,
+       folder.user_id,
+       folder.parent_id,
+       folder.name,
+       folder.position,
+       (SELECT COUNT(*) FROM folder_study_set WHERE folder_study_set.folder_id = folder.id),
+       folder.created_at
+FROM folder
+WHERE folder.user_id = ?
+ORDER BY folder.position, folder.id
+`
+
+// getFolder queries for the given folder with the number of study sets in it.
+const getFolder = `
+SELECT folder.id,
+       folder.user_id,
+       folder.parent_id,
+       folder.name,
+       folder.position,
+       (SELECT COUNT(*) FROM folder_study_set WHERE folder_study_set.folder_id = folder.id),
+       folder.created_at
+FROM folder
+WHERE folder.id = ?
+`
+
+const insertFolder = `
+INSERT INTO folder (user_id, parent_id, name, position)
+VALUES (?, ?, ?, ?)
+`
+
+const updateFolder = `
+UPDATE folder
+SET parent_id = ?,
+    name      = ?,
+    position  = ?
+WHERE id = ?
+`
+
+const updateFolderPosition = `
+UPDATE folder
+SET position = ?
+WHERE id = ?
+`
+
+const deleteFolderStudySets = `
+DELETE
+FROM folder_study_set
+WHERE folder_id = ?
+`
+
+const deleteFolder = `
+DELETE
+FROM folder
+WHERE id = ?
+`
+
+// getFolderStudySetIds queries for ids of the study sets in the given folder in order.
+const getFolderStudySetIds = `
+SELECT study_set_id
+FROM folder_study_set
+WHERE folder_id = ?
+ORDER BY position, study_set_id
+`
+
+const insertFolderStudySet = `
+INSERT INTO folder_study_set (folder_id, study_set_id, position)
+VALUES (?, ?, ?)
+`
+
+const updateFolderStudySetPosition = `
+UPDATE folder_study_set
+SET position = ?
+WHERE folder_id = ?
+  AND study_set_id = ?
+`
+
+const deleteFolderStudySet = `
+DELETE
+FROM folder_study_set
+WHERE folder_id = ?
+  AND study_set_id = ?
+`
+
+type folderRepo struct {
+	db DBTX
+}
+
+func NewFolderRepo(db DBTX) domain.FolderRepo {
+	return &folderRepo{
+		db: db,
+	}
+}
+
+func (r *folderRepo) GetAll(ctx context.Context, userID string) ([]*domain.FolderRow, error) {
+	folders := make([]*domain.FolderRow, 0)
+
+	rows, err := r.db.QueryContext(ctx, getFolders, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var folder domain.FolderRow
+		if err := rows.Scan(
+			&folder.Id, &folder.UserId, &folder.ParentId, &folder.Name, &folder.Position, &folder.StudySetCount, &folder.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan: %w", err)
+		}
+		folders = append(folders, &folder)
+	}
+
+	return folders, nil
+}
+
+func (r *folderRepo) Get(ctx context.Context, folderID int64) (*domain.FolderRow, error) {
+	var folder domain.FolderRow
+
+	if err := r.db.QueryRowContext(ctx, getFolder, folderID).Scan(
+		&folder.Id, &folder.UserId, &folder.ParentId, &folder.Name, &folder.Position, &folder.StudySetCount, &folder.CreatedAt,
+	); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to scan: %w", err)
+	}
+
+	return &folder, nil
+}
+
+func (r *folderRepo) Insert(ctx context.Context, userID string, parentID *int64, name string, position int) (int64, error) {
+	res, err := r.db.ExecContext(ctx, insertFolder, userID, parentID, name, position)
+	if err != nil {
+		return 0, fmt.Errorf("failed to exec: %w", err)
+	}
+
+	lastInsertId, err := res.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get last insert id: %w", err)
+	}
+
+	return lastInsertId, nil
+}
+
+func (r *folderRepo) Update(ctx context.Context, folderID int64, parentID *int64, name string, position int) error {
+	if _, err := r.db.ExecContext(ctx, updateFolder, parentID, name, position, folderID); err != nil {
+		return fmt.Errorf("failed to exec: %w", err)
+	}
+	return nil
+}
+
+func (r *folderRepo) SetPosition(ctx context.Context, folderID int64, position int) error {
+	if _, err := r.db.ExecContext(ctx, updateFolderPosition, position, folderID); err != nil {
+		return fmt.Errorf("failed to exec: %w", err)
+	}
+	return nil
+}
+
+func (r *folderRepo) Delete(ctx context.Context, folderID int64) error {
+	if _, err := r.db.ExecContext(ctx, deleteFolderStudySets, folderID); err != nil {
+		return fmt.Errorf("failed to exec: %w", err)
+	}
+
+	if _, err := r.db.ExecContext(ctx, deleteFolder, folderID); err != nil {
+		return fmt.Errorf("failed to exec: %w", err)
+	}
+
+	return nil
+}
+
+func (r *folderRepo) GetStudySetIds(ctx context.Context, folderID int64) ([]int64, error) {
+	studySetIDs := make([]int64, 0)
+
+	rows, err := r.db.QueryContext(ctx, getFolderStudySetIds, folderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var studySetID int64
+		if err := rows.Scan(&studySetID); err != nil {
+			return nil, fmt.Errorf("failed to scan: %w", err)
+		}
+		studySetIDs = append(studySetIDs, studySetID)
+	}
+
+	return studySetIDs, nil
+}
+
+func (r *folderRepo) AddStudySet(ctx context.Context, folderID int64, studySetID int64, position int) error {
+	if _, err := r.db.ExecContext(ctx, insertFolderStudySet, folderID, studySetID, position); err != nil {
+		var mysqlerr *mysql.MySQLError
+		if errors.As(err, &mysqlerr) {
+			// 1062 error number stands for duplicate entry code
+			if mysqlerr.Number == 1062 {
+				return ErrDuplicateRow
+			}
+		}
+		return fmt.Errorf("failed to exec: %w", err)
+	}
+	return nil
+}
+
+func (r *folderRepo) SetStudySetPosition(ctx context.Context, folderID int64, studySetID int64, position int) error {
+	if _, err := r.db.ExecContext(ctx, updateFolderStudySetPosition, position, folderID, studySetID); err != nil {
+		return fmt.Errorf("failed to exec: %w", err)
+	}
+	return nil
+}
+
+func (r *folderRepo) RemoveStudySet(ctx context.Context, folderID int64, studySetID int64) error {
+	if _, err := r.db.ExecContext(ctx, deleteFolderStudySet, folderID, studySetID); err != nil {
+		return fmt.Errorf("failed to exec: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,164 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"ailingo/internal/domain"
+)
+
+// getPlacementTest queries for a placement test with the given id.
+const getPlacementTest = `
+SELECT id, user_id, ability, std_err, level, started_at, finished_at
+FROM placement_test
+WHERE id = ?
+`
+
+// getPlacementQuestions queries for all questions of the given placement test in the order they were asked.
+const getPlacementQuestions = `
+SELECT id, item_id, word, level, choices, correct_index, answer_index
+FROM placement_question
+WHERE test_id = ?
+ORDER BY position
+`
+
+// insertPlacementTest inserts a new placement test.
+const insertPlacementTest = `
+INSERT INTO placement_test (user_id)
+VALUES (?)
+`
+
+// insertPlacementQuestion inserts a new question into the given placement test.
+const insertPlacementQuestion = `
+INSERT INTO placement_question (test_id, item_id, position, word, level, choices, correct_index)
+VALUES (?, ?, ?, ?, ?, ?, ?)
+`
+
+// savePlacementAnswer stores the choice made by the user.
+const savePlacementAnswer = `
+UPDATE placement_question
+SET answer_index = ?
+WHERE id = ?
+`
+
+// finishPlacementTest stores the result of the placement test.
+const finishPlacementTest = `
+UPDATE placement_test
+SET ability     = ?,
+    std_err     = ?,
+    level       = ?,
+    finished_at = NOW()
+WHERE id = ?
+`
+
+type placementRepo struct {
+	db DBTX
+}
+
+func NewPlacementRepo(db DBTX) domain.PlacementRepo {
+	return &placementRepo{
+		db: db,
+	}
+}
+
+func (r *placementRepo) Get(ctx context.Context, testID int64) (*domain.PlacementTestRow, error) {
+	var test domain.PlacementTestRow
+	if err := r.db.QueryRowContext(ctx, getPlacementTest, testID).Scan(
+		&test.Id, &test.UserId, &test.Ability, &test.StdErr, &test.Level, &test.StartedAt, &test.FinishedAt,
+	); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to scan: %w", err)
+	}
+	return &test, nil
+}
+
+func (r *placementRepo) GetQuestions(ctx context.Context, testID int64) ([]*domain.PlacementQuestionRow, error) {
+	questions := make([]*domain.PlacementQuestionRow, 0)
+
+	rows, err := r.db.QueryContext(ctx, getPlacementQuestions, testID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var question domain.PlacementQuestionRow
+		var choicesRaw json.RawMessage
+
+		if err := rows.Scan(
+			&question.Id, &question.ItemId, &question.Word, &question.Level, &choicesRaw, &question.CorrectIndex, &question.AnswerIndex,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan: %w", err)
+		}
+
+		if err := json.Unmarshal(choicesRaw, &question.Choices); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal choices: %w", err)
+		}
+
+		questions = append(questions, &question)
+	}
+
+	return questions, nil
+}
+
+func (r *placementRepo) Insert(ctx context.Context, userID string) (int64, error) {
+	res, err := r.db.ExecContext(ctx, insertPlacementTest, userID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to exec: %w", err)
+	}
+
+	lastInsertId, err := res.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get last insert id: %w", err)
+	}
+
+	return lastInsertId, nil
+}
+
+func (r *placementRepo) InsertQuestion(ctx context.Context, testID int64, position int, question *domain.PlacementQuestionRow) (int64, error) {
+	choicesJson, err := json.Marshal(question.Choices)
+	if err != nil {
+		return 0, fmt.Errorf("failed to marshal choices array")
+	}
+
+	res, err := r.db.ExecContext(
+		ctx,
+		insertPlacementQuestion,
+		testID,
+		question.ItemId,
+		position,
+		question.Word,
+		question.Level,
+		string(choicesJson),
+		question.CorrectIndex,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to exec: %w", err)
+	}
+
+	lastInsertId, err := res.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get last insert id: %w", err)
+	}
+
+	return lastInsertId, nil
+}
+
+func (r *placementRepo) SaveAnswer(ctx context.Context, questionID int64, answerIndex int) error {
+	if _, err := r.db.ExecContext(ctx, savePlacementAnswer, answerIndex, questionID); err != nil {
+		return fmt.Errorf("failed to exec: %w", err)
+	}
+	return nil
+}
+
+func (r *placementRepo) Finish(ctx context.Context, testID int64, result *domain.PlacementResult) error {
+	if _, err := r.db.ExecContext(ctx, finishPlacementTest, result.Ability, result.StdErr, result.Level, testID); err != nil {
+		return fmt.Errorf("failed to exec: %w", err)
+	}
+	return nil
+}
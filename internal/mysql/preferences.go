@@ -0,0 +1,65 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"ailingo/internal/domain"
+)
+
+// getPreferences queries for preferences of the given user.
+const getPreferences = `
+SELECT timezone, daily_goal_type, daily_goal_target, cefr_level
+FROM user_preferences
+WHERE user_id = ?
+`
+
+// upsertPreferences inserts or replaces preferences of the given user.
+const upsertPreferences = `
+INSERT INTO user_preferences (user_id, timezone, daily_goal_type, daily_goal_target, cefr_level)
+VALUES (?, ?, ?, ?, ?)
+ON DUPLICATE KEY UPDATE timezone          = VALUES(timezone),
+                        daily_goal_type   = VALUES(daily_goal_type),
+                        daily_goal_target = VALUES(daily_goal_target),
+                        cefr_level        = VALUES(cefr_level)
+`
+
+type preferencesRepo struct {
+	db DBTX
+}
+
+func NewPreferencesRepo(db DBTX) domain.PreferencesRepo {
+	return &preferencesRepo{
+		db: db,
+	}
+}
+
+func (r *preferencesRepo) Get(ctx context.Context, userID string) (*domain.UserPreferences, error) {
+	var preferences domain.UserPreferences
+	if err := r.db.QueryRowContext(ctx, getPreferences, userID).Scan(
+		&preferences.Timezone, &preferences.DailyGoalType, &preferences.DailyGoalTarget, &preferences.CefrLevel,
+	); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to scan: %w", err)
+	}
+	return &preferences, nil
+}
+
+func (r *preferencesRepo) Upsert(ctx context.Context, userID string, preferences *domain.UserPreferences) error {
+	if _, err := r.db.ExecContext(
+		ctx,
+		upsertPreferences,
+		userID,
+		preferences.Timezone,
+		preferences.DailyGoalType,
+		preferences.DailyGoalTarget,
+		preferences.CefrLevel,
+	); err != nil {
+		return fmt.Errorf("failed to exec: %w", err)
+	}
+	return nil
+}
@@ -21,6 +21,13 @@ WHERE user_id = ?
   AND study_set_id = ?
 `
 
+// refreshStudySetStars recounts stars of the study set, which are kept on it for sorting.
+const refreshStudySetStars = `
+UPDATE study_set
+SET stars = (SELECT COUNT(*) FROM star WHERE study_set_id = ?)
+WHERE id = ?
+`
+
 type profileRepo struct {
 	db DBTX
 }
@@ -42,12 +49,19 @@ func (r *profileRepo) InsertStar(ctx context.Context, userID string, studySetID
 		}
 		return fmt.Errorf("failed to exec: %w", err)
 	}
-	return nil
+	return r.refreshStars(ctx, studySetID)
 }
 
 func (r *profileRepo) DeleteStar(ctx context.Context, userID string, studySetID int64) error {
 	if _, err := r.db.ExecContext(ctx, deleteStar, userID, studySetID); err != nil {
 		return fmt.Errorf("failed to exec: %w", err)
 	}
+	return r.refreshStars(ctx, studySetID)
+}
+
+func (r *profileRepo) refreshStars(ctx context.Context, studySetID int64) error {
+	if _, err := r.db.ExecContext(ctx, refreshStudySetStars, studySetID, studySetID); err != nil {
+		return fmt.Errorf("failed to exec a refresh query: %w", err)
+	}
 	return nil
 }
@@ -0,0 +1,158 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"ailingo/internal/domain"
+)
+
+// getQuiz queries for a quiz with the given id.
+const getQuiz = `
+SELECT id, user_id, study_set_id, direction, score, created_at, submitted_at
+FROM quiz
+WHERE id = ?
+`
+
+// getQuizQuestions queries for all questions of the given quiz in their original order.
+const getQuizQuestions = `
+SELECT id, definition_id, prompt, choices, correct_index, answer_index
+FROM quiz_question
+WHERE quiz_id = ?
+ORDER BY position
+`
+
+// insertQuiz inserts a new quiz.
+const insertQuiz = `
+INSERT INTO quiz (user_id, study_set_id, direction)
+VALUES (?, ?, ?)
+`
+
+// insertQuizQuestion inserts a new question into the given quiz.
+const insertQuizQuestion = `
+INSERT INTO quiz_question (quiz_id, definition_id, position, prompt, choices, correct_index)
+VALUES (?, ?, ?, ?, ?, ?)
+`
+
+// saveQuizAnswer stores the choice made by the user.
+const saveQuizAnswer = `
+UPDATE quiz_question
+SET answer_index = ?
+WHERE id = ?
+`
+
+// submitQuiz marks the quiz as submitted, unless it has already been submitted.
+const submitQuiz = `
+UPDATE quiz
+SET score        = ?,
+    submitted_at = NOW()
+WHERE id = ?
+  AND submitted_at IS NULL
+`
+
+type quizRepo struct {
+	db DBTX
+}
+
+func NewQuizRepo(db DBTX) domain.QuizRepo {
+	return &quizRepo{
+		db: db,
+	}
+}
+
+func (r *quizRepo) Get(ctx context.Context, quizID int64) (*domain.QuizRow, error) {
+	var quiz domain.QuizRow
+	if err := r.db.QueryRowContext(ctx, getQuiz, quizID).Scan(
+		&quiz.Id, &quiz.UserId, &quiz.StudySetId, &quiz.Direction, &quiz.Score, &quiz.CreatedAt, &quiz.SubmittedAt,
+	); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to scan: %w", err)
+	}
+	return &quiz, nil
+}
+
+func (r *quizRepo) GetQuestions(ctx context.Context, quizID int64) ([]*domain.QuizQuestionRow, error) {
+	questions := make([]*domain.QuizQuestionRow, 0)
+
+	rows, err := r.db.QueryContext(ctx, getQuizQuestions, quizID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var question domain.QuizQuestionRow
+		var choicesRaw json.RawMessage
+
+		if err := rows.Scan(&question.Id, &question.DefinitionId, &question.Prompt, &choicesRaw, &question.CorrectIndex, &question.AnswerIndex); err != nil {
+			return nil, fmt.Errorf("failed to scan: %w", err)
+		}
+
+		if err := json.Unmarshal(choicesRaw, &question.Choices); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal choices: %w", err)
+		}
+
+		questions = append(questions, &question)
+	}
+
+	return questions, nil
+}
+
+func (r *quizRepo) Insert(ctx context.Context, userID string, studySetID int64, direction string) (int64, error) {
+	res, err := r.db.ExecContext(ctx, insertQuiz, userID, studySetID, direction)
+	if err != nil {
+		return 0, fmt.Errorf("failed to exec: %w", err)
+	}
+
+	lastInsertId, err := res.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get last insert id: %w", err)
+	}
+
+	return lastInsertId, nil
+}
+
+func (r *quizRepo) InsertQuestion(ctx context.Context, quizID int64, position int, question *domain.QuizQuestionRow) (int64, error) {
+	choicesJson, err := json.Marshal(question.Choices)
+	if err != nil {
+		return 0, fmt.Errorf("failed to marshal choices array")
+	}
+
+	res, err := r.db.ExecContext(ctx, insertQuizQuestion, quizID, question.DefinitionId, position, question.Prompt, string(choicesJson), question.CorrectIndex)
+	if err != nil {
+		return 0, fmt.Errorf("failed to exec: %w", err)
+	}
+
+	lastInsertId, err := res.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get last insert id: %w", err)
+	}
+
+	return lastInsertId, nil
+}
+
+func (r *quizRepo) SaveAnswer(ctx context.Context, questionID int64, choiceIndex int) error {
+	if _, err := r.db.ExecContext(ctx, saveQuizAnswer, choiceIndex, questionID); err != nil {
+		return fmt.Errorf("failed to exec: %w", err)
+	}
+	return nil
+}
+
+func (r *quizRepo) Submit(ctx context.Context, quizID int64, score int) (bool, error) {
+	res, err := r.db.ExecContext(ctx, submitQuiz, score, quizID)
+	if err != nil {
+		return false, fmt.Errorf("failed to exec: %w", err)
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rowsAffected == 1, nil
+}
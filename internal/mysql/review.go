@@ -0,0 +1,408 @@
+package mysql
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"ailingo/internal/domain"
+)
+
+// getReviewStatesForStudySet queries for review states of all definitions from the given study set.
+const getReviewStatesForStudySet = `
+SELECT definition_id, ease, interval_days, repetitions, lapses, due_at, last_reviewed_at
+FROM review_state
+WHERE user_id = ?
+  AND study_set_id = ?
+`
+
+// upsertReviewState inserts a new review state or replaces the existing one.
+const upsertReviewState = `
+INSERT INTO review_state (user_id, definition_id, study_set_id, ease, interval_days, repetitions, lapses, due_at, last_reviewed_at)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+ON DUPLICATE KEY UPDATE ease             = VALUES(ease),
+                        interval_days    = VALUES(interval_days),
+                        repetitions      = VALUES(repetitions),
+                        lapses           = VALUES(lapses),
+                        due_at           = VALUES(due_at),
+                        last_reviewed_at = VALUES(last_reviewed_at)
+`
+
+// getDueDefinitions queries for definitions due at the given time across all study sets the user can still see.
+const getDueDefinitions = `
+SELECT review_state.definition_id,
+       review_state.ease,
+       review_state.interval_days,
+       review_state.repetitions,
+       review_state.lapses,
+       review_state.due_at,
+       review_state.last_reviewed_at,
+       definition.phrase,
+       definition.meaning,
+       definition.sentences,
+       study_set.id,
+       study_set.name,
+       study_set.description,
+       study_set.phrase_language,
+       study_set.definition_language,
+       study_set.icon,
+       study_set.color,
+       study_set.cefr_level,
+       study_set.visibility,
+       study_set.category,
+       user.id,
+       user.username,
+       user.image_url
+FROM review_state
+         INNER JOIN definition ON definition.id = review_state.definition_id
+         INNER JOIN study_set ON study_set.id = definition.study_set_id
+         INNER JOIN user ON user.id = study_set.author_id
+WHERE review_state.user_id = ?
+  AND review_state.due_at <= ?
+  AND (study_set.visibility <> 'PRIVATE'
+    OR study_set.author_id = ?
+    OR study_set.id IN (SELECT study_set_id FROM collaborator WHERE user_id = ?))
+ORDER BY review_state.due_at
+LIMIT ?
+`
+
+// getNewDefinitions queries for never reviewed definitions from study sets the user has studied and can still see.
+const getNewDefinitions = `
+SELECT definition.id,
+       definition.phrase,
+       definition.meaning,
+       definition.sentences,
+       study_set.id,
+       study_set.name,
+       study_set.description,
+       study_set.phrase_language,
+       study_set.definition_language,
+       study_set.icon,
+       study_set.color,
+       study_set.cefr_level,
+       study_set.visibility,
+       study_set.category,
+       user.id,
+       user.username,
+       user.image_url
+FROM (SELECT study_set_id, MAX(last_session_at) AS last_session_at
+      FROM study_session
+      WHERE user_id = ?
+      GROUP BY study_set_id) AS studied
+         INNER JOIN study_set ON study_set.id = studied.study_set_id
+         INNER JOIN user ON user.id = study_set.author_id
+         INNER JOIN definition ON definition.study_set_id = study_set.id
+         LEFT JOIN review_state ON review_state.user_id = ?
+    AND review_state.definition_id = definition.id
+WHERE review_state.definition_id IS NULL
+  AND (study_set.visibility <> 'PRIVATE'
+    OR study_set.author_id = ?
+    OR study_set.id IN (SELECT study_set_id FROM collaborator WHERE user_id = ?))
+ORDER BY studied.last_session_at DESC, definition.id
+LIMIT ?
+`
+
+// insertReviewLog appends a graded answer to the review log.
+const insertReviewLog = `
+INSERT INTO review_log (user_id, definition_id, study_set_id, grade, response_time_ms, answered_at)
+VALUES (?, ?, ?, ?, ?, ?)
+`
+
+// insertReviewEvent appends a review made by an offline client to the review log, unless it is already there.
+const insertReviewEvent = `
+INSERT INTO review_log (user_id, definition_id, study_set_id, grade, response_time_ms, answered_at, client_event_id)
+VALUES (?, ?, ?, ?, ?, ?, ?)
+ON DUPLICATE KEY UPDATE id = id
+`
+
+// getReviewLogForDefinition queries for all review log entries of the given definition.
+// Ties are broken by client event id first, so that the order does not depend on upload order.
+const getReviewLogForDefinition = `
+SELECT id, definition_id, study_set_id, grade, response_time_ms, answered_at
+FROM review_log
+WHERE user_id = ?
+  AND definition_id = ?
+ORDER BY answered_at, client_event_id, id
+`
+
+// getWeakDefinitions queries for immature definitions with too many lapses or too low accuracy
+// across all study sets the user can still see.
+const getWeakDefinitions = `
+SELECT definition.id,
+       definition.phrase,
+       definition.meaning,
+       definition.sentences,
+       definition.study_set_id,
+       review_state.lapses,
+       answers.correct / answers.total AS accuracy
+FROM review_state
+         INNER JOIN definition ON definition.id = review_state.definition_id
+         INNER JOIN study_set ON study_set.id = definition.study_set_id
+         LEFT JOIN (SELECT definition_id, COUNT(*) AS total, SUM(grade >= 3) AS correct
+                    FROM review_log
+                    WHERE user_id = ?
+                    GROUP BY definition_id) AS answers ON answers.definition_id = review_state.definition_id
+WHERE review_state.user_id = ?
+  AND review_state.interval_days < ?
+  AND (review_state.lapses >= ? OR (answers.total >= ? AND answers.correct / answers.total <= ?))
+  AND (study_set.visibility <> 'PRIVATE'
+    OR study_set.author_id = ?
+    OR study_set.id IN (SELECT study_set_id FROM collaborator WHERE user_id = ?))
+ORDER BY review_state.lapses DESC, accuracy, definition.id
+LIMIT ?
+`
+
+// getReviewLogForStudySet queries for review log entries for the given study set answered since the given time.
+const getReviewLogForStudySet = `
+SELECT id, definition_id, study_set_id, grade, response_time_ms, answered_at
+FROM review_log
+WHERE user_id = ?
+  AND study_set_id = ?
+  AND answered_at >= ?
+ORDER BY answered_at
+`
+
+type reviewRepo struct {
+	db DBTX
+}
+
+func NewReviewRepo(db DBTX) domain.ReviewRepo {
+	return &reviewRepo{
+		db: db,
+	}
+}
+
+func (r *reviewRepo) GetStatesFor(ctx context.Context, userID string, studySetID int64) ([]*domain.ReviewState, error) {
+	states := make([]*domain.ReviewState, 0)
+
+	rows, err := r.db.QueryContext(ctx, getReviewStatesForStudySet, userID, studySetID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var state domain.ReviewState
+		if err := rows.Scan(&state.DefinitionId, &state.Ease, &state.IntervalDays, &state.Repetitions, &state.Lapses, &state.DueAt, &state.LastReviewedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan: %w", err)
+		}
+		states = append(states, &state)
+	}
+
+	return states, nil
+}
+
+func (r *reviewRepo) GetDue(ctx context.Context, userID string, now time.Time, limit int) ([]*domain.QueueCard, error) {
+	cards := make([]*domain.QueueCard, 0)
+
+	rows, err := r.db.QueryContext(ctx, getDueDefinitions, userID, now, userID, userID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var card domain.QueueCard
+		var state domain.ReviewState
+		var sentencesRaw json.RawMessage
+
+		if err := rows.Scan(
+			// review state
+			&state.DefinitionId, &state.Ease, &state.IntervalDays, &state.Repetitions, &state.Lapses, &state.DueAt, &state.LastReviewedAt,
+			// definition
+			&card.Definition.Phrase, &card.Definition.Meaning, &sentencesRaw,
+			// study set
+			&card.StudySet.Id, &card.StudySet.Name, &card.StudySet.Description, &card.StudySet.PhraseLanguage, &card.StudySet.DefinitionLanguage, &card.StudySet.Icon, &card.StudySet.Color, &card.StudySet.CefrLevel, &card.StudySet.Visibility, &card.StudySet.Category,
+			// author
+			&card.StudySet.Author.Id, &card.StudySet.Author.Username, &card.StudySet.Author.ImageURL,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan: %w", err)
+		}
+
+		if err := json.Unmarshal(sentencesRaw, &card.Definition.Sentences); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal sentences: %w", err)
+		}
+
+		card.Definition.Id = state.DefinitionId
+		card.State = &state
+		cards = append(cards, &card)
+	}
+
+	return cards, nil
+}
+
+func (r *reviewRepo) GetNew(ctx context.Context, userID string, limit int) ([]*domain.QueueCard, error) {
+	cards := make([]*domain.QueueCard, 0)
+
+	rows, err := r.db.QueryContext(ctx, getNewDefinitions, userID, userID, userID, userID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var card domain.QueueCard
+		var sentencesRaw json.RawMessage
+
+		if err := rows.Scan(
+			// definition
+			&card.Definition.Id, &card.Definition.Phrase, &card.Definition.Meaning, &sentencesRaw,
+			// study set
+			&card.StudySet.Id, &card.StudySet.Name, &card.StudySet.Description, &card.StudySet.PhraseLanguage, &card.StudySet.DefinitionLanguage, &card.StudySet.Icon, &card.StudySet.Color, &card.StudySet.CefrLevel, &card.StudySet.Visibility, &card.StudySet.Category,
+			// author
+			&card.StudySet.Author.Id, &card.StudySet.Author.Username, &card.StudySet.Author.ImageURL,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan: %w", err)
+		}
+
+		if err := json.Unmarshal(sentencesRaw, &card.Definition.Sentences); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal sentences: %w", err)
+		}
+
+		cards = append(cards, &card)
+	}
+
+	return cards, nil
+}
+
+func (r *reviewRepo) UpsertState(ctx context.Context, userID string, studySetID int64, state *domain.ReviewState) error {
+	if _, err := r.db.ExecContext(
+		ctx,
+		upsertReviewState,
+		userID,
+		state.DefinitionId,
+		studySetID,
+		state.Ease,
+		state.IntervalDays,
+		state.Repetitions,
+		state.Lapses,
+		state.DueAt,
+		state.LastReviewedAt,
+	); err != nil {
+		return fmt.Errorf("failed to exec: %w", err)
+	}
+	return nil
+}
+
+func (r *reviewRepo) InsertLog(ctx context.Context, userID string, studySetID int64, gradeData *domain.ReviewGradeData) error {
+	if _, err := r.db.ExecContext(
+		ctx,
+		insertReviewLog,
+		userID,
+		gradeData.DefinitionId,
+		studySetID,
+		gradeData.Grade,
+		gradeData.ResponseTimeMs,
+		gradeData.AnsweredAt,
+	); err != nil {
+		return fmt.Errorf("failed to exec: %w", err)
+	}
+	return nil
+}
+
+func (r *reviewRepo) GetLogFor(ctx context.Context, userID string, studySetID int64, since time.Time) ([]*domain.ReviewLogEntry, error) {
+	entries := make([]*domain.ReviewLogEntry, 0)
+
+	rows, err := r.db.QueryContext(ctx, getReviewLogForStudySet, userID, studySetID, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var entry domain.ReviewLogEntry
+		if err := rows.Scan(&entry.Id, &entry.DefinitionId, &entry.StudySetId, &entry.Grade, &entry.ResponseTimeMs, &entry.AnsweredAt); err != nil {
+			return nil, fmt.Errorf("failed to scan: %w", err)
+		}
+		entries = append(entries, &entry)
+	}
+
+	return entries, nil
+}
+
+func (r *reviewRepo) GetWeak(ctx context.Context, userID string, criteria *domain.WeakDefinitionCriteria, limit int) ([]*domain.WeakDefinition, error) {
+	definitions := make([]*domain.WeakDefinition, 0)
+
+	rows, err := r.db.QueryContext(
+		ctx,
+		getWeakDefinitions,
+		userID,
+		userID,
+		criteria.MaxIntervalDays,
+		criteria.MinLapses,
+		criteria.MinAnswers,
+		criteria.MaxAccuracy,
+		userID,
+		userID,
+		limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var definition domain.WeakDefinition
+		var sentencesRaw json.RawMessage
+
+		if err := rows.Scan(
+			&definition.Id, &definition.Phrase, &definition.Meaning, &sentencesRaw,
+			&definition.StudySetId, &definition.Lapses, &definition.Accuracy,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan: %w", err)
+		}
+
+		if err := json.Unmarshal(sentencesRaw, &definition.Sentences); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal sentences: %w", err)
+		}
+
+		definitions = append(definitions, &definition)
+	}
+
+	return definitions, nil
+}
+
+func (r *reviewRepo) InsertEvent(ctx context.Context, userID string, studySetID int64, eventID string, gradeData *domain.ReviewGradeData) (bool, error) {
+	res, err := r.db.ExecContext(
+		ctx,
+		insertReviewEvent,
+		userID,
+		gradeData.DefinitionId,
+		studySetID,
+		gradeData.Grade,
+		gradeData.ResponseTimeMs,
+		gradeData.AnsweredAt,
+		eventID,
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to exec: %w", err)
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rowsAffected == 1, nil
+}
+
+func (r *reviewRepo) GetDefinitionLog(ctx context.Context, userID string, definitionID int64) ([]*domain.ReviewLogEntry, error) {
+	entries := make([]*domain.ReviewLogEntry, 0)
+
+	rows, err := r.db.QueryContext(ctx, getReviewLogForDefinition, userID, definitionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var entry domain.ReviewLogEntry
+		if err := rows.Scan(&entry.Id, &entry.DefinitionId, &entry.StudySetId, &entry.Grade, &entry.ResponseTimeMs, &entry.AnsweredAt); err != nil {
+			return nil, fmt.Errorf("failed to scan: %w", err)
+		}
+		entries = append(entries, &entry)
+	}
+
+	return entries, nil
+}
@@ -0,0 +1,200 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"ailingo/internal/domain"
+)
+
+// getRevisionPage queries for revisions of the given study set older than the cursor.
+// Revisions stay in the history even if their author has deleted the account.
+const getRevisionPage = `
+SELECT revision.id,
+       revision.study_set_id,
+       revision.author_id,
+       COALESCE(user.username, ''),
+       COALESCE(user.image_url, ''),
+       revision.target,
+       revision.definition_id,
+       revision.before_snapshot,
+       revision.after_snapshot,
+       revision.created_at
+FROM revision
+         LEFT JOIN user ON user.id = revision.author_id
+WHERE revision.study_set_id = ?
+  AND (? = 0 OR revision.id < ?)
+ORDER BY revision.id DESC
+LIMIT ?
+`
+
+// getRevision queries for the given revision of the given study set.
+const getRevision = `
+SELECT revision.id,
+       revision.study_set_id,
+       revision.author_id,
+       COALESCE(user.username, ''),
+       COALESCE(user.image_url, ''),
+       revision.target,
+       revision.definition_id,
+       revision.before_snapshot,
+       revision.after_snapshot,
+       revision.created_at
+FROM revision
+         LEFT JOIN user ON user.id = revision.author_id
+WHERE revision.study_set_id = ?
+  AND revision.id = ?
+`
+
+// getRevisionsNewerThan queries for all revisions of the given study set made after the given revision.
+const getRevisionsNewerThan = `
+SELECT revision.id,
+       revision.study_set_id,
+       revision.author_id,
+       COALESCE(user.username, ''),
+       COALESCE(user.image_url, ''),
+       revision.target,
+       revision.definition_id,
+       revision.before_snapshot,
+       revision.after_snapshot,
+       revision.created_at
+FROM revision
+         LEFT JOIN user ON user.id = revision.author_id
+WHERE revision.study_set_id = ?
+  AND revision.id > ?
+ORDER BY revision.id DESC
+`
+
+// insertRevision records a new change of the study set.
+const insertRevision = `
+INSERT INTO revision (study_set_id, author_id, target, definition_id, before_snapshot, after_snapshot)
+VALUES (?, ?, ?, ?, ?, ?)
+`
+
+type revisionRepo struct {
+	db DBTX
+}
+
+func NewRevisionRepo(db DBTX) domain.RevisionRepo {
+	return &revisionRepo{
+		db: db,
+	}
+}
+
+func (r *revisionRepo) GetPage(ctx context.Context, studySetID int64, cursor int64, limit int) ([]*domain.RevisionRow, error) {
+	return r.query(ctx, getRevisionPage, studySetID, cursor, cursor, limit)
+}
+
+func (r *revisionRepo) Get(ctx context.Context, studySetID int64, revisionID int64) (*domain.RevisionRow, error) {
+	revisions, err := r.query(ctx, getRevision, studySetID, revisionID)
+	if err != nil {
+		return nil, err
+	}
+	if len(revisions) == 0 {
+		return nil, nil
+	}
+
+	return revisions[0], nil
+}
+
+func (r *revisionRepo) GetNewerThan(ctx context.Context, studySetID int64, revisionID int64) ([]*domain.RevisionRow, error) {
+	return r.query(ctx, getRevisionsNewerThan, studySetID, revisionID)
+}
+
+func (r *revisionRepo) Insert(ctx context.Context, studySetID int64, authorID string, change *domain.RevisionChange) error {
+	var before, after sql.NullString
+	var err error
+	if change.Target == domain.RevisionTargetStudySet {
+		before, err = marshalSnapshot(change.StudySetBefore)
+		if err == nil {
+			after, err = marshalSnapshot(change.StudySetAfter)
+		}
+	} else {
+		before, err = marshalSnapshot(change.DefinitionBefore)
+		if err == nil {
+			after, err = marshalSnapshot(change.DefinitionAfter)
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	if _, err := r.db.ExecContext(ctx, insertRevision, studySetID, authorID, change.Target, change.DefinitionId, before, after); err != nil {
+		return fmt.Errorf("failed to exec: %w", err)
+	}
+
+	return nil
+}
+
+// query runs the given revision query and scans the snapshots according to the revision target.
+func (r *revisionRepo) query(ctx context.Context, query string, args ...any) ([]*domain.RevisionRow, error) {
+	revisions := make([]*domain.RevisionRow, 0)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var revision domain.RevisionRow
+		var beforeRaw, afterRaw []byte
+
+		if err := rows.Scan(
+			&revision.Id, &revision.StudySetId,
+			&revision.Author.Id, &revision.Author.Username, &revision.Author.ImageURL,
+			&revision.Target, &revision.DefinitionId, &beforeRaw, &afterRaw, &revision.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan: %w", err)
+		}
+
+		if revision.Target == domain.RevisionTargetStudySet {
+			err = errors.Join(
+				unmarshalSnapshot(beforeRaw, &revision.StudySetBefore),
+				unmarshalSnapshot(afterRaw, &revision.StudySetAfter),
+			)
+		} else {
+			err = errors.Join(
+				unmarshalSnapshot(beforeRaw, &revision.DefinitionBefore),
+				unmarshalSnapshot(afterRaw, &revision.DefinitionAfter),
+			)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		revisions = append(revisions, &revision)
+	}
+
+	return revisions, nil
+}
+
+// marshalSnapshot encodes the snapshot as JSON. Missing snapshots are stored as NULL.
+func marshalSnapshot[T any](snapshot *T) (sql.NullString, error) {
+	if snapshot == nil {
+		return sql.NullString{}, nil
+	}
+
+	snapshotJson, err := json.Marshal(snapshot)
+	if err != nil {
+		return sql.NullString{}, fmt.Errorf("failed to marshal snapshot: %w", err)
+	}
+
+	return sql.NullString{String: string(snapshotJson), Valid: true}, nil
+}
+
+func unmarshalSnapshot[T any](raw []byte, snapshot **T) error {
+	if raw == nil {
+		return nil
+	}
+
+	*snapshot = new(T)
+	if err := json.Unmarshal(raw, *snapshot); err != nil {
+		return fmt.Errorf("failed to unmarshal snapshot: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,109 @@
+package mysql
+
+import (
+	"context"
+	"fmt"
+
+	"ailingo/internal/domain"
+)
+
+// searchStudySets queries for study sets visible to the viewer with names or descriptions matching the query.
+// As in listings, study sets that are not public are visible only to their authors and collaborators.
+const searchStudySets = `
+SELECT study_set.id,
+       study_set.name,
+       study_set.description,
+       study_set.phrase_language,
+       study_set.definition_language,
+       study_set.icon,
+       study_set.color,
+       study_set.cefr_level,
+       study_set.visibility,
+       study_set.category,
+       user.id,
+       user.username,
+       user.image_url,
+       MATCH (study_set.name, study_set.description) AGAINST (? IN NATURAL LANGUAGE MODE) AS score
+FROM study_set
+         INNER JOIN user ON user.id = study_set.author_id
+WHERE MATCH (study_set.name, study_set.description) AGAINST (? IN NATURAL LANGUAGE MODE)
+  AND (study_set.visibility = 'PUBLIC'
+    OR study_set.author_id = ?
+    OR study_set.id IN (SELECT collaborator.study_set_id FROM collaborator WHERE collaborator.user_id = ?))
+ORDER BY score DESC, study_set.id DESC
+LIMIT ?
+`
+
+// searchDefinitions queries for definitions from study sets visible to the viewer with phrases or meanings matching the query.
+const searchDefinitions = `
+SELECT definition.id,
+       definition.study_set_id,
+       definition.phrase,
+       definition.meaning,
+       MATCH (definition.phrase, definition.meaning) AGAINST (? IN NATURAL LANGUAGE MODE) AS score
+FROM definition
+         INNER JOIN study_set ON study_set.id = definition.study_set_id
+WHERE MATCH (definition.phrase, definition.meaning) AGAINST (? IN NATURAL LANGUAGE MODE)
+  AND (study_set.visibility = 'PUBLIC'
+    OR study_set.author_id = ?
+    OR study_set.id IN (SELECT collaborator.study_set_id FROM collaborator WHERE collaborator.user_id = ?))
+ORDER BY score DESC, definition.id DESC
+LIMIT ?
+`
+
+type searchRepo struct {
+	db DBTX
+}
+
+func NewSearchRepo(db DBTX) domain.SearchRepo {
+	return &searchRepo{
+		db: db,
+	}
+}
+
+func (r *searchRepo) SearchStudySets(ctx context.Context, viewerID string, query string, limit int) ([]*domain.StudySetHit, error) {
+	hits := make([]*domain.StudySetHit, 0)
+
+	rows, err := r.db.QueryContext(ctx, searchStudySets, query, query, viewerID, viewerID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var hit domain.StudySetHit
+		if err := rows.Scan(
+			// study set
+			&hit.Id, &hit.Name, &hit.Description, &hit.PhraseLanguage, &hit.DefinitionLanguage, &hit.Icon, &hit.Color, &hit.CefrLevel, &hit.Visibility, &hit.Category,
+			// author
+			&hit.Author.Id, &hit.Author.Username, &hit.Author.ImageURL,
+			// relevance
+			&hit.Score,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan: %w", err)
+		}
+		hits = append(hits, &hit)
+	}
+
+	return hits, nil
+}
+
+func (r *searchRepo) SearchDefinitions(ctx context.Context, viewerID string, query string, limit int) ([]*domain.DefinitionHit, error) {
+	hits := make([]*domain.DefinitionHit, 0)
+
+	rows, err := r.db.QueryContext(ctx, searchDefinitions, query, query, viewerID, viewerID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var hit domain.DefinitionHit
+		if err := rows.Scan(&hit.Id, &hit.StudySetId, &hit.Phrase, &hit.Meaning, &hit.Score); err != nil {
+			return nil, fmt.Errorf("failed to scan: %w", err)
+		}
+		hits = append(hits, &hit)
+	}
+
+	return hits, nil
+}
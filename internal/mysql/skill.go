@@ -0,0 +1,127 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"ailingo/internal/domain"
+)
+
+// getSkills queries for all skills of the given user.
+const getSkills = `
+SELECT language, ability, answers, updated_at
+FROM skill
+WHERE user_id = ?
+ORDER BY language
+`
+
+// getSkill queries for the skill of the given user in the given language.
+const getSkill = `
+SELECT language, ability, answers, updated_at
+FROM skill
+WHERE user_id = ?
+  AND language = ?
+`
+
+// upsertSkill inserts or replaces the skill of the given user.
+const upsertSkill = `
+INSERT INTO skill (user_id, language, ability, answers)
+VALUES (?, ?, ?, ?)
+ON DUPLICATE KEY UPDATE ability = VALUES(ability),
+                        answers = VALUES(answers)
+`
+
+// getDefinitionDifficultiesForStudySet queries for difficulty estimates of definitions from the given study set.
+const getDefinitionDifficultiesForStudySet = `
+SELECT definition_difficulty.definition_id, definition_difficulty.difficulty, definition_difficulty.answers
+FROM definition_difficulty
+         INNER JOIN definition ON definition.id = definition_difficulty.definition_id
+WHERE definition.study_set_id = ?
+`
+
+// upsertDefinitionDifficulty inserts or replaces the difficulty estimate of the given definition.
+const upsertDefinitionDifficulty = `
+INSERT INTO definition_difficulty (definition_id, difficulty, answers)
+VALUES (?, ?, ?)
+ON DUPLICATE KEY UPDATE difficulty = VALUES(difficulty),
+                        answers    = VALUES(answers)
+`
+
+type skillRepo struct {
+	db DBTX
+}
+
+func NewSkillRepo(db DBTX) domain.SkillRepo {
+	return &skillRepo{
+		db: db,
+	}
+}
+
+func (r *skillRepo) GetAll(ctx context.Context, userID string) ([]*domain.Skill, error) {
+	skills := make([]*domain.Skill, 0)
+
+	rows, err := r.db.QueryContext(ctx, getSkills, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var skill domain.Skill
+		if err := rows.Scan(&skill.Language, &skill.Ability, &skill.Answers, &skill.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan: %w", err)
+		}
+		skills = append(skills, &skill)
+	}
+
+	return skills, nil
+}
+
+func (r *skillRepo) Get(ctx context.Context, userID string, language string) (*domain.Skill, error) {
+	var skill domain.Skill
+	if err := r.db.QueryRowContext(ctx, getSkill, userID, language).Scan(
+		&skill.Language, &skill.Ability, &skill.Answers, &skill.UpdatedAt,
+	); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to scan: %w", err)
+	}
+	return &skill, nil
+}
+
+func (r *skillRepo) Upsert(ctx context.Context, userID string, skill *domain.Skill) error {
+	if _, err := r.db.ExecContext(ctx, upsertSkill, userID, skill.Language, skill.Ability, skill.Answers); err != nil {
+		return fmt.Errorf("failed to exec: %w", err)
+	}
+	return nil
+}
+
+func (r *skillRepo) GetDifficultiesFor(ctx context.Context, studySetID int64) ([]*domain.DefinitionDifficulty, error) {
+	difficulties := make([]*domain.DefinitionDifficulty, 0)
+
+	rows, err := r.db.QueryContext(ctx, getDefinitionDifficultiesForStudySet, studySetID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var difficulty domain.DefinitionDifficulty
+		if err := rows.Scan(&difficulty.DefinitionId, &difficulty.Difficulty, &difficulty.Answers); err != nil {
+			return nil, fmt.Errorf("failed to scan: %w", err)
+		}
+		difficulties = append(difficulties, &difficulty)
+	}
+
+	return difficulties, nil
+}
+
+func (r *skillRepo) UpsertDifficulty(ctx context.Context, difficulty *domain.DefinitionDifficulty) error {
+	if _, err := r.db.ExecContext(ctx, upsertDefinitionDifficulty, difficulty.DefinitionId, difficulty.Difficulty, difficulty.Answers); err != nil {
+		return fmt.Errorf("failed to exec: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,108 @@
+package mysql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"ailingo/internal/domain"
+)
+
+// getStreak queries for the streak of the given user.
+const getStreak = `
+SELECT current_streak, longest_streak, freezes_available, last_active_date
+FROM streak
+WHERE user_id = ?
+`
+
+// upsertStreak inserts or replaces the streak of the given user.
+const upsertStreak = `
+INSERT INTO streak (user_id, current_streak, longest_streak, freezes_available, last_active_date)
+VALUES (?, ?, ?, ?, ?)
+ON DUPLICATE KEY UPDATE current_streak    = VALUES(current_streak),
+                        longest_streak    = VALUES(longest_streak),
+                        freezes_available = VALUES(freezes_available),
+                        last_active_date  = VALUES(last_active_date)
+`
+
+// getDailyActivity queries for study activity of the given user during the given day.
+const getDailyActivity = `
+SELECT reviews, response_time_ms
+FROM daily_activity
+WHERE user_id = ?
+  AND day = ?
+`
+
+// addDailyActivity adds study activity to the given day.
+const addDailyActivity = `
+INSERT INTO daily_activity (user_id, day, reviews, response_time_ms)
+VALUES (?, ?, ?, ?)
+ON DUPLICATE KEY UPDATE reviews          = reviews + VALUES(reviews),
+                        response_time_ms = response_time_ms + VALUES(response_time_ms)
+`
+
+type streakRepo struct {
+	db DBTX
+}
+
+func NewStreakRepo(db DBTX) domain.StreakRepo {
+	return &streakRepo{
+		db: db,
+	}
+}
+
+func (r *streakRepo) Get(ctx context.Context, userID string) (*domain.StreakRow, error) {
+	var streak domain.StreakRow
+	var lastActiveDate sql.NullTime
+
+	if err := r.db.QueryRowContext(ctx, getStreak, userID).Scan(
+		&streak.Current, &streak.Longest, &streak.FreezesAvailable, &lastActiveDate,
+	); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to scan: %w", err)
+	}
+
+	if lastActiveDate.Valid {
+		formatted := lastActiveDate.Time.Format(time.DateOnly)
+		streak.LastActiveDate = &formatted
+	}
+
+	return &streak, nil
+}
+
+func (r *streakRepo) Upsert(ctx context.Context, userID string, streak *domain.StreakRow) error {
+	if _, err := r.db.ExecContext(
+		ctx,
+		upsertStreak,
+		userID,
+		streak.Current,
+		streak.Longest,
+		streak.FreezesAvailable,
+		streak.LastActiveDate,
+	); err != nil {
+		return fmt.Errorf("failed to exec: %w", err)
+	}
+	return nil
+}
+
+func (r *streakRepo) GetDailyActivity(ctx context.Context, userID string, date string) (*domain.DailyActivityRow, error) {
+	var activity domain.DailyActivityRow
+	if err := r.db.QueryRowContext(ctx, getDailyActivity, userID, date).Scan(&activity.Reviews, &activity.ResponseTimeMs); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return &activity, nil
+		}
+		return nil, fmt.Errorf("failed to scan: %w", err)
+	}
+	return &activity, nil
+}
+
+func (r *streakRepo) AddDailyActivity(ctx context.Context, userID string, date string, activity *domain.DailyActivityRow) error {
+	if _, err := r.db.ExecContext(ctx, addDailyActivity, userID, date, activity.Reviews, activity.ResponseTimeMs); err != nil {
+		return fmt.Errorf("failed to exec: %w", err)
+	}
+	return nil
+}
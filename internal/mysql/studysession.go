@@ -10,7 +10,7 @@ import (
 )
 
 const getRecentStudySessions = `
-SELECT study_session.last_session_at,
+SELECT MAX(study_session.last_session_at),
        study_set.id,
        study_set.name,
        study_set.description,
@@ -18,6 +18,9 @@ SELECT study_session.last_session_at,
        study_set.definition_language,
        study_set.icon,
        study_set.color,
+       study_set.cefr_level,
+       study_set.visibility,
+       study_set.category,
        user.id,
        user.username,
        user.image_url
@@ -25,30 +28,116 @@ FROM study_session
 	     INNER JOIN study_set ON study_session.study_set_id = study_set.id
 	     INNER JOIN user ON study_set.author_id = user.id
 WHERE study_session.user_id = ?
-ORDER BY study_session.last_session_at DESC 
+GROUP BY study_set.id, user.id
+ORDER BY MAX(study_session.last_session_at) DESC 
+`
+
+const getStudySessionHistory = `
+SELECT study_session.id,
+       COALESCE(study_session.study_set_id, 0),
+       study_session.folder_id,
+       COALESCE(study_set.name, folder.name, ''),
+       study_session.mode,
+       study_session.direction,
+       study_session.started_at,
+       study_session.finished_at,
+       study_session.duration_ms,
+       study_session.cards_seen,
+       study_session.correct_count,
+       study_session.incorrect_count
+FROM study_session
+         LEFT JOIN study_set ON study_session.study_set_id = study_set.id
+         LEFT JOIN folder ON study_session.folder_id = folder.id
+WHERE study_session.user_id = ?
+  AND (? = 0 OR study_session.id < ?)
+ORDER BY study_session.id DESC
+LIMIT ?
 `
 
 const getStudySessionForStudySet = `
-SELECT last_session_at
+SELECT MAX(last_session_at)
 FROM study_session
 WHERE user_id = ?
   AND study_set_id = ?
 `
 
+const getStudySession = `
+SELECT study_session.id,
+       study_session.user_id,
+       COALESCE(study_session.study_set_id, 0),
+       study_session.folder_id,
+       COALESCE(study_set.name, folder.name, ''),
+       study_session.mode,
+       study_session.direction,
+       study_session.started_at,
+       study_session.finished_at,
+       study_session.duration_ms,
+       study_session.cards_seen,
+       study_session.correct_count,
+       study_session.incorrect_count
+FROM study_session
+         LEFT JOIN study_set ON study_session.study_set_id = study_set.id
+         LEFT JOIN folder ON study_session.folder_id = folder.id
+WHERE study_session.id = ?
+`
+
+const getStudySessionOutcomes = `
+SELECT definition_id, correct, response_time_ms
+FROM study_session_outcome
+WHERE study_session_id = ?
+ORDER BY position
+`
+
+const startStudySession = `
+INSERT INTO study_session (user_id, study_set_id, mode, direction)
+VALUES (?, ?, ?, ?)
+`
+
+const startFolderStudySession = `
+INSERT INTO study_session (user_id, folder_id, mode, direction)
+VALUES (?, ?, ?, ?)
+`
+
+const finishStudySession = `
+UPDATE study_session
+SET finished_at     = NOW(3),
+    last_session_at = NOW(3),
+    duration_ms     = ?,
+    cards_seen      = ?,
+    correct_count   = ?,
+    incorrect_count = ?
+WHERE id = ?
+`
+
+const insertStudySessionOutcome = `
+INSERT INTO study_session_outcome (study_session_id, definition_id, position, correct, response_time_ms)
+VALUES (?, ?, ?, ?, ?)
+`
+
 const refreshStudySession = `
 UPDATE study_session
-SET last_session_at = NOW()
+SET last_session_at = NOW(3)
 WHERE user_id = ?
   AND study_set_id = ?
+  AND finished_at IS NULL
+ORDER BY id DESC
+LIMIT 1
 `
 
 const insertStudySession = `
-INSERT INTO study_session (user_id, study_set_id)
-VALUES (?, ?)
+INSERT INTO study_session (user_id, study_set_id, mode, direction)
+VALUES (?, ?, 'REVIEW', 'PHRASE_TO_MEANING')
+`
+
+// refreshStudySetLearners recounts users who have studied the study set, which are kept on it for sorting.
+const refreshStudySetLearners = `
+UPDATE study_set
+SET learners = (SELECT COUNT(DISTINCT user_id) FROM study_session WHERE study_set_id = ?)
+WHERE id = ?
 `
 
 const studySessionExists = `
-SELECT EXISTS(SELECT 1 FROM study_session WHERE user_id = ? AND study_set_id = ?) 
+SELECT EXISTS(SELECT 1 FROM study_session WHERE user_id = ? AND study_set_id = ? AND finished_at IS NULL) 
 `
 
 type studySessionRepo struct {
@@ -73,7 +162,7 @@ func (r *studySessionRepo) GetRecent(ctx context.Context, userID string) ([]*dom
 		var studySession domain.StudySessionWithStudySet
 
 		if err := rows.Scan(
-			&studySession.LastSessionAt, &studySession.StudySet.Id, &studySession.StudySet.Name, &studySession.StudySet.Description, &studySession.StudySet.PhraseLanguage, &studySession.StudySet.DefinitionLanguage, &studySession.StudySet.Icon, &studySession.StudySet.Color,
+			&studySession.LastSessionAt, &studySession.StudySet.Id, &studySession.StudySet.Name, &studySession.StudySet.Description, &studySession.StudySet.PhraseLanguage, &studySession.StudySet.DefinitionLanguage, &studySession.StudySet.Icon, &studySession.StudySet.Color, &studySession.StudySet.CefrLevel, &studySession.StudySet.Visibility, &studySession.StudySet.Category,
 			&studySession.StudySet.Author.Id, &studySession.StudySet.Author.Username, &studySession.StudySet.Author.ImageURL,
 		); err != nil {
 			return nil, fmt.Errorf("failed to scan: %w", err)
@@ -85,25 +174,137 @@ func (r *studySessionRepo) GetRecent(ctx context.Context, userID string) ([]*dom
 	return studySessions, nil
 }
 
+func (r *studySessionRepo) GetHistory(ctx context.Context, userID string, cursor int64, limit int) ([]*domain.StudySessionSummary, error) {
+	studySessions := make([]*domain.StudySessionSummary, 0)
+
+	rows, err := r.db.QueryContext(ctx, getStudySessionHistory, userID, cursor, cursor, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var s domain.StudySessionSummary
+		if err := rows.Scan(
+			&s.Id, &s.StudySetId, &s.FolderId, &s.StudySetName, &s.Mode, &s.Direction, &s.StartedAt, &s.FinishedAt,
+			&s.DurationMs, &s.CardsSeen, &s.CorrectCount, &s.IncorrectCount,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan: %w", err)
+		}
+		studySessions = append(studySessions, &s)
+	}
+
+	return studySessions, nil
+}
+
 func (r *studySessionRepo) GetForStudySet(ctx context.Context, userID string, studySetID int64) (*domain.StudySession, error) {
 	row := r.db.QueryRowContext(ctx, getStudySessionForStudySet, userID, studySetID)
 
 	var studySession domain.StudySession
 	if err := row.Scan(&studySession.LastSessionAt); err != nil {
+		return nil, fmt.Errorf("failed to scan: %w", err)
+	}
+	// MAX over no rows yields NULL
+	if studySession.LastSessionAt == nil {
+		return nil, nil
+	}
+
+	return &studySession, nil
+}
+
+func (r *studySessionRepo) Get(ctx context.Context, studySessionID int64) (*domain.StudySessionRow, error) {
+	var s domain.StudySessionRow
+	if err := r.db.QueryRowContext(ctx, getStudySession, studySessionID).Scan(
+		&s.Id, &s.UserId, &s.StudySetId, &s.FolderId, &s.StudySetName, &s.Mode, &s.Direction, &s.StartedAt, &s.FinishedAt,
+		&s.DurationMs, &s.CardsSeen, &s.CorrectCount, &s.IncorrectCount,
+	); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, nil
 		}
-		return nil, fmt.Errorf("")
+		return nil, fmt.Errorf("failed to scan: %w", err)
 	}
+	return &s, nil
+}
 
-	return &studySession, nil
+func (r *studySessionRepo) GetOutcomes(ctx context.Context, studySessionID int64) ([]*domain.StudySessionOutcome, error) {
+	outcomes := make([]*domain.StudySessionOutcome, 0)
+
+	rows, err := r.db.QueryContext(ctx, getStudySessionOutcomes, studySessionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var outcome domain.StudySessionOutcome
+		if err := rows.Scan(&outcome.DefinitionId, &outcome.Correct, &outcome.ResponseTimeMs); err != nil {
+			return nil, fmt.Errorf("failed to scan: %w", err)
+		}
+		outcomes = append(outcomes, &outcome)
+	}
+
+	return outcomes, nil
+}
+
+func (r *studySessionRepo) Start(ctx context.Context, userID string, studySetID int64, mode string, direction string) (int64, error) {
+	res, err := r.db.ExecContext(ctx, startStudySession, userID, studySetID, mode, direction)
+	if err != nil {
+		return 0, fmt.Errorf("failed to exec: %w", err)
+	}
+
+	lastInsertId, err := res.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get last insert id: %w", err)
+	}
+
+	if err := r.refreshLearners(ctx, studySetID); err != nil {
+		return 0, err
+	}
+
+	return lastInsertId, nil
+}
+
+func (r *studySessionRepo) StartForFolder(ctx context.Context, userID string, folderID int64, mode string, direction string) (int64, error) {
+	res, err := r.db.ExecContext(ctx, startFolderStudySession, userID, folderID, mode, direction)
+	if err != nil {
+		return 0, fmt.Errorf("failed to exec: %w", err)
+	}
+
+	lastInsertId, err := res.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get last insert id: %w", err)
+	}
+
+	return lastInsertId, nil
+}
+
+func (r *studySessionRepo) Finish(ctx context.Context, studySessionID int64, finishData *domain.FinishStudySessionRow) error {
+	if _, err := r.db.ExecContext(
+		ctx,
+		finishStudySession,
+		finishData.DurationMs,
+		finishData.CardsSeen,
+		finishData.CorrectCount,
+		finishData.IncorrectCount,
+		studySessionID,
+	); err != nil {
+		return fmt.Errorf("failed to exec: %w", err)
+	}
+	return nil
+}
+
+func (r *studySessionRepo) InsertOutcome(ctx context.Context, studySessionID int64, position int, outcome *domain.StudySessionOutcome) error {
+	if _, err := r.db.ExecContext(ctx, insertStudySessionOutcome, studySessionID, outcome.DefinitionId, position, outcome.Correct, outcome.ResponseTimeMs); err != nil {
+		return fmt.Errorf("failed to exec: %w", err)
+	}
+	return nil
 }
 
 func (r *studySessionRepo) Create(ctx context.Context, userID string, studySetID int64) error {
 	if _, err := r.db.ExecContext(ctx, insertStudySession, userID, studySetID); err != nil {
 		return fmt.Errorf("failed to exec insert query: %w", err)
 	}
-	return nil
+	return r.refreshLearners(ctx, studySetID)
 }
 
 func (r *studySessionRepo) Refresh(ctx context.Context, userID string, studySetID int64) error {
@@ -123,3 +324,10 @@ func (r *studySessionRepo) Exists(ctx context.Context, userID string, studySetID
 
 	return exists == 1, nil
 }
+
+func (r *studySessionRepo) refreshLearners(ctx context.Context, studySetID int64) error {
+	if _, err := r.db.ExecContext(ctx, refreshStudySetLearners, studySetID, studySetID); err != nil {
+		return fmt.Errorf("failed to exec a refresh query: %w", err)
+	}
+	return nil
+}
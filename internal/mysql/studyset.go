@@ -9,8 +9,92 @@ import (
 	"ailingo/internal/domain"
 )
 
-// getAllStudySets queries for all study sets that have ever been created.
-const getStudySets = `
+// getStudySetPage queries for a page of study sets matching the filters.
+// The keyset condition and the ordering depend on the sort option and are filled in with studySetPageOrders.
+// The page is cut in the inner query, so that definitions are counted only for the returned study sets.
+const getStudySetPage = `
+SELECT id,
+       name,
+       description,
+       phrase_language,
+       definition_language,
+       icon,
+       color,
+       cefr_level,
+       visibility,
+       category,
+       author_id,
+       author_username,
+       author_image_url,
+       stars,
+       learners,
+       (SELECT COUNT(*) FROM definition WHERE definition.study_set_id = listing.id) AS definitions
+FROM (SELECT study_set.id,
+             study_set.name,
+             study_set.description,
+             study_set.phrase_language,
+             study_set.definition_language,
+             study_set.icon,
+             study_set.color,
+             study_set.cefr_level,
+             study_set.visibility,
+             study_set.category,
+             user.id             AS author_id,
+             user.username       AS author_username,
+             user.image_url      AS author_image_url,
+             study_set.stars,
+             study_set.learners
+      FROM study_set
+               INNER JOIN user ON user.id = study_set.author_id
+      WHERE (? = '' OR study_set.phrase_language = ?)
+        AND (? = '' OR study_set.definition_language = ?)
+        AND (? = '' OR study_set.author_id = ?)
+        AND (? = '' OR study_set.cefr_level = ?)
+        AND (? = '' OR study_set.category = ?)
+        AND (? = '' OR study_set.id IN (SELECT study_set_tag.study_set_id
+                                        FROM study_set_tag
+                                                 INNER JOIN tag ON tag.id = study_set_tag.tag_id
+                                        WHERE tag.name = ?))
+        AND (? = '' OR study_set.id IN (SELECT star.study_set_id FROM star WHERE star.user_id = ?))
+        AND (? = '' OR study_set.id IN (SELECT collaborator.study_set_id FROM collaborator WHERE collaborator.user_id = ?))
+        AND (study_set.visibility = 'PUBLIC'
+          OR (? AND study_set.visibility = 'UNLISTED')
+          OR study_set.author_id = ?
+          OR study_set.id IN (SELECT collaborator.study_set_id FROM collaborator WHERE collaborator.user_id = ?))
+        AND (? IS NULL OR EXISTS(SELECT 1 FROM definition WHERE definition.study_set_id = study_set.id) = ?)
+        AND %s
+      ORDER BY %s
+      LIMIT ?) AS listing
+ORDER BY %s
+`
+
+// studySetPageOrders maps sort options to the keyset condition and the ordering of getStudySetPage.
+// The condition is true for every row when the cursor is not set. Stars and learners are counters
+// kept on the study set, so that every sort option can walk an index.
+var studySetPageOrders = map[string]struct {
+	after   string
+	orderBy string
+}{
+	domain.StudySetSortNewest: {
+		after:   "(? = 0 OR study_set.id < ?)",
+		orderBy: "id DESC",
+	},
+	domain.StudySetSortStars: {
+		after:   "(? = 0 OR study_set.stars < ? OR (study_set.stars = ? AND study_set.id < ?))",
+		orderBy: "stars DESC, id DESC",
+	},
+	domain.StudySetSortStudied: {
+		after:   "(? = 0 OR study_set.learners < ? OR (study_set.learners = ? AND study_set.id < ?))",
+		orderBy: "learners DESC, id DESC",
+	},
+	domain.StudySetSortName: {
+		after:   "(? = 0 OR study_set.name > ? OR (study_set.name = ? AND study_set.id > ?))",
+		orderBy: "name, id",
+	},
+}
+
+// getStudySetsByCefrLevel queries for all public study sets of the given phrase language and CEFR level.
+const getStudySetsByCefrLevel = `
 SELECT study_set.id,
        study_set.name,
        study_set.description,
@@ -18,22 +102,22 @@ SELECT study_set.id,
        study_set.definition_language,
        study_set.icon,
        study_set.color,
+       study_set.cefr_level,
+       study_set.visibility,
+       study_set.category,
        user.id,
        user.username,
        user.image_url
 FROM study_set
          INNER JOIN user ON user.id = study_set.author_id
+WHERE study_set.phrase_language = ?
+  AND study_set.cefr_level = ?
+  AND study_set.visibility = 'PUBLIC'
+ORDER BY study_set.id DESC
 `
 
-// getStudySetsCreatedBy queries for all study sets created by the specified user.
-const getStudySetsCreatedBy = `
-SELECT id, name, description, phrase_language, definition_language, icon, color
-FROM study_set
-WHERE author_id = ?
-`
-
-// getStudySetsStarredBy queries for all study sets starred by the specified user.
-const getStudySetsStarredBy = `
+// getStudySetById queries for a study set with the given id
+const getStudySetById = `
 SELECT study_set.id,
        study_set.name,
        study_set.description,
@@ -41,17 +125,20 @@ SELECT study_set.id,
        study_set.definition_language,
        study_set.icon,
        study_set.color,
+       study_set.cefr_level,
+       study_set.visibility,
+       study_set.category,
        user.id,
        user.username,
        user.image_url
-FROM star
-         INNER JOIN study_set ON star.study_set_id = study_set.id
+FROM study_set
          INNER JOIN user ON user.id = study_set.author_id
-WHERE star.user_id = ?
+WHERE study_set.id = ?
+LIMIT 1
 `
 
-// getStudySetById queries for a study set with the given id
-const getStudySetById = `
+// getStudySetDetailsById queries for a study set with the given id, its fork provenance and the number of its forks.
+const getStudySetDetailsById = `
 SELECT study_set.id,
        study_set.name,
        study_set.description,
@@ -59,19 +146,29 @@ SELECT study_set.id,
        study_set.definition_language,
        study_set.icon,
        study_set.color,
+       study_set.cefr_level,
+       study_set.visibility,
+       study_set.category,
        user.id,
        user.username,
-       user.image_url
+       user.image_url,
+       study_set.forked_from_id,
+       study_set.forked_from_author_id,
+       fork_author.username,
+       fork_author.image_url,
+       (SELECT COUNT(*) FROM study_set AS fork WHERE fork.forked_from_id = study_set.id)
 FROM study_set
          INNER JOIN user ON user.id = study_set.author_id
+         LEFT JOIN user AS fork_author ON fork_author.id = study_set.forked_from_author_id
 WHERE study_set.id = ?
 LIMIT 1
 `
 
 // insertStudySets inserts a new study sets into the db.
 const insertStudySet = `
-INSERT INTO study_set (author_id, name, description, phrase_language, definition_language, icon, color)
-VALUES (?, ?, ?, ?, ?, ?, ?)
+INSERT INTO study_set (author_id, name, description, phrase_language, definition_language, icon, color, cefr_level, visibility,
+                       category, forked_from_id, forked_from_author_id)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 `
 
 // updateStudySet updates the given study set.
@@ -82,7 +179,10 @@ SET name                = ?,
     phrase_language     = ?,
     definition_language = ?,
     icon = ?,
-    color = ?
+    color = ?,
+    cefr_level = ?,
+    visibility = ?,
+    category = ?
 WHERE id = ?
 `
 
@@ -111,12 +211,112 @@ FROM star
 WHERE study_set_id = ?
 `
 
+// unlinkStudySetForks makes forks of the study set forget it, while keeping its author as their origin.
+const unlinkStudySetForks = `
+UPDATE study_set
+SET forked_from_id = NULL
+WHERE forked_from_id = ?
+`
+
+const deleteStudySetTagLinks = `
+DELETE
+FROM study_set_tag
+WHERE study_set_id = ?
+`
+
+const deleteStudySetFolderEntries = `
+DELETE
+FROM folder_study_set
+WHERE study_set_id = ?
+`
+
+const deleteStudySetRevisions = `
+DELETE
+FROM revision
+WHERE study_set_id = ?
+`
+
+const deleteStudySetCollaborators = `
+DELETE
+FROM collaborator
+WHERE study_set_id = ?
+`
+
+const deleteStudySetStudySessionOutcomes = `
+DELETE study_session_outcome
+FROM study_session_outcome
+         INNER JOIN study_session ON study_session.id = study_session_outcome.study_session_id
+WHERE study_session.study_set_id = ?
+`
+
 const deleteStudySetStudySessions = `
 DELETE 
 FROM study_session 
 WHERE study_set_id = ?
 `
 
+const deleteStudySetReviewStates = `
+DELETE
+FROM review_state
+WHERE study_set_id = ?
+`
+
+const deleteStudySetQuizQuestions = `
+DELETE quiz_question
+FROM quiz_question
+         INNER JOIN quiz ON quiz.id = quiz_question.quiz_id
+WHERE quiz.study_set_id = ?
+`
+
+const deleteStudySetQuizzes = `
+DELETE
+FROM quiz
+WHERE study_set_id = ?
+`
+
+const deleteStudySetExamQuestions = `
+DELETE exam_question
+FROM exam_question
+         INNER JOIN exam ON exam.id = exam_question.exam_id
+WHERE exam.study_set_id = ?
+`
+
+const deleteStudySetExams = `
+DELETE
+FROM exam
+WHERE study_set_id = ?
+`
+
+const insertStudySetDefinitionTombstones = `
+INSERT INTO definition_tombstone (definition_id, study_set_id)
+SELECT id, study_set_id
+FROM definition
+WHERE study_set_id = ?
+`
+
+// insertStudySetTombstone remembers users who have studied the study set, directly or through a folder,
+// so that they can still be told about its deleted definitions once their study sessions are gone.
+const insertStudySetTombstone = `
+INSERT INTO study_set_tombstone (study_set_id, user_id)
+SELECT ?, learners.user_id
+FROM (SELECT user_id
+      FROM study_session
+      WHERE study_set_id = ?
+      UNION
+      SELECT study_session.user_id
+      FROM study_session
+               INNER JOIN study_session_outcome ON study_session_outcome.study_session_id = study_session.id
+               INNER JOIN definition ON definition.id = study_session_outcome.definition_id
+      WHERE definition.study_set_id = ?) AS learners
+`
+
+const deleteStudySetDefinitionDifficulties = `
+DELETE definition_difficulty
+FROM definition_difficulty
+         INNER JOIN definition ON definition.id = definition_difficulty.definition_id
+WHERE definition.study_set_id = ?
+`
+
 type studySetRepo struct {
 	db DBTX
 }
@@ -127,21 +327,64 @@ func NewStudySetRepo(db DBTX) domain.StudySetRepo {
 	}
 }
 
-func (r *studySetRepo) GetAll(ctx context.Context) ([]*domain.StudySetWithAuthor, error) {
-	studySets := make([]*domain.StudySetWithAuthor, 0)
+func (r *studySetRepo) GetPage(ctx context.Context, filter *domain.StudySetFilter, after *domain.StudySetCursor, limit int) ([]*domain.StudySetListing, error) {
+	order, ok := studySetPageOrders[filter.Sort]
+	if !ok {
+		order = studySetPageOrders[domain.StudySetSortNewest]
+	}
+
+	args := []any{
+		filter.PhraseLanguage, filter.PhraseLanguage,
+		filter.DefinitionLanguage, filter.DefinitionLanguage,
+		filter.AuthorId, filter.AuthorId,
+		filter.CefrLevel, filter.CefrLevel,
+		filter.Category, filter.Category,
+		filter.Tag, filter.Tag,
+		filter.StarredBy, filter.StarredBy,
+		filter.SharedWith, filter.SharedWith,
+		filter.IncludeUnlisted, filter.ViewerId, filter.ViewerId,
+		filter.HasDefinitions, filter.HasDefinitions,
+	}
 
-	rows, err := r.db.QueryContext(ctx, getStudySets)
+	var cursorID int64
+	if after != nil {
+		cursorID = after.Id
+	}
+	switch filter.Sort {
+	case domain.StudySetSortStars, domain.StudySetSortStudied:
+		var count int64
+		if after != nil {
+			count = after.Count
+		}
+		args = append(args, cursorID, count, count, cursorID)
+	case domain.StudySetSortName:
+		var name string
+		if after != nil {
+			name = after.Name
+		}
+		args = append(args, cursorID, name, name, cursorID)
+	default:
+		args = append(args, cursorID, cursorID)
+	}
+	args = append(args, limit)
+
+	studySets := make([]*domain.StudySetListing, 0)
+
+	rows, err := r.db.QueryContext(ctx, fmt.Sprintf(getStudySetPage, order.after, order.orderBy, order.orderBy), args...)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query: %w", err)
 	}
+	defer rows.Close()
 
 	for rows.Next() {
-		var studySet domain.StudySetWithAuthor
+		var studySet domain.StudySetListing
 		if err := rows.Scan(
 			// study set
-			&studySet.Id, &studySet.Name, &studySet.Description, &studySet.PhraseLanguage, &studySet.DefinitionLanguage, &studySet.Icon, &studySet.Color,
+			&studySet.Id, &studySet.Name, &studySet.Description, &studySet.PhraseLanguage, &studySet.DefinitionLanguage, &studySet.Icon, &studySet.Color, &studySet.CefrLevel, &studySet.Visibility, &studySet.Category,
 			// author
 			&studySet.Author.Id, &studySet.Author.Username, &studySet.Author.ImageURL,
+			// statistics
+			&studySet.Stars, &studySet.Learners, &studySet.Definitions,
 		); err != nil {
 			return nil, fmt.Errorf("failed to scan: %w", err)
 		}
@@ -156,7 +399,7 @@ func (r *studySetRepo) GetById(ctx context.Context, studySetID int64) (*domain.S
 
 	if err := r.db.QueryRowContext(ctx, getStudySetById, studySetID).Scan(
 		// study set
-		&studySet.Id, &studySet.Name, &studySet.Description, &studySet.PhraseLanguage, &studySet.DefinitionLanguage, &studySet.Icon, &studySet.Color,
+		&studySet.Id, &studySet.Name, &studySet.Description, &studySet.PhraseLanguage, &studySet.DefinitionLanguage, &studySet.Icon, &studySet.Color, &studySet.CefrLevel, &studySet.Visibility, &studySet.Category,
 		// author
 		&studySet.Author.Id, &studySet.Author.Username, &studySet.Author.ImageURL,
 	); err != nil {
@@ -169,38 +412,55 @@ func (r *studySetRepo) GetById(ctx context.Context, studySetID int64) (*domain.S
 	return &studySet, nil
 }
 
-func (r *studySetRepo) GetCreatedBy(ctx context.Context, userID string) ([]*domain.StudySet, error) {
-	studySets := make([]*domain.StudySet, 0)
+func (r *studySetRepo) GetDetailsById(ctx context.Context, studySetID int64) (*domain.StudySetDetails, error) {
+	var studySet domain.StudySetDetails
+	var forkedFromId sql.NullInt64
+	var forkAuthorId, forkAuthorUsername, forkAuthorImageURL sql.NullString
 
-	rows, err := r.db.QueryContext(ctx, getStudySetsCreatedBy, userID)
-	if err != nil {
+	if err := r.db.QueryRowContext(ctx, getStudySetDetailsById, studySetID).Scan(
+		// study set
+		&studySet.Id, &studySet.Name, &studySet.Description, &studySet.PhraseLanguage, &studySet.DefinitionLanguage, &studySet.Icon, &studySet.Color, &studySet.CefrLevel, &studySet.Visibility, &studySet.Category,
+		// author
+		&studySet.Author.Id, &studySet.Author.Username, &studySet.Author.ImageURL,
+		// forks
+		&forkedFromId, &forkAuthorId, &forkAuthorUsername, &forkAuthorImageURL, &studySet.Forks,
+	); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
 		return nil, fmt.Errorf("failed to query: %w", err)
 	}
 
-	for rows.Next() {
-		var studySet domain.StudySet
-		if err := rows.Scan(&studySet.Id, &studySet.Name, &studySet.Description, &studySet.PhraseLanguage, &studySet.DefinitionLanguage, &studySet.Icon, &studySet.Color); err != nil {
-			return nil, fmt.Errorf("failed to scan: %w", err)
+	if forkAuthorId.Valid {
+		studySet.ForkedFrom = &domain.ForkOrigin{
+			Author: domain.Author{
+				Id:       forkAuthorId.String,
+				Username: forkAuthorUsername.String,
+				ImageURL: forkAuthorImageURL.String,
+			},
+		}
+		if forkedFromId.Valid {
+			studySet.ForkedFrom.StudySetId = &forkedFromId.Int64
 		}
-		studySets = append(studySets, &studySet)
 	}
 
-	return studySets, nil
+	return &studySet, nil
 }
 
-func (r *studySetRepo) GetStarredBy(ctx context.Context, userID string) ([]*domain.StudySetWithAuthor, error) {
+func (r *studySetRepo) GetByCefrLevel(ctx context.Context, phraseLanguage string, level string) ([]*domain.StudySetWithAuthor, error) {
 	studySets := make([]*domain.StudySetWithAuthor, 0)
 
-	rows, err := r.db.QueryContext(ctx, getStudySetsStarredBy, userID)
+	rows, err := r.db.QueryContext(ctx, getStudySetsByCefrLevel, phraseLanguage, level)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query: %w", err)
 	}
+	defer rows.Close()
 
 	for rows.Next() {
 		var studySet domain.StudySetWithAuthor
 		if err := rows.Scan(
 			// study set
-			&studySet.Id, &studySet.Name, &studySet.Description, &studySet.PhraseLanguage, &studySet.DefinitionLanguage, &studySet.Icon, &studySet.Color,
+			&studySet.Id, &studySet.Name, &studySet.Description, &studySet.PhraseLanguage, &studySet.DefinitionLanguage, &studySet.Icon, &studySet.Color, &studySet.CefrLevel, &studySet.Visibility, &studySet.Category,
 			// author
 			&studySet.Author.Id, &studySet.Author.Username, &studySet.Author.ImageURL,
 		); err != nil {
@@ -223,6 +483,11 @@ func (r *studySetRepo) Insert(ctx context.Context, insertData *domain.InsertStud
 		insertData.DefinitionLanguage,
 		insertData.Icon,
 		insertData.Color,
+		insertData.CefrLevel,
+		insertData.Visibility,
+		insertData.Category,
+		insertData.ForkedFromId,
+		insertData.ForkedFromAuthorId,
 	)
 	if err != nil {
 		return 0, fmt.Errorf("failed to exec: %w", err)
@@ -246,6 +511,9 @@ func (r *studySetRepo) Update(ctx context.Context, studySetID int64, updateData
 		updateData.DefinitionLanguage,
 		updateData.Icon,
 		updateData.Color,
+		updateData.CefrLevel,
+		updateData.Visibility,
+		updateData.Category,
 		studySetID,
 	); err != nil {
 		return fmt.Errorf("failed to exec: %w", err)
@@ -256,14 +524,70 @@ func (r *studySetRepo) Update(ctx context.Context, studySetID int64, updateData
 func (r *studySetRepo) Delete(ctx context.Context, studySetID int64) error {
 	// TODO: This could be split into separate repo functions and run with DataStore.Atomic
 
+	if _, err := r.db.ExecContext(ctx, insertStudySetTombstone, studySetID, studySetID, studySetID); err != nil {
+		return fmt.Errorf("failed to exec: %w", err)
+	}
+
 	if _, err := r.db.ExecContext(ctx, deleteStudySetStars, studySetID); err != nil {
 		return fmt.Errorf("failed to exec: %w", err)
 	}
 
+	if _, err := r.db.ExecContext(ctx, unlinkStudySetForks, studySetID); err != nil {
+		return fmt.Errorf("failed to exec: %w", err)
+	}
+
+	if _, err := r.db.ExecContext(ctx, deleteStudySetTagLinks, studySetID); err != nil {
+		return fmt.Errorf("failed to exec: %w", err)
+	}
+
+	if _, err := r.db.ExecContext(ctx, deleteStudySetFolderEntries, studySetID); err != nil {
+		return fmt.Errorf("failed to exec: %w", err)
+	}
+
+	if _, err := r.db.ExecContext(ctx, deleteStudySetRevisions, studySetID); err != nil {
+		return fmt.Errorf("failed to exec: %w", err)
+	}
+
+	if _, err := r.db.ExecContext(ctx, deleteStudySetCollaborators, studySetID); err != nil {
+		return fmt.Errorf("failed to exec: %w", err)
+	}
+
+	if _, err := r.db.ExecContext(ctx, deleteStudySetStudySessionOutcomes, studySetID); err != nil {
+		return fmt.Errorf("failed to exec: %w", err)
+	}
+
 	if _, err := r.db.ExecContext(ctx, deleteStudySetStudySessions, studySetID); err != nil {
 		return fmt.Errorf("failed to exec: %w", err)
 	}
 
+	if _, err := r.db.ExecContext(ctx, deleteStudySetQuizQuestions, studySetID); err != nil {
+		return fmt.Errorf("failed to exec: %w", err)
+	}
+
+	if _, err := r.db.ExecContext(ctx, deleteStudySetQuizzes, studySetID); err != nil {
+		return fmt.Errorf("failed to exec: %w", err)
+	}
+
+	if _, err := r.db.ExecContext(ctx, deleteStudySetExamQuestions, studySetID); err != nil {
+		return fmt.Errorf("failed to exec: %w", err)
+	}
+
+	if _, err := r.db.ExecContext(ctx, deleteStudySetExams, studySetID); err != nil {
+		return fmt.Errorf("failed to exec: %w", err)
+	}
+
+	if _, err := r.db.ExecContext(ctx, deleteStudySetReviewStates, studySetID); err != nil {
+		return fmt.Errorf("failed to exec: %w", err)
+	}
+
+	if _, err := r.db.ExecContext(ctx, deleteStudySetDefinitionDifficulties, studySetID); err != nil {
+		return fmt.Errorf("failed to exec: %w", err)
+	}
+
+	if _, err := r.db.ExecContext(ctx, insertStudySetDefinitionTombstones, studySetID); err != nil {
+		return fmt.Errorf("failed to exec: %w", err)
+	}
+
 	if _, err := r.db.ExecContext(ctx, deleteStudySetDefinitions, studySetID); err != nil {
 		return fmt.Errorf("failed to exec: %w", err)
 	}
@@ -0,0 +1,147 @@
+package mysql
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"ailingo/internal/domain"
+)
+
+// getNow queries for current time of the database clock.
+const getNow = `SELECT NOW(3)`
+
+// getChangedReviewStates queries for review states of the user changed since the given time.
+const getChangedReviewStates = `
+SELECT definition_id, study_set_id, ease, interval_days, repetitions, lapses, due_at, last_reviewed_at
+FROM review_state
+WHERE user_id = ?
+  AND (? IS NULL OR updated_at >= ?)
+`
+
+// getChangedDefinitions queries for definitions changed since the given time in study sets the user has studied
+// and can still see.
+const getChangedDefinitions = `
+SELECT id, study_set_id, phrase, meaning, sentences
+FROM definition
+WHERE study_set_id IN (SELECT study_set_id FROM study_session WHERE user_id = ?)
+  AND study_set_id IN (SELECT id
+                       FROM study_set
+                       WHERE visibility <> 'PRIVATE'
+                          OR author_id = ?
+                          OR id IN (SELECT study_set_id FROM collaborator WHERE user_id = ?))
+  AND (? IS NULL OR updated_at >= ?)
+`
+
+// getDeletedDefinitions queries for ids of definitions deleted since the given time from study sets the user has studied.
+// Definitions of existing study sets are returned only while the user can still see the set. Learners of deleted
+// study sets are remembered in study_set_tombstone, as their study sessions are deleted together with the set.
+// Study sets studied through folders are found by the outcomes of folder sessions.
+const getDeletedDefinitions = `
+SELECT definition_id
+FROM definition_tombstone
+WHERE deleted_at >= ?
+  AND (study_set_id IN (SELECT study_set_id FROM study_set_tombstone WHERE user_id = ?)
+    OR ((study_set_id IN (SELECT study_set_id FROM study_session WHERE user_id = ?)
+      OR study_set_id IN (SELECT definition.study_set_id
+                          FROM study_session
+                                   INNER JOIN study_session_outcome
+                                              ON study_session_outcome.study_session_id = study_session.id
+                                   INNER JOIN definition ON definition.id = study_session_outcome.definition_id
+                          WHERE study_session.user_id = ?
+                            AND study_session.folder_id IS NOT NULL))
+      AND study_set_id IN (SELECT id
+                           FROM study_set
+                           WHERE visibility <> 'PRIVATE'
+                              OR author_id = ?
+                              OR id IN (SELECT study_set_id FROM collaborator WHERE user_id = ?))))
+`
+
+type syncRepo struct {
+	db DBTX
+}
+
+func NewSyncRepo(db DBTX) domain.SyncRepo {
+	return &syncRepo{
+		db: db,
+	}
+}
+
+func (r *syncRepo) Now(ctx context.Context) (time.Time, error) {
+	var now time.Time
+	if err := r.db.QueryRowContext(ctx, getNow).Scan(&now); err != nil {
+		return time.Time{}, fmt.Errorf("failed to scan: %w", err)
+	}
+	return now, nil
+}
+
+func (r *syncRepo) GetChangedStates(ctx context.Context, userID string, since *time.Time) ([]*domain.SyncedReviewState, error) {
+	states := make([]*domain.SyncedReviewState, 0)
+
+	rows, err := r.db.QueryContext(ctx, getChangedReviewStates, userID, since, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var state domain.SyncedReviewState
+		if err := rows.Scan(
+			&state.DefinitionId, &state.StudySetId, &state.Ease, &state.IntervalDays, &state.Repetitions, &state.Lapses,
+			&state.DueAt, &state.LastReviewedAt,
+		); err != nil {
+			return nil, fmt.Errorf("failed to scan: %w", err)
+		}
+		states = append(states, &state)
+	}
+
+	return states, nil
+}
+
+func (r *syncRepo) GetChangedDefinitions(ctx context.Context, userID string, since *time.Time) ([]*domain.SyncedDefinition, error) {
+	definitions := make([]*domain.SyncedDefinition, 0)
+
+	rows, err := r.db.QueryContext(ctx, getChangedDefinitions, userID, userID, userID, since, since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var definition domain.SyncedDefinition
+		var sentencesRaw json.RawMessage
+
+		if err := rows.Scan(&definition.Id, &definition.StudySetId, &definition.Phrase, &definition.Meaning, &sentencesRaw); err != nil {
+			return nil, fmt.Errorf("failed to scan: %w", err)
+		}
+
+		if err := json.Unmarshal(sentencesRaw, &definition.Sentences); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal sentences: %w", err)
+		}
+
+		definitions = append(definitions, &definition)
+	}
+
+	return definitions, nil
+}
+
+func (r *syncRepo) GetDeletedDefinitions(ctx context.Context, userID string, since time.Time) ([]int64, error) {
+	definitionIDs := make([]int64, 0)
+
+	rows, err := r.db.QueryContext(ctx, getDeletedDefinitions, since, userID, userID, userID, userID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var definitionID int64
+		if err := rows.Scan(&definitionID); err != nil {
+			return nil, fmt.Errorf("failed to scan: %w", err)
+		}
+		definitionIDs = append(definitionIDs, definitionID)
+	}
+
+	return definitionIDs, nil
+}
@@ -0,0 +1,152 @@
+package mysql
+
+import (
+	"context"
+	"fmt"
+
+	"ailingo/internal/domain"
+)
+
+// getTagsByPrefix queries for tags starting with the given prefix with the number of public study sets using them.
+// Tags used only by study sets that are not public are left out. The prefix is normalized, so it cannot contain LIKE wildcards.
+const getTagsByPrefix = `
+SELECT tag.name, COUNT(study_set.id) AS uses
+FROM tag
+         LEFT JOIN study_set_tag ON study_set_tag.tag_id = tag.id
+         LEFT JOIN study_set ON study_set.id = study_set_tag.study_set_id AND study_set.visibility = 'PUBLIC'
+WHERE tag.name LIKE CONCAT(?, '%')
+GROUP BY tag.id, tag.name
+HAVING uses > 0
+ORDER BY uses DESC, tag.name
+LIMIT ?
+`
+
+// getStudySetTags queries for names of the tags of the given study set.
+const getStudySetTags = `
+SELECT tag.name
+FROM study_set_tag
+         INNER JOIN tag ON tag.id = study_set_tag.tag_id
+WHERE study_set_tag.study_set_id = ?
+ORDER BY tag.name
+`
+
+// insertTag creates the tag if it does not exist yet. Either way the id of the tag becomes the last insert id.
+const insertTag = `
+INSERT INTO tag (name)
+VALUES (?)
+ON DUPLICATE KEY UPDATE id = LAST_INSERT_ID(id)
+`
+
+const deleteStudySetTags = `
+DELETE
+FROM study_set_tag
+WHERE study_set_id = ?
+`
+
+const insertStudySetTag = `
+INSERT INTO study_set_tag (study_set_id, tag_id)
+VALUES (?, ?)
+`
+
+// getCategoryUsage queries for the number of public study sets in each category.
+const getCategoryUsage = `
+SELECT category, COUNT(*)
+FROM study_set
+WHERE category IS NOT NULL
+  AND visibility = 'PUBLIC'
+GROUP BY category
+`
+
+type tagRepo struct {
+	db DBTX
+}
+
+func NewTagRepo(db DBTX) domain.TagRepo {
+	return &tagRepo{
+		db: db,
+	}
+}
+
+func (r *tagRepo) GetByPrefix(ctx context.Context, prefix string, limit int) ([]*domain.Tag, error) {
+	tags := make([]*domain.Tag, 0)
+
+	rows, err := r.db.QueryContext(ctx, getTagsByPrefix, prefix, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var tag domain.Tag
+		if err := rows.Scan(&tag.Name, &tag.Uses); err != nil {
+			return nil, fmt.Errorf("failed to scan: %w", err)
+		}
+		tags = append(tags, &tag)
+	}
+
+	return tags, nil
+}
+
+func (r *tagRepo) GetForStudySet(ctx context.Context, studySetID int64) ([]string, error) {
+	tags := make([]string, 0)
+
+	rows, err := r.db.QueryContext(ctx, getStudySetTags, studySetID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var tag string
+		if err := rows.Scan(&tag); err != nil {
+			return nil, fmt.Errorf("failed to scan: %w", err)
+		}
+		tags = append(tags, tag)
+	}
+
+	return tags, nil
+}
+
+func (r *tagRepo) SetForStudySet(ctx context.Context, studySetID int64, tags []string) error {
+	if _, err := r.db.ExecContext(ctx, deleteStudySetTags, studySetID); err != nil {
+		return fmt.Errorf("failed to exec: %w", err)
+	}
+
+	for _, tag := range tags {
+		res, err := r.db.ExecContext(ctx, insertTag, tag)
+		if err != nil {
+			return fmt.Errorf("failed to exec: %w", err)
+		}
+
+		tagID, err := res.LastInsertId()
+		if err != nil {
+			return fmt.Errorf("failed to get last insert id: %w", err)
+		}
+
+		if _, err := r.db.ExecContext(ctx, insertStudySetTag, studySetID, tagID); err != nil {
+			return fmt.Errorf("failed to exec: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (r *tagRepo) GetCategoryUsage(ctx context.Context) ([]*domain.CategoryUsage, error) {
+	categories := make([]*domain.CategoryUsage, 0)
+
+	rows, err := r.db.QueryContext(ctx, getCategoryUsage)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var category domain.CategoryUsage
+		if err := rows.Scan(&category.Category, &category.StudySets); err != nil {
+			return nil, fmt.Errorf("failed to scan: %w", err)
+		}
+		categories = append(categories, &category)
+	}
+
+	return categories, nil
+}
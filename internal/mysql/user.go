@@ -3,6 +3,7 @@ package mysql
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
 
 	"github.com/clerkinc/clerk-sdk-go/clerk"
@@ -70,6 +71,9 @@ func (r *userRepo) GetById(ctx context.Context, userID string) (*domain.UserRow,
 
 	var user domain.UserRow
 	if err := row.Scan(&user.Id, &user.Username, &user.ImageURL); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
 		return nil, fmt.Errorf("failed to query: %w", err)
 	}
 
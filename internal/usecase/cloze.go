@@ -0,0 +1,130 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"unicode/utf8"
+
+	"github.com/go-playground/validator/v10"
+
+	"ailingo/internal/domain"
+	"ailingo/pkg/cloze"
+	"ailingo/pkg/grader"
+)
+
+type clozeUseCase struct {
+	dataStore domain.DataStore
+	validate  *validator.Validate
+}
+
+func NewClozeUseCase(dataStore domain.DataStore, validate *validator.Validate) domain.ClozeUseCase {
+	return &clozeUseCase{
+		dataStore: dataStore,
+		validate:  validate,
+	}
+}
+
+func (uc *clozeUseCase) GetExercises(ctx context.Context, userID string, studySetID int64) (*domain.ClozeExercises, error) {
+	definitionRows, err := uc.getDefinitions(ctx, userID, studySetID)
+	if err != nil {
+		return nil, err
+	}
+
+	exercises := &domain.ClozeExercises{
+		Exercises: make([]*domain.ClozeExercise, 0),
+		Unlocated: make([]*domain.UnlocatedSentence, 0),
+	}
+
+	for _, definitionRow := range definitionRows {
+		for i, sentence := range definitionRow.Sentences {
+			exercise, ok := cloze.Make(sentence, definitionRow.Phrase)
+			if !ok {
+				exercises.Unlocated = append(exercises.Unlocated, &domain.UnlocatedSentence{
+					DefinitionId:  definitionRow.Id,
+					SentenceIndex: i,
+					Sentence:      sentence,
+				})
+				continue
+			}
+
+			firstLetter, _ := utf8.DecodeRuneInString(exercise.Answer)
+			exercises.Exercises = append(exercises.Exercises, &domain.ClozeExercise{
+				DefinitionId:  definitionRow.Id,
+				SentenceIndex: i,
+				Text:          exercise.Text,
+				Hint: domain.ClozeHint{
+					FirstLetter: string(firstLetter),
+					Length:      utf8.RuneCountInString(exercise.Answer),
+				},
+			})
+		}
+	}
+
+	return exercises, nil
+}
+
+func (uc *clozeUseCase) Check(ctx context.Context, userID string, studySetID int64, checkData *domain.ClozeCheckData) ([]*domain.ClozeAnswerResult, error) {
+	if err := uc.validate.Struct(checkData); err != nil {
+		return nil, fmt.Errorf("%w: invalid check data: %w", ErrValidation, err)
+	}
+
+	definitionRows, err := uc.getDefinitions(ctx, userID, studySetID)
+	if err != nil {
+		return nil, err
+	}
+
+	definitionsByID := make(map[int64]*domain.DefinitionRow, len(definitionRows))
+	for _, definitionRow := range definitionRows {
+		definitionsByID[definitionRow.Id] = definitionRow
+	}
+
+	results := make([]*domain.ClozeAnswerResult, 0, len(checkData.Answers))
+	for _, answer := range checkData.Answers {
+		definitionRow, ok := definitionsByID[answer.DefinitionId]
+		if !ok {
+			return nil, &ErrNotFound{
+				Resource: DefinitionResource,
+			}
+		}
+		if answer.SentenceIndex >= len(definitionRow.Sentences) {
+			return nil, fmt.Errorf("%w: sentence index out of range for definition %d", ErrValidation, answer.DefinitionId)
+		}
+
+		exercise, ok := cloze.Make(definitionRow.Sentences[answer.SentenceIndex], definitionRow.Phrase)
+		if !ok {
+			return nil, fmt.Errorf("%w: sentence %d of definition %d is not an exercise", ErrValidation, answer.SentenceIndex, answer.DefinitionId)
+		}
+
+		results = append(results, &domain.ClozeAnswerResult{
+			DefinitionId:  answer.DefinitionId,
+			SentenceIndex: answer.SentenceIndex,
+			Result:        grader.Grade(exercise.Answer, answer.Answer),
+		})
+	}
+
+	return results, nil
+}
+
+func (uc *clozeUseCase) getDefinitions(ctx context.Context, userID string, studySetID int64) ([]*domain.DefinitionRow, error) {
+	var definitionRows []*domain.DefinitionRow
+
+	err := uc.dataStore.Atomic(ctx, func(ds domain.DataStore) error {
+		_, err := newPermissionService(ds).Authorize(ctx, userID, studySetID, actionView)
+		if err != nil {
+			return err
+		}
+
+		definitionRows, err = ds.GetDefinitionRepo().GetAllFor(ctx, studySetID)
+		if err != nil {
+			return fmt.Errorf("%w: failed to get definitions for the study set: %w", ErrRepoFailed, err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("atomic operation failed: %w", err)
+	}
+
+	return definitionRows, nil
+}
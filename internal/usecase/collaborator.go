@@ -0,0 +1,154 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/go-playground/validator/v10"
+
+	"ailingo/internal/domain"
+	"ailingo/internal/mysql"
+)
+
+var (
+	ErrAlreadyCollaborator = errors.New("user is already a collaborator")
+)
+
+type collaboratorUseCase struct {
+	dataStore domain.DataStore
+	validate  *validator.Validate
+}
+
+func NewCollaboratorUseCase(dataStore domain.DataStore, validate *validator.Validate) domain.CollaboratorUseCase {
+	return &collaboratorUseCase{
+		dataStore: dataStore,
+		validate:  validate,
+	}
+}
+
+func (uc *collaboratorUseCase) GetAll(ctx context.Context, userID string, studySetID int64) ([]*domain.Collaborator, error) {
+	if _, err := newPermissionService(uc.dataStore).Authorize(ctx, userID, studySetID, actionEdit); err != nil {
+		return nil, err
+	}
+
+	collaborators, err := uc.dataStore.GetCollaboratorRepo().GetAll(ctx, studySetID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to get collaborators: %w", ErrRepoFailed, err)
+	}
+
+	return collaborators, nil
+}
+
+func (uc *collaboratorUseCase) Add(ctx context.Context, userID string, studySetID int64, addData *domain.AddCollaboratorData) error {
+	if err := uc.validate.Struct(addData); err != nil {
+		return fmt.Errorf("%w: invalid collaborator data: %w", ErrValidation, err)
+	}
+
+	err := uc.dataStore.Atomic(ctx, func(ds domain.DataStore) error {
+		studySet, err := newPermissionService(ds).Authorize(ctx, userID, studySetID, actionManage)
+		if err != nil {
+			return err
+		}
+		if studySet.Author.Id == addData.UserId {
+			return fmt.Errorf("%w: author cannot be a collaborator", ErrValidation)
+		}
+
+		user, err := ds.GetUserRepo().GetById(ctx, addData.UserId)
+		if err != nil {
+			return fmt.Errorf("%w: failed to get the user: %w", ErrRepoFailed, err)
+		}
+		if user == nil {
+			return &ErrNotFound{
+				Resource: UserResource,
+			}
+		}
+
+		if err := ds.GetCollaboratorRepo().Insert(ctx, studySetID, addData.UserId, addData.Role); err != nil {
+			if errors.Is(err, mysql.ErrDuplicateRow) {
+				return ErrAlreadyCollaborator
+			}
+			return fmt.Errorf("%w: failed to insert the collaborator: %w", ErrRepoFailed, err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return fmt.Errorf("atomic operation failed: %w", err)
+	}
+
+	return nil
+}
+
+func (uc *collaboratorUseCase) Update(ctx context.Context, userID string, studySetID int64, collaboratorID string, updateData *domain.UpdateCollaboratorData) error {
+	if err := uc.validate.Struct(updateData); err != nil {
+		return fmt.Errorf("%w: invalid collaborator data: %w", ErrValidation, err)
+	}
+
+	err := uc.dataStore.Atomic(ctx, func(ds domain.DataStore) error {
+		collaboratorRepo := ds.GetCollaboratorRepo()
+
+		if _, err := newPermissionService(ds).Authorize(ctx, userID, studySetID, actionManage); err != nil {
+			return err
+		}
+		if err := checkCollaboratorExists(ctx, collaboratorRepo, studySetID, collaboratorID); err != nil {
+			return err
+		}
+
+		if err := collaboratorRepo.UpdateRole(ctx, studySetID, collaboratorID, updateData.Role); err != nil {
+			return fmt.Errorf("%w: failed to update the collaborator: %w", ErrRepoFailed, err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return fmt.Errorf("atomic operation failed: %w", err)
+	}
+
+	return nil
+}
+
+func (uc *collaboratorUseCase) Remove(ctx context.Context, userID string, studySetID int64, collaboratorID string) error {
+	err := uc.dataStore.Atomic(ctx, func(ds domain.DataStore) error {
+		collaboratorRepo := ds.GetCollaboratorRepo()
+
+		// Collaborators can leave without the author's permission.
+		action := actionManage
+		if collaboratorID == userID {
+			action = actionView
+		}
+		if _, err := newPermissionService(ds).Authorize(ctx, userID, studySetID, action); err != nil {
+			return err
+		}
+		if err := checkCollaboratorExists(ctx, collaboratorRepo, studySetID, collaboratorID); err != nil {
+			return err
+		}
+
+		if err := collaboratorRepo.Delete(ctx, studySetID, collaboratorID); err != nil {
+			return fmt.Errorf("%w: failed to delete the collaborator: %w", ErrRepoFailed, err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return fmt.Errorf("atomic operation failed: %w", err)
+	}
+
+	return nil
+}
+
+func checkCollaboratorExists(ctx context.Context, collaboratorRepo domain.CollaboratorRepo, studySetID int64, collaboratorID string) error {
+	role, err := collaboratorRepo.GetRole(ctx, studySetID, collaboratorID)
+	if err != nil {
+		return fmt.Errorf("%w: failed to get the collaborator role: %w", ErrRepoFailed, err)
+	}
+	if role == "" {
+		return &ErrNotFound{
+			Resource: CollaboratorResource,
+		}
+	}
+	return nil
+}
@@ -2,6 +2,7 @@ package usecase
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"time"
@@ -9,6 +10,7 @@ import (
 	"github.com/go-playground/validator/v10"
 
 	"ailingo/internal/domain"
+	"ailingo/pkg/grader"
 )
 
 // definitionUseCase implements methods required by domain.DefinitionUseCase interface.
@@ -29,21 +31,14 @@ func NewDefinitionUseCase(l *slog.Logger, dataStore domain.DataStore, aiService
 	}
 }
 
-func (uc *definitionUseCase) GetAllFor(ctx context.Context, parentStudySetID int64) ([]*domain.Definition, error) {
+func (uc *definitionUseCase) GetAllFor(ctx context.Context, userID string, parentStudySetID int64) ([]*domain.Definition, error) {
 	var definitions []*domain.Definition
 
 	err := uc.dataStore.Atomic(ctx, func(ds domain.DataStore) error {
-		studySetRepo := ds.GetStudySetRepo()
 		definitionRepo := ds.GetDefinitionRepo()
 
-		parentExists, err := studySetRepo.Exists(ctx, parentStudySetID)
-		if err != nil {
-			return fmt.Errorf("%w: failed to check if parent study set exists: %w", ErrRepoFailed, err)
-		}
-		if !parentExists {
-			return &ErrNotFound{
-				Resource: DefinitionResource,
-			}
+		if _, err := newPermissionService(ds).Authorize(ctx, userID, parentStudySetID, actionView); err != nil {
+			return err
 		}
 
 		definitionRows, err := definitionRepo.GetAllFor(ctx, parentStudySetID)
@@ -72,18 +67,22 @@ func (uc *definitionUseCase) Create(ctx context.Context, userID string, parentSt
 	}
 
 	err := uc.dataStore.Atomic(ctx, func(ds domain.DataStore) error {
-		studySetRepo := ds.GetStudySetRepo()
 		definitionRepo := ds.GetDefinitionRepo()
 
-		if _, err := uc.checkStudySetOwnership(ctx, studySetRepo, userID, parentStudySetID); err != nil {
+		if _, err := newPermissionService(ds).Authorize(ctx, userID, parentStudySetID, actionEdit); err != nil {
 			return err
 		}
 
-		if err := definitionRepo.Insert(ctx, parentStudySetID, insertData); err != nil {
+		definitionID, err := definitionRepo.Insert(ctx, parentStudySetID, insertData)
+		if err != nil {
 			return fmt.Errorf("%w: failed to insert a new definition: %w", ErrRepoFailed, err)
 		}
 
-		return nil
+		return recordRevision(ctx, ds, userID, parentStudySetID, &domain.RevisionChange{
+			Target:          domain.RevisionTargetDefinition,
+			DefinitionId:    &definitionID,
+			DefinitionAfter: definitionSnapshot(insertData.Phrase, insertData.Meaning, insertData.Sentences),
+		})
 	})
 
 	if err != nil {
@@ -99,10 +98,13 @@ func (uc *definitionUseCase) Update(ctx context.Context, userID string, parentSt
 	}
 
 	err := uc.dataStore.Atomic(ctx, func(ds domain.DataStore) error {
-		studySetRepo := ds.GetStudySetRepo()
 		definitionRepo := ds.GetDefinitionRepo()
 
-		if _, err := uc.checkStudySetOwnership(ctx, studySetRepo, userID, parentStudySetID); err != nil {
+		if _, err := newPermissionService(ds).Authorize(ctx, userID, parentStudySetID, actionEdit); err != nil {
+			return err
+		}
+		definition, err := getStudySetDefinition(ctx, definitionRepo, parentStudySetID, definitionID)
+		if err != nil {
 			return err
 		}
 
@@ -110,7 +112,12 @@ func (uc *definitionUseCase) Update(ctx context.Context, userID string, parentSt
 			return fmt.Errorf("failed to update the definition: %w", err)
 		}
 
-		return nil
+		return recordRevision(ctx, ds, userID, parentStudySetID, &domain.RevisionChange{
+			Target:           domain.RevisionTargetDefinition,
+			DefinitionId:     &definitionID,
+			DefinitionBefore: definitionSnapshot(definition.Phrase, definition.Meaning, definition.Sentences),
+			DefinitionAfter:  definitionSnapshot(updateData.Phrase, updateData.Meaning, updateData.Sentences),
+		})
 	})
 
 	if err != nil {
@@ -122,11 +129,14 @@ func (uc *definitionUseCase) Update(ctx context.Context, userID string, parentSt
 
 func (uc *definitionUseCase) Delete(ctx context.Context, userID string, parentStudySetID int64, definitionID int64) error {
 	err := uc.dataStore.Atomic(ctx, func(ds domain.DataStore) error {
-		studySetRepo := ds.GetStudySetRepo()
 		definitionRepo := ds.GetDefinitionRepo()
 
 		// TODO: In theory study set can be deleted between checking if it exists and deleting it's definition. Should we use transaction for that?
-		if _, err := uc.checkStudySetOwnership(ctx, studySetRepo, userID, parentStudySetID); err != nil {
+		if _, err := newPermissionService(ds).Authorize(ctx, userID, parentStudySetID, actionEdit); err != nil {
+			return err
+		}
+		definition, err := getStudySetDefinition(ctx, definitionRepo, parentStudySetID, definitionID)
+		if err != nil {
 			return err
 		}
 
@@ -134,7 +144,11 @@ func (uc *definitionUseCase) Delete(ctx context.Context, userID string, parentSt
 			return fmt.Errorf("%w: failed to delete the definition: %w", ErrRepoFailed, err)
 		}
 
-		return nil
+		return recordRevision(ctx, ds, userID, parentStudySetID, &domain.RevisionChange{
+			Target:           domain.RevisionTargetDefinition,
+			DefinitionId:     &definitionID,
+			DefinitionBefore: definitionSnapshot(definition.Phrase, definition.Meaning, definition.Sentences),
+		})
 	})
 
 	if err != nil {
@@ -144,11 +158,191 @@ func (uc *definitionUseCase) Delete(ctx context.Context, userID string, parentSt
 	return nil
 }
 
+func (uc *definitionUseCase) Batch(ctx context.Context, userID string, parentStudySetID int64, batchData *domain.BatchDefinitionData) (*domain.BatchDefinitionResult, error) {
+	if err := uc.validate.Struct(batchData); err != nil {
+		return nil, fmt.Errorf("%w: invalid batch data: %w", ErrValidation, err)
+	}
+
+	var result *domain.BatchDefinitionResult
+
+	err := uc.dataStore.Atomic(ctx, func(ds domain.DataStore) error {
+		definitionRepo := ds.GetDefinitionRepo()
+
+		if _, err := newPermissionService(ds).Authorize(ctx, userID, parentStudySetID, actionEdit); err != nil {
+			return err
+		}
+
+		definitionRows, err := definitionRepo.GetAllFor(ctx, parentStudySetID)
+		if err != nil {
+			return fmt.Errorf("%w: failed to get all definitions for the study set: %w", ErrRepoFailed, err)
+		}
+
+		order := make([]int64, 0, len(definitionRows))
+		definitions := make(map[int64]*domain.DefinitionRow, len(definitionRows))
+		for _, definition := range definitionRows {
+			order = append(order, definition.Id)
+			definitions[definition.Id] = definition
+		}
+
+		// All the operations are checked, so that every invalid one is reported at once.
+		// Changes are not made after the first invalid operation, as the transaction is rolled back anyway.
+		createdIDs := make([]int64, 0)
+		invalid := make([]*domain.DefinitionOperationError, 0)
+		reordered := false
+		for i, operation := range batchData.Operations {
+			if err := uc.checkOperation(operation, definitions); err != nil {
+				invalid = append(invalid, &domain.DefinitionOperationError{
+					Index:   i,
+					Message: err.Error(),
+				})
+				continue
+			}
+
+			definitionID := operation.DefinitionId
+			switch operation.Type {
+			case domain.DefinitionOperationCreate:
+				// Definitions are created with negative placeholder ids while the batch is only being checked.
+				definitionID = -int64(i + 1)
+				if len(invalid) == 0 {
+					if definitionID, err = definitionRepo.Insert(ctx, parentStudySetID, operation.Definition); err != nil {
+						return fmt.Errorf("%w: failed to insert a new definition: %w", ErrRepoFailed, err)
+					}
+					if err := recordRevision(ctx, ds, userID, parentStudySetID, &domain.RevisionChange{
+						Target:          domain.RevisionTargetDefinition,
+						DefinitionId:    &definitionID,
+						DefinitionAfter: definitionSnapshot(operation.Definition.Phrase, operation.Definition.Meaning, operation.Definition.Sentences),
+					}); err != nil {
+						return err
+					}
+				}
+				createdIDs = append(createdIDs, definitionID)
+				definitions[definitionID] = &domain.DefinitionRow{
+					Id:        definitionID,
+					Phrase:    operation.Definition.Phrase,
+					Meaning:   operation.Definition.Meaning,
+					Sentences: operation.Definition.Sentences,
+				}
+
+				position := len(order)
+				if operation.Position != nil {
+					position = *operation.Position
+					reordered = true
+				}
+				order = placeAt(order, definitionID, position)
+			case domain.DefinitionOperationUpdate:
+				updateData := domain.UpdateDefinitionData(*operation.Definition)
+				if len(invalid) == 0 {
+					if err := definitionRepo.Update(ctx, definitionID, &updateData); err != nil {
+						return fmt.Errorf("%w: failed to update the definition: %w", ErrRepoFailed, err)
+					}
+					definition := definitions[definitionID]
+					if err := recordRevision(ctx, ds, userID, parentStudySetID, &domain.RevisionChange{
+						Target:           domain.RevisionTargetDefinition,
+						DefinitionId:     &definitionID,
+						DefinitionBefore: definitionSnapshot(definition.Phrase, definition.Meaning, definition.Sentences),
+						DefinitionAfter:  definitionSnapshot(updateData.Phrase, updateData.Meaning, updateData.Sentences),
+					}); err != nil {
+						return err
+					}
+				}
+				definitions[definitionID] = &domain.DefinitionRow{
+					Id:        definitionID,
+					Phrase:    updateData.Phrase,
+					Meaning:   updateData.Meaning,
+					Sentences: updateData.Sentences,
+				}
+			case domain.DefinitionOperationDelete:
+				if len(invalid) == 0 {
+					if err := definitionRepo.Delete(ctx, definitionID); err != nil {
+						return fmt.Errorf("%w: failed to delete the definition: %w", ErrRepoFailed, err)
+					}
+					definition := definitions[definitionID]
+					if err := recordRevision(ctx, ds, userID, parentStudySetID, &domain.RevisionChange{
+						Target:           domain.RevisionTargetDefinition,
+						DefinitionId:     &definitionID,
+						DefinitionBefore: definitionSnapshot(definition.Phrase, definition.Meaning, definition.Sentences),
+					}); err != nil {
+						return err
+					}
+				}
+				delete(definitions, definitionID)
+				order = withoutDefinition(order, definitionID)
+			case domain.DefinitionOperationMove:
+				order = placeAt(withoutDefinition(order, definitionID), definitionID, *operation.Position)
+				reordered = true
+			}
+		}
+
+		if len(invalid) > 0 {
+			return &ErrInvalidOperations{
+				Errors: invalid,
+			}
+		}
+
+		// Positions are not versioned, so reordering is not recorded as a revision.
+		if reordered {
+			for i, definitionID := range order {
+				if err := definitionRepo.SetPosition(ctx, definitionID, i); err != nil {
+					return fmt.Errorf("%w: failed to update the definition position: %w", ErrRepoFailed, err)
+				}
+			}
+		}
+
+		result = &domain.BatchDefinitionResult{
+			CreatedIds:  createdIDs,
+			Definitions: make([]*domain.Definition, 0, len(order)),
+		}
+		for _, definitionID := range order {
+			result.Definitions = append(result.Definitions, definitions[definitionID].Populate())
+		}
+		return nil
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("atomic operation failed: %w", err)
+	}
+
+	return result, nil
+}
+
+// checkOperation makes sure the operation is complete and that it refers to a definition
+// which is in the study set at that point of the batch.
+func (uc *definitionUseCase) checkOperation(operation *domain.DefinitionOperation, definitions map[int64]*domain.DefinitionRow) error {
+	if operation == nil {
+		return errors.New("operation is missing")
+	}
+
+	switch operation.Type {
+	case domain.DefinitionOperationCreate, domain.DefinitionOperationUpdate:
+		if operation.Definition == nil {
+			return errors.New("definition is required")
+		}
+		if err := uc.validate.Struct(operation.Definition); err != nil {
+			return fmt.Errorf("invalid definition: %w", err)
+		}
+	case domain.DefinitionOperationDelete:
+	case domain.DefinitionOperationMove:
+		if operation.Position == nil {
+			return errors.New("position is required")
+		}
+	default:
+		return fmt.Errorf("unknown operation type %q", operation.Type)
+	}
+
+	if operation.Position != nil && *operation.Position < 0 {
+		return errors.New("position must not be negative")
+	}
+	if operation.Type != domain.DefinitionOperationCreate && (operation.DefinitionId <= 0 || definitions[operation.DefinitionId] == nil) {
+		return fmt.Errorf("definition %d is not in the study set", operation.DefinitionId)
+	}
+
+	return nil
+}
+
 func (uc *definitionUseCase) AiFill(ctx context.Context, userID string, parentStudySetID int64) (int64, error) {
-	studySetRepo := uc.dataStore.GetStudySetRepo()
 	taskRepo := uc.dataStore.GetTaskRepo()
 
-	parentStudySet, err := uc.checkStudySetOwnership(ctx, studySetRepo, userID, parentStudySetID)
+	parentStudySet, err := newPermissionService(uc.dataStore).Authorize(ctx, userID, parentStudySetID, actionEdit)
 	if err != nil {
 		return 0, err
 	}
@@ -175,9 +369,17 @@ func (uc *definitionUseCase) AiFill(ctx context.Context, userID string, parentSt
 				definitionRepo := ds.GetDefinitionRepo()
 				for _, definition := range definitions {
 					definition.Sentences = []string{}
-					if err := definitionRepo.Insert(ctx, parentStudySetID, definition); err != nil {
+					definitionID, err := definitionRepo.Insert(ctx, parentStudySetID, definition)
+					if err != nil {
 						return fmt.Errorf("failed to insert a definition: %w", err)
 					}
+					if err := recordRevision(ctx, ds, userID, parentStudySetID, &domain.RevisionChange{
+						Target:          domain.RevisionTargetDefinition,
+						DefinitionId:    &definitionID,
+						DefinitionAfter: definitionSnapshot(definition.Phrase, definition.Meaning, definition.Sentences),
+					}); err != nil {
+						return err
+					}
 				}
 				return nil
 			})
@@ -209,18 +411,55 @@ func (uc *definitionUseCase) AiFill(ctx context.Context, userID string, parentSt
 	return taskId, nil
 }
 
-func (uc *definitionUseCase) checkStudySetOwnership(ctx context.Context, studySetRepo domain.StudySetRepo, userID string, studySetID int64) (*domain.StudySetWithAuthor, error) {
-	parentStudySet, err := studySetRepo.GetById(ctx, studySetID)
+func (uc *definitionUseCase) CheckAnswer(ctx context.Context, userID string, parentStudySetID int64, definitionID int64, answerData *domain.CheckAnswerData) (*grader.Result, error) {
+	if err := uc.validate.Struct(answerData); err != nil {
+		return nil, fmt.Errorf("%w: invalid answer data: %w", ErrValidation, err)
+	}
+
+	if _, err := newPermissionService(uc.dataStore).Authorize(ctx, userID, parentStudySetID, actionView); err != nil {
+		return nil, err
+	}
+
+	definition, err := uc.dataStore.GetDefinitionRepo().Get(ctx, parentStudySetID, definitionID)
 	if err != nil {
-		return nil, fmt.Errorf("%w: failed to get the parent study set: %w", ErrRepoFailed, err)
+		return nil, fmt.Errorf("%w: failed to get the definition: %w", ErrRepoFailed, err)
 	}
-	if parentStudySet == nil {
+	if definition == nil {
 		return nil, &ErrNotFound{
-			Resource: StudySetResource,
+			Resource: DefinitionResource,
+		}
+	}
+
+	expected := definition.Meaning
+	if answerData.Field == domain.CheckFieldPhrase {
+		expected = definition.Phrase
+	}
+
+	return grader.Grade(expected, answerData.Answer), nil
+}
+
+// withoutDefinition returns the ordered definition ids without the given one.
+func withoutDefinition(order []int64, definitionID int64) []int64 {
+	without := make([]int64, 0, len(order))
+	for _, id := range order {
+		if id != definitionID {
+			without = append(without, id)
 		}
 	}
-	if parentStudySet.Author.Id != userID {
-		return nil, ErrForbidden
+	return without
+}
+
+// getStudySetDefinition gets the definition making sure it belongs to the study set,
+// so that permissions to the study set cannot be used to change definitions of other study sets.
+func getStudySetDefinition(ctx context.Context, definitionRepo domain.DefinitionRepo, parentStudySetID int64, definitionID int64) (*domain.DefinitionRow, error) {
+	definition, err := definitionRepo.Get(ctx, parentStudySetID, definitionID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to get the definition: %w", ErrRepoFailed, err)
+	}
+	if definition == nil {
+		return nil, &ErrNotFound{
+			Resource: DefinitionResource,
+		}
 	}
-	return parentStudySet, nil
+	return definition, nil
 }
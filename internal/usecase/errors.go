@@ -3,12 +3,21 @@ package usecase
 import (
 	"errors"
 	"fmt"
+
+	"ailingo/internal/domain"
 )
 
 const StudySetResource = "study_set"
 const DefinitionResource = "definition"
 const TaskResource = "task"
 const StudySessionResource = "study_session"
+const QuizResource = "quiz"
+const ExamResource = "exam"
+const PlacementTestResource = "placement_test"
+const UserResource = "user"
+const CollaboratorResource = "collaborator"
+const RevisionResource = "revision"
+const FolderResource = "folder"
 
 // ErrNotFound means that resource was not found.
 type ErrNotFound struct {
@@ -19,6 +28,19 @@ func (err *ErrNotFound) Error() string {
 	return fmt.Sprintf("resource not found: [%s]", err.Resource)
 }
 
+// ErrInvalidOperations means that some operations of a batch did not meet validation requirements.
+type ErrInvalidOperations struct {
+	Errors []*domain.DefinitionOperationError
+}
+
+func (err *ErrInvalidOperations) Error() string {
+	return fmt.Sprintf("%d invalid operations", len(err.Errors))
+}
+
+func (err *ErrInvalidOperations) Unwrap() error {
+	return ErrValidation
+}
+
 var (
 	// ErrForbidden represents an error meaning that user didn't have
 	// enough permissions to perform the operation.
@@ -0,0 +1,434 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+
+	"ailingo/internal/domain"
+	"ailingo/pkg/cloze"
+	"ailingo/pkg/grader"
+)
+
+const (
+	defaultExamQuestionCount = 20
+	defaultExamTimeLimit     = 10 * time.Minute
+
+	// examSubmitGrace gives clients auto submitting at the deadline some room for network latency.
+	examSubmitGrace = 30 * time.Second
+)
+
+var defaultExamQuestionTypes = []string{
+	domain.ExamQuestionMultipleChoice,
+	domain.ExamQuestionTyped,
+	domain.ExamQuestionCloze,
+}
+
+var (
+	ErrExamAlreadySubmitted = errors.New("exam has already been submitted")
+	ErrExamNotSubmitted     = errors.New("exam has not been submitted yet")
+)
+
+type examUseCase struct {
+	dataStore domain.DataStore
+	validate  *validator.Validate
+}
+
+func NewExamUseCase(dataStore domain.DataStore, validate *validator.Validate) domain.ExamUseCase {
+	return &examUseCase{
+		dataStore: dataStore,
+		validate:  validate,
+	}
+}
+
+func (uc *examUseCase) Create(ctx context.Context, userID string, studySetID int64, createData *domain.CreateExamData) (*domain.Exam, error) {
+	if err := uc.validate.Struct(createData); err != nil {
+		return nil, fmt.Errorf("%w: invalid create data: %w", ErrValidation, err)
+	}
+
+	questionCount := createData.QuestionCount
+	if questionCount == 0 {
+		questionCount = defaultExamQuestionCount
+	}
+	timeLimit := defaultExamTimeLimit
+	if createData.TimeLimitSeconds != 0 {
+		timeLimit = time.Duration(createData.TimeLimitSeconds) * time.Second
+	}
+	questionTypes := createData.QuestionTypes
+	if len(questionTypes) == 0 {
+		questionTypes = defaultExamQuestionTypes
+	}
+
+	var examID int64
+
+	err := uc.dataStore.Atomic(ctx, func(ds domain.DataStore) error {
+		definitionRepo := ds.GetDefinitionRepo()
+		examRepo := ds.GetExamRepo()
+
+		studySet, err := newPermissionService(ds).Authorize(ctx, userID, studySetID, actionView)
+		if err != nil {
+			return err
+		}
+
+		definitionRows, err := definitionRepo.GetAllFor(ctx, studySetID)
+		if err != nil {
+			return fmt.Errorf("%w: failed to get definitions for the study set: %w", ErrRepoFailed, err)
+		}
+		if len(definitionRows) == 0 {
+			return ErrNotEnoughDefinitions
+		}
+
+		// Same as in quizzes, small study sets borrow distractors from study sets in the same languages.
+		var pool []*domain.DefinitionRow
+		if len(uniqueQuizAnswers(definitionRows, domain.QuizDirectionPhraseToMeaning)) < quizChoiceCount {
+			pool, err = definitionRepo.GetSample(ctx, studySet.PhraseLanguage, studySet.DefinitionLanguage, studySetID, quizChoiceCount*questionCount)
+			if err != nil {
+				return fmt.Errorf("%w: failed to get definitions for distractors: %w", ErrRepoFailed, err)
+			}
+		}
+
+		questions := buildExamQuestions(definitionRows, pool, questionTypes, questionCount)
+		if len(questions) == 0 {
+			return ErrNotEnoughDefinitions
+		}
+
+		startedAt := time.Now()
+		deadlineAt := startedAt.Add(timeLimit)
+
+		examID, err = examRepo.Insert(ctx, &domain.ExamRow{
+			UserId:           userID,
+			StudySetId:       studySetID,
+			TimeLimitSeconds: int(timeLimit.Seconds()),
+			Total:            len(questions),
+			StartedAt:        &startedAt,
+			DeadlineAt:       &deadlineAt,
+		})
+		if err != nil {
+			return fmt.Errorf("%w: failed to insert the exam: %w", ErrRepoFailed, err)
+		}
+
+		for i, question := range questions {
+			if _, err := examRepo.InsertQuestion(ctx, examID, i, question); err != nil {
+				return fmt.Errorf("%w: failed to insert an exam question: %w", ErrRepoFailed, err)
+			}
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("atomic operation failed: %w", err)
+	}
+
+	return uc.Get(ctx, userID, examID)
+}
+
+func (uc *examUseCase) Get(ctx context.Context, userID string, examID int64) (*domain.Exam, error) {
+	examRepo := uc.dataStore.GetExamRepo()
+
+	examRow, err := getOwnExam(ctx, examRepo, userID, examID)
+	if err != nil {
+		return nil, err
+	}
+
+	questionRows, err := examRepo.GetQuestions(ctx, examID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to get exam questions: %w", ErrRepoFailed, err)
+	}
+
+	exam := &domain.Exam{
+		Id:               examRow.Id,
+		StudySetId:       examRow.StudySetId,
+		TimeLimitSeconds: examRow.TimeLimitSeconds,
+		StartedAt:        examRow.StartedAt,
+		DeadlineAt:       examRow.DeadlineAt,
+		SubmittedAt:      examRow.SubmittedAt,
+		Questions:        make([]*domain.ExamQuestion, 0, len(questionRows)),
+	}
+	for _, questionRow := range questionRows {
+		exam.Questions = append(exam.Questions, questionRow.Populate())
+	}
+
+	return exam, nil
+}
+
+func (uc *examUseCase) GetAttempts(ctx context.Context, userID string, studySetID int64) ([]*domain.ExamAttempt, error) {
+	attempts, err := uc.dataStore.GetExamRepo().GetAttempts(ctx, userID, studySetID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to get exam attempts: %w", ErrRepoFailed, err)
+	}
+	return attempts, nil
+}
+
+func (uc *examUseCase) Submit(ctx context.Context, userID string, examID int64, submitData *domain.SubmitExamData) (*domain.ExamReport, error) {
+	if err := uc.validate.Struct(submitData); err != nil {
+		return nil, fmt.Errorf("%w: invalid submit data: %w", ErrValidation, err)
+	}
+
+	var report *domain.ExamReport
+
+	err := uc.dataStore.Atomic(ctx, func(ds domain.DataStore) error {
+		examRepo := ds.GetExamRepo()
+
+		examRow, err := getOwnExam(ctx, examRepo, userID, examID)
+		if err != nil {
+			return err
+		}
+		if examRow.SubmittedAt != nil {
+			return ErrExamAlreadySubmitted
+		}
+
+		questionRows, err := examRepo.GetQuestions(ctx, examID)
+		if err != nil {
+			return fmt.Errorf("%w: failed to get exam questions: %w", ErrRepoFailed, err)
+		}
+
+		questionsByID := make(map[int64]*domain.ExamQuestionRow, len(questionRows))
+		for _, questionRow := range questionRows {
+			questionsByID[questionRow.Id] = questionRow
+		}
+
+		responses := make(map[int64]*string, len(submitData.Answers))
+		for _, answer := range submitData.Answers {
+			question, ok := questionsByID[answer.QuestionId]
+			if !ok {
+				return fmt.Errorf("%w: question %d does not belong to the exam", ErrValidation, answer.QuestionId)
+			}
+
+			response, err := examResponse(question, answer)
+			if err != nil {
+				return err
+			}
+			responses[question.Id] = response
+		}
+
+		submittedAt := time.Now()
+		timedOut := submittedAt.After(examRow.DeadlineAt.Add(examSubmitGrace))
+
+		// Responses submitted after the deadline are kept, but they are not graded and do not count to the score.
+		score := 0
+		for _, question := range questionRows {
+			response, ok := responses[question.Id]
+			if !ok || response == nil {
+				continue
+			}
+			question.Response = response
+
+			if !timedOut {
+				correct := gradeExamResponse(question, *response)
+				if correct {
+					score++
+				}
+				question.Correct = &correct
+			}
+
+			if err := examRepo.SaveResponse(ctx, question.Id, response, question.Correct); err != nil {
+				return fmt.Errorf("%w: failed to save the response: %w", ErrRepoFailed, err)
+			}
+		}
+
+		// The exam may have been submitted by a concurrent request since it was read.
+		submitted, err := examRepo.Submit(ctx, examID, score, timedOut, submittedAt)
+		if err != nil {
+			return fmt.Errorf("%w: failed to submit the exam: %w", ErrRepoFailed, err)
+		}
+		if !submitted {
+			return ErrExamAlreadySubmitted
+		}
+
+		examRow.Score = &score
+		examRow.TimedOut = timedOut
+		examRow.SubmittedAt = &submittedAt
+		report = examReport(examRow, questionRows)
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("atomic operation failed: %w", err)
+	}
+
+	return report, nil
+}
+
+func (uc *examUseCase) GetReport(ctx context.Context, userID string, examID int64) (*domain.ExamReport, error) {
+	examRepo := uc.dataStore.GetExamRepo()
+
+	examRow, err := getOwnExam(ctx, examRepo, userID, examID)
+	if err != nil {
+		return nil, err
+	}
+	// Reports reveal the answers, so they are hidden until the exam is over.
+	if examRow.SubmittedAt == nil {
+		return nil, ErrExamNotSubmitted
+	}
+
+	questionRows, err := examRepo.GetQuestions(ctx, examID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to get exam questions: %w", ErrRepoFailed, err)
+	}
+
+	return examReport(examRow, questionRows), nil
+}
+
+// getOwnExam gets the exam and makes sure it belongs to the given user.
+func getOwnExam(ctx context.Context, examRepo domain.ExamRepo, userID string, examID int64) (*domain.ExamRow, error) {
+	examRow, err := examRepo.Get(ctx, examID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to get the exam: %w", ErrRepoFailed, err)
+	}
+	if examRow == nil {
+		return nil, &ErrNotFound{
+			Resource: ExamResource,
+		}
+	}
+	if examRow.UserId != userID {
+		return nil, ErrForbidden
+	}
+	return examRow, nil
+}
+
+// examResponse extracts the response to the question from the answer.
+// Multiple choice responses are stored as the text of the picked choice.
+// Nil is returned for blank answers, so that they are treated as unanswered.
+func examResponse(question *domain.ExamQuestionRow, answer *domain.ExamAnswerData) (*string, error) {
+	if question.Type == domain.ExamQuestionMultipleChoice {
+		if answer.ChoiceIndex == nil {
+			return nil, nil
+		}
+		if *answer.ChoiceIndex >= len(question.Choices) {
+			return nil, fmt.Errorf("%w: choice index out of range for question %d", ErrValidation, question.Id)
+		}
+		return &question.Choices[*answer.ChoiceIndex], nil
+	}
+
+	if answer.Answer == "" {
+		return nil, nil
+	}
+	response := answer.Answer
+	return &response, nil
+}
+
+// gradeExamResponse checks if the response is correct.
+// Typed answers are graded leniently, close answers are accepted.
+func gradeExamResponse(question *domain.ExamQuestionRow, response string) bool {
+	if question.Type == domain.ExamQuestionMultipleChoice {
+		return response == question.Answer
+	}
+	return grader.Grade(question.Answer, response).Verdict != grader.VerdictWrong
+}
+
+// examReport builds the report of a submitted exam.
+func examReport(examRow *domain.ExamRow, questionRows []*domain.ExamQuestionRow) *domain.ExamReport {
+	report := &domain.ExamReport{
+		ExamId:      examRow.Id,
+		StudySetId:  examRow.StudySetId,
+		Total:       examRow.Total,
+		TimedOut:    examRow.TimedOut,
+		StartedAt:   examRow.StartedAt,
+		SubmittedAt: examRow.SubmittedAt,
+		Questions:   make([]*domain.ExamQuestionFeedback, 0, len(questionRows)),
+	}
+	if examRow.Score != nil {
+		report.Score = *examRow.Score
+	}
+
+	for _, question := range questionRows {
+		feedback := &domain.ExamQuestionFeedback{
+			QuestionId:   question.Id,
+			DefinitionId: question.DefinitionId,
+			Type:         question.Type,
+			Prompt:       question.Prompt,
+			Expected:     question.Answer,
+			Response:     question.Response,
+			Correct:      question.Correct != nil && *question.Correct,
+			Graded:       question.Correct != nil,
+		}
+		if question.Type != domain.ExamQuestionMultipleChoice && question.Response != nil && question.Correct != nil {
+			feedback.Grading = grader.Grade(question.Answer, *question.Response)
+		}
+
+		report.Questions = append(report.Questions, feedback)
+	}
+
+	return report
+}
+
+// buildExamQuestions generates up to count questions about random definitions, one question per definition.
+// Question types are rotated, so that the exam mixes them. If a definition does not support
+// the next type (e.g. no example sentence for a cloze question) the following type is tried.
+func buildExamQuestions(definitions []*domain.DefinitionRow, pool []*domain.DefinitionRow, questionTypes []string, count int) []*domain.ExamQuestionRow {
+	shuffled := make([]*domain.DefinitionRow, len(definitions))
+	copy(shuffled, definitions)
+	rand.Shuffle(len(shuffled), func(i, j int) {
+		shuffled[i], shuffled[j] = shuffled[j], shuffled[i]
+	})
+
+	setAnswers := uniqueQuizAnswers(definitions, domain.QuizDirectionPhraseToMeaning)
+	poolAnswers := uniqueQuizAnswers(pool, domain.QuizDirectionPhraseToMeaning)
+
+	questions := make([]*domain.ExamQuestionRow, 0, count)
+	for _, definition := range shuffled {
+		if len(questions) == count {
+			break
+		}
+
+		offset := len(questions)
+		for i := range questionTypes {
+			questionType := questionTypes[(offset+i)%len(questionTypes)]
+			if question, ok := buildExamQuestion(definition, questionType, setAnswers, poolAnswers); ok {
+				questions = append(questions, question)
+				break
+			}
+		}
+	}
+
+	return questions
+}
+
+// buildExamQuestion generates a question of the given type about the definition.
+func buildExamQuestion(definition *domain.DefinitionRow, questionType string, setAnswers []string, poolAnswers []string) (*domain.ExamQuestionRow, bool) {
+	question := &domain.ExamQuestionRow{
+		DefinitionId: definition.Id,
+		Type:         questionType,
+	}
+
+	switch questionType {
+	case domain.ExamQuestionMultipleChoice:
+		distractors := pickDistractors(definition.Meaning, setAnswers, poolAnswers, quizChoiceCount-1)
+		if len(distractors) == 0 {
+			return nil, false
+		}
+
+		choices := append(distractors, definition.Meaning)
+		rand.Shuffle(len(choices), func(i, j int) {
+			choices[i], choices[j] = choices[j], choices[i]
+		})
+
+		question.Prompt = definition.Phrase
+		question.Choices = choices
+		question.Answer = definition.Meaning
+	case domain.ExamQuestionTyped:
+		question.Prompt = definition.Meaning
+		question.Answer = definition.Phrase
+	case domain.ExamQuestionCloze:
+		for _, i := range rand.Perm(len(definition.Sentences)) {
+			exercise, ok := cloze.Make(definition.Sentences[i], definition.Phrase)
+			if !ok {
+				continue
+			}
+			question.Prompt = exercise.Text
+			question.Answer = exercise.Answer
+			return question, true
+		}
+		return nil, false
+	default:
+		return nil, false
+	}
+
+	return question, true
+}
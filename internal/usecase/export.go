@@ -0,0 +1,209 @@
+package usecase
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"strings"
+	"time"
+
+	"ailingo/internal/domain"
+)
+
+var printableHeader = template.Must(template.New("header").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>{{.Name}}</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+table { border-collapse: collapse; width: 100%; }
+td { border: 1px solid #ccc; padding: 0.5em; vertical-align: top; }
+tr { break-inside: avoid; }
+.sentence { color: #555; font-style: italic; }
+</style>
+</head>
+<body>
+<h1>{{.Name}}</h1>
+{{if .Description}}<p>{{.Description}}</p>
+{{end}}<table>
+`))
+
+var printableRow = template.Must(template.New("row").Parse(`<tr><td>{{.Phrase}}</td><td>{{.Meaning}}{{range .Sentences}}<div class="sentence">{{.}}</div>{{end}}</td></tr>
+`))
+
+const printableFooter = `</table>
+</body>
+</html>
+`
+
+type exportUseCase struct {
+	dataStore domain.DataStore
+}
+
+func NewExportUseCase(dataStore domain.DataStore) domain.ExportUseCase {
+	return &exportUseCase{
+		dataStore: dataStore,
+	}
+}
+
+func (uc *exportUseCase) Export(ctx context.Context, userID string, studySetID int64, format string, w io.Writer) error {
+	studySet, err := newPermissionService(uc.dataStore).Authorize(ctx, userID, studySetID, actionView)
+	if err != nil {
+		return err
+	}
+
+	// Writes are buffered, so that every definition does not end up in a separate chunk of the response.
+	buffered := bufio.NewWriter(w)
+
+	var writeDefinition func(definition *domain.DefinitionRow) error
+	var finish func() error
+	switch format {
+	case domain.ExportFormatCSV:
+		writeDefinition, finish = exportCSV(buffered)
+	case domain.ExportFormatTSV:
+		writeDefinition, finish = exportTSV(buffered)
+	case domain.ExportFormatJSON:
+		tags, err := uc.dataStore.GetTagRepo().GetForStudySet(ctx, studySetID)
+		if err != nil {
+			return fmt.Errorf("%w: failed to get tags of the study set: %w", ErrRepoFailed, err)
+		}
+		if writeDefinition, finish, err = exportJSON(buffered, studySet, tags); err != nil {
+			return err
+		}
+	case domain.ExportFormatHTML:
+		if writeDefinition, finish, err = exportHTML(buffered, studySet); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("%w: unknown export format %q", ErrValidation, format)
+	}
+
+	if err := uc.dataStore.GetDefinitionRepo().ForEach(ctx, studySetID, writeDefinition); err != nil {
+		return fmt.Errorf("%w: failed to export definitions: %w", ErrRepoFailed, err)
+	}
+	if err := finish(); err != nil {
+		return fmt.Errorf("failed to finish the export: %w", err)
+	}
+
+	return buffered.Flush()
+}
+
+// exportCSV writes each definition as a row with the phrase, the meaning and one sentence per remaining column,
+// which is the layout expected by the import.
+func exportCSV(w *bufio.Writer) (func(definition *domain.DefinitionRow) error, func() error) {
+	writer := csv.NewWriter(w)
+
+	writeDefinition := func(definition *domain.DefinitionRow) error {
+		return writer.Write(append([]string{definition.Phrase, definition.Meaning}, definition.Sentences...))
+	}
+
+	finish := func() error {
+		writer.Flush()
+		return writer.Error()
+	}
+
+	return writeDefinition, finish
+}
+
+// exportTSV writes definitions in the same layout as exportCSV. TSV has no quoting,
+// so tabs and line breaks inside the terms are replaced with spaces.
+func exportTSV(w *bufio.Writer) (func(definition *domain.DefinitionRow) error, func() error) {
+	writeDefinition := func(definition *domain.DefinitionRow) error {
+		terms := append([]string{definition.Phrase, definition.Meaning}, definition.Sentences...)
+		for i, term := range terms {
+			terms[i] = strings.Join(strings.Fields(term), " ")
+		}
+		_, err := w.WriteString(strings.Join(terms, "\t") + "\n")
+		return err
+	}
+
+	finish := func() error {
+		return nil
+	}
+
+	return writeDefinition, finish
+}
+
+// exportJSON writes the bundle piece by piece, as the definitions are not known up front.
+func exportJSON(w *bufio.Writer, studySet *domain.StudySetWithAuthor, tags []string) (func(definition *domain.DefinitionRow) error, func() error, error) {
+	if tags == nil {
+		tags = []string{}
+	}
+	metadata, err := json.Marshal(&domain.ExportedStudySet{
+		Name:               studySet.Name,
+		Description:        studySet.Description,
+		PhraseLanguage:     studySet.PhraseLanguage,
+		DefinitionLanguage: studySet.DefinitionLanguage,
+		Icon:               studySet.Icon,
+		Color:              studySet.Color,
+		CefrLevel:          studySet.CefrLevel,
+		Category:           studySet.Category,
+		Tags:               tags,
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal the study set: %w", err)
+	}
+	exportedAt, err := json.Marshal(time.Now().UTC())
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to marshal the export time: %w", err)
+	}
+
+	if _, err := fmt.Fprintf(w, `{"version":%d,"exportedAt":%s,"studySet":%s,"definitions":[`, domain.ExportBundleVersion, exportedAt, metadata); err != nil {
+		return nil, nil, err
+	}
+
+	first := true
+	writeDefinition := func(definition *domain.DefinitionRow) error {
+		sentences := definition.Sentences
+		if sentences == nil {
+			sentences = []string{}
+		}
+		item, err := json.Marshal(&domain.InsertDefinitionData{
+			Phrase:    definition.Phrase,
+			Meaning:   definition.Meaning,
+			Sentences: sentences,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to marshal the definition: %w", err)
+		}
+
+		if !first {
+			if err := w.WriteByte(','); err != nil {
+				return err
+			}
+		}
+		first = false
+		_, err = w.Write(item)
+		return err
+	}
+
+	finish := func() error {
+		_, err := w.WriteString("]}\n")
+		return err
+	}
+
+	return writeDefinition, finish, nil
+}
+
+// exportHTML writes a page meant to be printed, with phrases on the left and meanings with sentences on the right.
+func exportHTML(w *bufio.Writer, studySet *domain.StudySetWithAuthor) (func(definition *domain.DefinitionRow) error, func() error, error) {
+	if err := printableHeader.Execute(w, studySet); err != nil {
+		return nil, nil, fmt.Errorf("failed to execute the header template: %w", err)
+	}
+
+	writeDefinition := func(definition *domain.DefinitionRow) error {
+		return printableRow.Execute(w, definition)
+	}
+
+	finish := func() error {
+		_, err := w.WriteString(printableFooter)
+		return err
+	}
+
+	return writeDefinition, finish, nil
+}
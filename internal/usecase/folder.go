@@ -0,0 +1,579 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/go-playground/validator/v10"
+
+	"ailingo/internal/domain"
+	"ailingo/internal/mysql"
+)
+
+// maxFolderDepth is the maximum number of nested folder levels, top level folders included.
+const maxFolderDepth = 10
+
+var (
+	ErrAlreadyInFolder = errors.New("study set is already in the folder")
+)
+
+type folderUseCase struct {
+	dataStore domain.DataStore
+	validate  *validator.Validate
+}
+
+func NewFolderUseCase(dataStore domain.DataStore, validate *validator.Validate) domain.FolderUseCase {
+	return &folderUseCase{
+		dataStore: dataStore,
+		validate:  validate,
+	}
+}
+
+func (uc *folderUseCase) GetAll(ctx context.Context, userID string) ([]*domain.Folder, error) {
+	tree, err := getFolderTree(ctx, uc.dataStore, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	folders := make([]*domain.Folder, 0)
+	for _, row := range tree.rows {
+		if row.ParentId == nil {
+			folders = append(folders, tree.populate(row.Id))
+		}
+	}
+
+	return folders, nil
+}
+
+func (uc *folderUseCase) Get(ctx context.Context, userID string, folderID int64) (*domain.FolderDetails, error) {
+	var details *domain.FolderDetails
+
+	err := uc.dataStore.Atomic(ctx, func(ds domain.DataStore) error {
+		if _, err := getOwnFolder(ctx, ds, userID, folderID); err != nil {
+			return err
+		}
+
+		tree, err := getFolderTree(ctx, ds, userID)
+		if err != nil {
+			return err
+		}
+
+		folder := tree.folders[folderID].Populate()
+		for _, childID := range tree.children[folderID] {
+			folder.Children = append(folder.Children, tree.folders[childID].Populate())
+		}
+
+		studySets, err := getFolderStudySets(ctx, ds, userID, folderID)
+		if err != nil {
+			return err
+		}
+
+		details = &domain.FolderDetails{
+			Folder:    *folder,
+			StudySets: studySets,
+		}
+		return nil
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("atomic operation failed: %w", err)
+	}
+
+	return details, nil
+}
+
+func (uc *folderUseCase) Create(ctx context.Context, userID string, insertData *domain.InsertFolderData) (int64, error) {
+	if err := uc.validate.Struct(insertData); err != nil {
+		return 0, fmt.Errorf("%w: invalid insert data: %w", ErrValidation, err)
+	}
+
+	var folderID int64
+
+	err := uc.dataStore.Atomic(ctx, func(ds domain.DataStore) error {
+		tree, err := getFolderTree(ctx, ds, userID)
+		if err != nil {
+			return err
+		}
+		if err := tree.checkParent(insertData.ParentId, 1); err != nil {
+			return err
+		}
+
+		// New folders are put after their siblings.
+		folderID, err = ds.GetFolderRepo().Insert(ctx, userID, insertData.ParentId, insertData.Name, len(tree.siblings(insertData.ParentId)))
+		if err != nil {
+			return fmt.Errorf("%w: failed to insert the folder: %w", ErrRepoFailed, err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return 0, fmt.Errorf("atomic operation failed: %w", err)
+	}
+
+	return folderID, nil
+}
+
+func (uc *folderUseCase) Update(ctx context.Context, userID string, folderID int64, updateData *domain.UpdateFolderData) error {
+	if err := uc.validate.Struct(updateData); err != nil {
+		return fmt.Errorf("%w: invalid update data: %w", ErrValidation, err)
+	}
+
+	err := uc.dataStore.Atomic(ctx, func(ds domain.DataStore) error {
+		folderRepo := ds.GetFolderRepo()
+
+		folder, err := getOwnFolder(ctx, ds, userID, folderID)
+		if err != nil {
+			return err
+		}
+
+		tree, err := getFolderTree(ctx, ds, userID)
+		if err != nil {
+			return err
+		}
+
+		sameParent := sameFolder(folder.ParentId, updateData.ParentId)
+		if !sameParent {
+			if updateData.ParentId != nil && tree.contains(folderID, *updateData.ParentId) {
+				return fmt.Errorf("%w: folder cannot be moved into itself", ErrValidation)
+			}
+			if err := tree.checkParent(updateData.ParentId, tree.height(folderID)); err != nil {
+				return err
+			}
+		}
+
+		if sameParent && updateData.Position == nil {
+			if err := folderRepo.Update(ctx, folderID, folder.ParentId, updateData.Name, folder.Position); err != nil {
+				return fmt.Errorf("%w: failed to update the folder: %w", ErrRepoFailed, err)
+			}
+			return nil
+		}
+
+		siblings := make([]int64, 0)
+		for _, siblingID := range tree.siblings(updateData.ParentId) {
+			if siblingID != folderID {
+				siblings = append(siblings, siblingID)
+			}
+		}
+		position := len(siblings)
+		if updateData.Position != nil {
+			position = *updateData.Position
+		}
+
+		for i, id := range placeAt(siblings, folderID, position) {
+			if id == folderID {
+				if err := folderRepo.Update(ctx, folderID, updateData.ParentId, updateData.Name, i); err != nil {
+					return fmt.Errorf("%w: failed to update the folder: %w", ErrRepoFailed, err)
+				}
+				continue
+			}
+			if tree.folders[id].Position == i {
+				continue
+			}
+			if err := folderRepo.SetPosition(ctx, id, i); err != nil {
+				return fmt.Errorf("%w: failed to update the folder position: %w", ErrRepoFailed, err)
+			}
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return fmt.Errorf("atomic operation failed: %w", err)
+	}
+
+	return nil
+}
+
+func (uc *folderUseCase) Delete(ctx context.Context, userID string, folderID int64) error {
+	err := uc.dataStore.Atomic(ctx, func(ds domain.DataStore) error {
+		if _, err := getOwnFolder(ctx, ds, userID, folderID); err != nil {
+			return err
+		}
+
+		tree, err := getFolderTree(ctx, ds, userID)
+		if err != nil {
+			return err
+		}
+
+		for _, id := range tree.subtree(folderID) {
+			if err := ds.GetFolderRepo().Delete(ctx, id); err != nil {
+				return fmt.Errorf("%w: failed to delete the folder: %w", ErrRepoFailed, err)
+			}
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return fmt.Errorf("atomic operation failed: %w", err)
+	}
+
+	return nil
+}
+
+func (uc *folderUseCase) AddStudySet(ctx context.Context, userID string, folderID int64, addData *domain.AddFolderStudySetData) error {
+	if err := uc.validate.Struct(addData); err != nil {
+		return fmt.Errorf("%w: invalid study set data: %w", ErrValidation, err)
+	}
+
+	err := uc.dataStore.Atomic(ctx, func(ds domain.DataStore) error {
+		folderRepo := ds.GetFolderRepo()
+
+		if _, err := getOwnFolder(ctx, ds, userID, folderID); err != nil {
+			return err
+		}
+		if _, err := newPermissionService(ds).Authorize(ctx, userID, addData.StudySetId, actionView); err != nil {
+			return err
+		}
+
+		studySetIDs, err := folderRepo.GetStudySetIds(ctx, folderID)
+		if err != nil {
+			return fmt.Errorf("%w: failed to get folder study sets: %w", ErrRepoFailed, err)
+		}
+
+		if err := folderRepo.AddStudySet(ctx, folderID, addData.StudySetId, len(studySetIDs)); err != nil {
+			if errors.Is(err, mysql.ErrDuplicateRow) {
+				return ErrAlreadyInFolder
+			}
+			return fmt.Errorf("%w: failed to add the study set to the folder: %w", ErrRepoFailed, err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return fmt.Errorf("atomic operation failed: %w", err)
+	}
+
+	return nil
+}
+
+func (uc *folderUseCase) MoveStudySet(ctx context.Context, userID string, folderID int64, studySetID int64, moveData *domain.MoveFolderStudySetData) error {
+	if err := uc.validate.Struct(moveData); err != nil {
+		return fmt.Errorf("%w: invalid move data: %w", ErrValidation, err)
+	}
+
+	err := uc.dataStore.Atomic(ctx, func(ds domain.DataStore) error {
+		folderRepo := ds.GetFolderRepo()
+
+		studySetIDs, err := getFolderStudySetIds(ctx, ds, userID, folderID, studySetID)
+		if err != nil {
+			return err
+		}
+
+		others := make([]int64, 0, len(studySetIDs))
+		for _, id := range studySetIDs {
+			if id != studySetID {
+				others = append(others, id)
+			}
+		}
+
+		for i, id := range placeAt(others, studySetID, moveData.Position) {
+			if err := folderRepo.SetStudySetPosition(ctx, folderID, id, i); err != nil {
+				return fmt.Errorf("%w: failed to update the study set position: %w", ErrRepoFailed, err)
+			}
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return fmt.Errorf("atomic operation failed: %w", err)
+	}
+
+	return nil
+}
+
+func (uc *folderUseCase) RemoveStudySet(ctx context.Context, userID string, folderID int64, studySetID int64) error {
+	err := uc.dataStore.Atomic(ctx, func(ds domain.DataStore) error {
+		// Study sets that are not visible anymore can still be removed, so the permissions are not checked.
+		if _, err := getFolderStudySetIds(ctx, ds, userID, folderID, studySetID); err != nil {
+			return err
+		}
+
+		if err := ds.GetFolderRepo().RemoveStudySet(ctx, folderID, studySetID); err != nil {
+			return fmt.Errorf("%w: failed to remove the study set from the folder: %w", ErrRepoFailed, err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return fmt.Errorf("atomic operation failed: %w", err)
+	}
+
+	return nil
+}
+
+func (uc *folderUseCase) Study(ctx context.Context, userID string, folderID int64, startData *domain.StartFolderStudySessionData) (*domain.FolderStudySession, error) {
+	if err := uc.validate.Struct(startData); err != nil {
+		return nil, fmt.Errorf("%w: invalid study session: %w", ErrValidation, err)
+	}
+
+	var session *domain.FolderStudySession
+
+	err := uc.dataStore.Atomic(ctx, func(ds domain.DataStore) error {
+		if _, err := getOwnFolder(ctx, ds, userID, folderID); err != nil {
+			return err
+		}
+
+		studySets, err := getFolderSubtreeStudySets(ctx, ds, userID, folderID)
+		if err != nil {
+			return err
+		}
+
+		cards := make([]*domain.FolderCard, 0)
+		for _, studySet := range studySets {
+			definitions, err := ds.GetDefinitionRepo().GetAllFor(ctx, studySet.Id)
+			if err != nil {
+				return fmt.Errorf("%w: failed to get all definitions for the study set: %w", ErrRepoFailed, err)
+			}
+			for _, definition := range definitions {
+				cards = append(cards, &domain.FolderCard{
+					StudySetId: studySet.Id,
+					Definition: definition.Populate(),
+				})
+			}
+		}
+
+		if len(cards) == 0 {
+			return fmt.Errorf("%w: folder has no definitions to study", ErrValidation)
+		}
+
+		studySessionID, err := ds.GetStudySessionRepo().StartForFolder(ctx, userID, folderID, startData.Mode, startData.Direction)
+		if err != nil {
+			return fmt.Errorf("%w: failed to start the study session: %w", ErrRepoFailed, err)
+		}
+
+		session = &domain.FolderStudySession{
+			StudySessionId: studySessionID,
+			Cards:          cards,
+		}
+		return nil
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("atomic operation failed: %w", err)
+	}
+
+	return session, nil
+}
+
+// getOwnFolder gets the folder and makes sure that it belongs to the user.
+func getOwnFolder(ctx context.Context, ds domain.DataStore, userID string, folderID int64) (*domain.FolderRow, error) {
+	folder, err := ds.GetFolderRepo().Get(ctx, folderID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to get the folder: %w", ErrRepoFailed, err)
+	}
+	if folder == nil {
+		return nil, &ErrNotFound{
+			Resource: FolderResource,
+		}
+	}
+	if folder.UserId != userID {
+		return nil, ErrForbidden
+	}
+
+	return folder, nil
+}
+
+// getFolderStudySetIds returns ids of the study sets in the user's folder in order
+// and makes sure that the given study set is one of them.
+func getFolderStudySetIds(ctx context.Context, ds domain.DataStore, userID string, folderID int64, studySetID int64) ([]int64, error) {
+	if _, err := getOwnFolder(ctx, ds, userID, folderID); err != nil {
+		return nil, err
+	}
+
+	studySetIDs, err := ds.GetFolderRepo().GetStudySetIds(ctx, folderID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to get folder study sets: %w", ErrRepoFailed, err)
+	}
+
+	for _, id := range studySetIDs {
+		if id == studySetID {
+			return studySetIDs, nil
+		}
+	}
+
+	return nil, &ErrNotFound{
+		Resource: StudySetResource,
+	}
+}
+
+// getFolderStudySets returns the study sets in the folder in order, leaving out those the user cannot see anymore.
+func getFolderStudySets(ctx context.Context, ds domain.DataStore, userID string, folderID int64) ([]*domain.StudySetWithAuthor, error) {
+	studySetIDs, err := ds.GetFolderRepo().GetStudySetIds(ctx, folderID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to get folder study sets: %w", ErrRepoFailed, err)
+	}
+
+	studySets := make([]*domain.StudySetWithAuthor, 0, len(studySetIDs))
+	for _, studySetID := range studySetIDs {
+		studySet, err := newPermissionService(ds).Authorize(ctx, userID, studySetID, actionView)
+		if err != nil {
+			var errNotFound *ErrNotFound
+			if errors.As(err, &errNotFound) {
+				continue
+			}
+			return nil, err
+		}
+		studySets = append(studySets, studySet)
+	}
+
+	return studySets, nil
+}
+
+// getFolderSubtreeStudySets returns the study sets in the folder and all its subfolders in order, leaving out
+// those the user cannot see anymore. A study set may be in more than one of the folders, but it is returned once.
+func getFolderSubtreeStudySets(ctx context.Context, ds domain.DataStore, userID string, folderID int64) ([]*domain.StudySetWithAuthor, error) {
+	tree, err := getFolderTree(ctx, ds, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	studySets := make([]*domain.StudySetWithAuthor, 0)
+	seen := make(map[int64]bool)
+	for _, id := range tree.subtree(folderID) {
+		folderStudySets, err := getFolderStudySets(ctx, ds, userID, id)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, studySet := range folderStudySets {
+			if seen[studySet.Id] {
+				continue
+			}
+			seen[studySet.Id] = true
+			studySets = append(studySets, studySet)
+		}
+	}
+
+	return studySets, nil
+}
+
+// folderTree holds all folders of a user indexed by id, with ids of the children of each folder in order.
+// Children of the top level are stored under id 0.
+type folderTree struct {
+	rows     []*domain.FolderRow
+	folders  map[int64]*domain.FolderRow
+	children map[int64][]int64
+}
+
+func getFolderTree(ctx context.Context, ds domain.DataStore, userID string) (*folderTree, error) {
+	rows, err := ds.GetFolderRepo().GetAll(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to get folders: %w", ErrRepoFailed, err)
+	}
+
+	tree := &folderTree{
+		rows:     rows,
+		folders:  make(map[int64]*domain.FolderRow, len(rows)),
+		children: make(map[int64][]int64),
+	}
+	for _, row := range rows {
+		tree.folders[row.Id] = row
+		tree.children[parentKey(row.ParentId)] = append(tree.children[parentKey(row.ParentId)], row.Id)
+	}
+
+	return tree, nil
+}
+
+// populate returns the folder with all its subfolders.
+func (t *folderTree) populate(folderID int64) *domain.Folder {
+	folder := t.folders[folderID].Populate()
+	for _, childID := range t.children[folderID] {
+		folder.Children = append(folder.Children, t.populate(childID))
+	}
+	return folder
+}
+
+// siblings returns ids of the folders directly under the parent in order.
+func (t *folderTree) siblings(parentID *int64) []int64 {
+	return t.children[parentKey(parentID)]
+}
+
+// subtree returns ids of the folder and all its subfolders, parents before their children.
+func (t *folderTree) subtree(folderID int64) []int64 {
+	ids := []int64{folderID}
+	for _, childID := range t.children[folderID] {
+		ids = append(ids, t.subtree(childID)...)
+	}
+	return ids
+}
+
+// contains tells if the other folder is the folder itself or one of its subfolders.
+func (t *folderTree) contains(folderID int64, otherID int64) bool {
+	for id := otherID; ; {
+		if id == folderID {
+			return true
+		}
+		folder := t.folders[id]
+		if folder == nil || folder.ParentId == nil {
+			return false
+		}
+		id = *folder.ParentId
+	}
+}
+
+// depth returns the level of the folder, where top level folders are at level 1.
+func (t *folderTree) depth(folderID int64) int {
+	depth := 0
+	for folder := t.folders[folderID]; folder != nil; {
+		depth++
+		if folder.ParentId == nil {
+			break
+		}
+		folder = t.folders[*folder.ParentId]
+	}
+	return depth
+}
+
+// height returns the number of levels of the folder and its subfolders.
+func (t *folderTree) height(folderID int64) int {
+	height := 0
+	for _, childID := range t.children[folderID] {
+		height = max(height, t.height(childID))
+	}
+	return height + 1
+}
+
+// checkParent makes sure the parent is a folder of the user, and that it can hold the given number of nested levels.
+func (t *folderTree) checkParent(parentID *int64, levels int) error {
+	depth := 0
+	if parentID != nil {
+		if t.folders[*parentID] == nil {
+			return &ErrNotFound{
+				Resource: FolderResource,
+			}
+		}
+		depth = t.depth(*parentID)
+	}
+
+	if depth+levels > maxFolderDepth {
+		return fmt.Errorf("%w: folders cannot be nested more than %d levels deep", ErrValidation, maxFolderDepth)
+	}
+	return nil
+}
+
+func parentKey(parentID *int64) int64 {
+	if parentID == nil {
+		return 0
+	}
+	return *parentID
+}
+
+func sameFolder(a *int64, b *int64) bool {
+	return parentKey(a) == parentKey(b)
+}
+
+// placeAt inserts the id into the ordered ids at the given position, or at the end if the position is past it.
+func placeAt(ids []int64, id int64, position int) []int64 {
+	position = min(position, len(ids))
+
+	placed := make([]int64, 0, len(ids)+1)
+	placed = append(placed, ids[:position]...)
+	placed = append(placed, id)
+	return append(placed, ids[position:]...)
+}
@@ -0,0 +1,211 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+
+	"ailingo/internal/domain"
+	"ailingo/pkg/importer"
+)
+
+// maxImportRows is the maximum number of non-empty rows imported at once.
+const maxImportRows = 1000
+
+func (uc *definitionUseCase) PreviewImport(ctx context.Context, userID string, parentStudySetID int64, importData *domain.ImportDefinitionsData) (*domain.ImportPreview, error) {
+	preview, err := uc.parseImport(importData)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := newPermissionService(uc.dataStore).Authorize(ctx, userID, parentStudySetID, actionEdit); err != nil {
+		return nil, err
+	}
+
+	return preview, nil
+}
+
+func (uc *definitionUseCase) Import(ctx context.Context, userID string, parentStudySetID int64, importData *domain.ImportDefinitionsData) (*domain.ImportResult, error) {
+	preview, err := uc.parseImport(importData)
+	if err != nil {
+		return nil, err
+	}
+	if preview.InvalidRows > 0 && !importData.SkipInvalid {
+		return nil, fmt.Errorf("%w: %d of %d rows are invalid", ErrValidation, preview.InvalidRows, len(preview.Rows))
+	}
+	if preview.ValidRows == 0 {
+		return nil, fmt.Errorf("%w: there are no rows to import", ErrValidation)
+	}
+
+	result := &domain.ImportResult{
+		ImportPreview: *preview,
+		CreatedIds:    make([]int64, 0, preview.ValidRows),
+	}
+
+	err = uc.dataStore.Atomic(ctx, func(ds domain.DataStore) error {
+		definitionRepo := ds.GetDefinitionRepo()
+
+		if _, err := newPermissionService(ds).Authorize(ctx, userID, parentStudySetID, actionEdit); err != nil {
+			return err
+		}
+
+		for _, row := range preview.Rows {
+			if len(row.Errors) > 0 {
+				continue
+			}
+
+			definitionID, err := definitionRepo.Insert(ctx, parentStudySetID, row.Definition)
+			if err != nil {
+				return fmt.Errorf("%w: failed to insert a new definition: %w", ErrRepoFailed, err)
+			}
+			if err := recordRevision(ctx, ds, userID, parentStudySetID, &domain.RevisionChange{
+				Target:          domain.RevisionTargetDefinition,
+				DefinitionId:    &definitionID,
+				DefinitionAfter: definitionSnapshot(row.Definition.Phrase, row.Definition.Meaning, row.Definition.Sentences),
+			}); err != nil {
+				return err
+			}
+			result.CreatedIds = append(result.CreatedIds, definitionID)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("atomic operation failed: %w", err)
+	}
+
+	return result, nil
+}
+
+// parseImport splits the text into definitions and validates each of them against the limits of inserted definitions.
+func (uc *definitionUseCase) parseImport(importData *domain.ImportDefinitionsData) (*domain.ImportPreview, error) {
+	if err := uc.validate.Struct(importData); err != nil {
+		return nil, fmt.Errorf("%w: invalid import data: %w", ErrValidation, err)
+	}
+
+	format := importData.Format
+	if (format == "" || format == string(importer.FormatAuto)) && strings.HasPrefix(strings.TrimSpace(importData.Text), "{") {
+		format = domain.ExportFormatJSON
+	}
+	if format == domain.ExportFormatJSON {
+		return uc.parseImportBundle(importData.Text)
+	}
+
+	table, err := importer.Parse(importData.Text, importer.Options{
+		Format:        importer.Format(importData.Format),
+		TermSeparator: importData.TermSeparator,
+		RowSeparator:  importData.RowSeparator,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to parse the text: %w", ErrValidation, err)
+	}
+
+	rows := table.Rows
+	if importData.SkipHeader && len(rows) > 0 {
+		rows = rows[1:]
+	}
+	if len(rows) > maxImportRows {
+		return nil, fmt.Errorf("%w: at most %d rows can be imported at once", ErrValidation, maxImportRows)
+	}
+
+	preview := &domain.ImportPreview{
+		Format:        string(table.Format),
+		TermSeparator: table.TermSeparator,
+		RowSeparator:  table.RowSeparator,
+		Rows:          make([]*domain.ImportRow, 0, len(rows)),
+	}
+	for _, row := range rows {
+		definition := &domain.InsertDefinitionData{
+			Phrase:    row.Terms[0],
+			Sentences: make([]string, 0),
+		}
+		if len(row.Terms) > 1 {
+			definition.Meaning = row.Terms[1]
+		}
+		for _, sentence := range row.Terms[min(len(row.Terms), 2):] {
+			if sentence != "" {
+				definition.Sentences = append(definition.Sentences, sentence)
+			}
+		}
+
+		uc.addImportRow(preview, row.Number, definition)
+	}
+
+	return preview, nil
+}
+
+// parseImportBundle reads definitions of a JSON bundle written by the export.
+func (uc *definitionUseCase) parseImportBundle(text string) (*domain.ImportPreview, error) {
+	var bundle domain.ExportBundle
+	if err := json.Unmarshal([]byte(text), &bundle); err != nil {
+		return nil, fmt.Errorf("%w: failed to parse the bundle: %w", ErrValidation, err)
+	}
+	if bundle.Version < 1 || bundle.Version > domain.ExportBundleVersion {
+		return nil, fmt.Errorf("%w: unsupported bundle version %d", ErrValidation, bundle.Version)
+	}
+	if len(bundle.Definitions) > maxImportRows {
+		return nil, fmt.Errorf("%w: at most %d rows can be imported at once", ErrValidation, maxImportRows)
+	}
+
+	preview := &domain.ImportPreview{
+		Format: domain.ExportFormatJSON,
+		Rows:   make([]*domain.ImportRow, 0, len(bundle.Definitions)),
+	}
+	for i, definition := range bundle.Definitions {
+		if definition == nil {
+			definition = &domain.InsertDefinitionData{}
+		}
+		if definition.Sentences == nil {
+			definition.Sentences = make([]string, 0)
+		}
+		uc.addImportRow(preview, i+1, definition)
+	}
+
+	return preview, nil
+}
+
+// addImportRow validates the definition and adds it to the preview.
+func (uc *definitionUseCase) addImportRow(preview *domain.ImportPreview, number int, definition *domain.InsertDefinitionData) {
+	row := &domain.ImportRow{
+		Number:     number,
+		Definition: definition,
+		Errors:     definitionErrors(uc.validate.Struct(definition)),
+	}
+	if len(row.Errors) > 0 {
+		preview.InvalidRows++
+	} else {
+		preview.ValidRows++
+	}
+	preview.Rows = append(preview.Rows, row)
+}
+
+// definitionErrors turns validation errors of a definition into messages that can be shown next to the row.
+func definitionErrors(err error) []string {
+	messages := make([]string, 0)
+	if err == nil {
+		return messages
+	}
+
+	var validationErrors validator.ValidationErrors
+	if !errors.As(err, &validationErrors) {
+		return append(messages, err.Error())
+	}
+
+	for _, fieldError := range validationErrors {
+		field := strings.ToLower(fieldError.Field()[:1]) + fieldError.Field()[1:]
+		switch fieldError.Tag() {
+		case "required":
+			messages = append(messages, fmt.Sprintf("%s is required", field))
+		case "max":
+			messages = append(messages, fmt.Sprintf("%s must be at most %s characters long", field, fieldError.Param()))
+		default:
+			messages = append(messages, fmt.Sprintf("%s is invalid", field))
+		}
+	}
+	return messages
+}
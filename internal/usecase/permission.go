@@ -0,0 +1,93 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+
+	"ailingo/internal/domain"
+)
+
+// studySetAction is an operation on a study set that requires a permission.
+type studySetAction int
+
+const (
+	// actionView allows seeing the study set and its definitions and studying it.
+	actionView studySetAction = iota
+	// actionEdit allows changing the study set and its definitions.
+	actionEdit
+	// actionManage allows deleting the study set, changing its visibility and managing its collaborators.
+	actionManage
+)
+
+// roleOwner is the role of the study set author. It is never stored, as authorship comes from the study set itself.
+const roleOwner = "OWNER"
+
+// permissionService decides what users can do with study sets based on authorship, collaborator roles and visibility.
+// It works on the data store it is created with, so that it can be used inside atomic operations.
+type permissionService struct {
+	ds domain.DataStore
+}
+
+func newPermissionService(ds domain.DataStore) *permissionService {
+	return &permissionService{
+		ds: ds,
+	}
+}
+
+// Authorize gets the study set and makes sure the user is allowed to perform the action. Empty user id means an anonymous user.
+// Study sets the user cannot see are reported as not found, so that their existence is not revealed.
+func (s *permissionService) Authorize(ctx context.Context, userID string, studySetID int64, action studySetAction) (*domain.StudySetWithAuthor, error) {
+	studySet, err := s.ds.GetStudySetRepo().GetById(ctx, studySetID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to get the study set: %w", ErrRepoFailed, err)
+	}
+	if studySet == nil {
+		return nil, &ErrNotFound{
+			Resource: StudySetResource,
+		}
+	}
+
+	role, err := s.role(ctx, studySet, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if studySet.Visibility == domain.VisibilityPrivate && role == "" {
+		return nil, &ErrNotFound{
+			Resource: StudySetResource,
+		}
+	}
+	if !allows(role, action) {
+		return nil, ErrForbidden
+	}
+
+	return studySet, nil
+}
+
+// role returns the role of the user in the study set or an empty string if the user has no role in it.
+func (s *permissionService) role(ctx context.Context, studySet *domain.StudySetWithAuthor, userID string) (string, error) {
+	if userID == "" {
+		return "", nil
+	}
+	if studySet.Author.Id == userID {
+		return roleOwner, nil
+	}
+
+	role, err := s.ds.GetCollaboratorRepo().GetRole(ctx, studySet.Id, userID)
+	if err != nil {
+		return "", fmt.Errorf("%w: failed to get the collaborator role: %w", ErrRepoFailed, err)
+	}
+	return role, nil
+}
+
+// allows tells if the role permits the action on a study set the user can see.
+func allows(role string, action studySetAction) bool {
+	switch action {
+	case actionView:
+		return true
+	case actionEdit:
+		return role == roleOwner || role == domain.CollaboratorRoleEditor
+	default:
+		return role == roleOwner
+	}
+}
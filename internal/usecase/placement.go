@@ -0,0 +1,265 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/go-playground/validator/v10"
+
+	"ailingo/internal/domain"
+	"ailingo/pkg/placement"
+)
+
+var (
+	ErrPlacementFinished   = errors.New("placement test has already been finished")
+	ErrPlacementNotTaken   = errors.New("placement test has not been taken yet")
+	ErrPlacementNotCurrent = errors.New("question is not the current question of the placement test")
+)
+
+type placementUseCase struct {
+	dataStore domain.DataStore
+	validate  *validator.Validate
+}
+
+func NewPlacementUseCase(dataStore domain.DataStore, validate *validator.Validate) domain.PlacementUseCase {
+	return &placementUseCase{
+		dataStore: dataStore,
+		validate:  validate,
+	}
+}
+
+func (uc *placementUseCase) Start(ctx context.Context, userID string) (*domain.PlacementStep, error) {
+	var step *domain.PlacementStep
+
+	err := uc.dataStore.Atomic(ctx, func(ds domain.DataStore) error {
+		placementRepo := ds.GetPlacementRepo()
+
+		testID, err := placementRepo.Insert(ctx, userID)
+		if err != nil {
+			return fmt.Errorf("%w: failed to insert the placement test: %w", ErrRepoFailed, err)
+		}
+
+		// Nothing is known about the learner yet, so the first question is asked at the middle of the scale.
+		question, err := askPlacementQuestion(ctx, placementRepo, testID, nil, 0)
+		if err != nil {
+			return err
+		}
+
+		step = &domain.PlacementStep{
+			TestId:   testID,
+			Question: question,
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("atomic operation failed: %w", err)
+	}
+
+	return step, nil
+}
+
+func (uc *placementUseCase) Get(ctx context.Context, userID string, testID int64) (*domain.PlacementStep, error) {
+	placementRepo := uc.dataStore.GetPlacementRepo()
+
+	testRow, err := getOwnPlacementTest(ctx, placementRepo, userID, testID)
+	if err != nil {
+		return nil, err
+	}
+
+	questionRows, err := placementRepo.GetQuestions(ctx, testID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to get placement questions: %w", ErrRepoFailed, err)
+	}
+
+	return placementStep(testRow, questionRows), nil
+}
+
+func (uc *placementUseCase) Answer(ctx context.Context, userID string, testID int64, answerData *domain.PlacementAnswerData) (*domain.PlacementStep, error) {
+	if err := uc.validate.Struct(answerData); err != nil {
+		return nil, fmt.Errorf("%w: invalid answer data: %w", ErrValidation, err)
+	}
+
+	var step *domain.PlacementStep
+
+	err := uc.dataStore.Atomic(ctx, func(ds domain.DataStore) error {
+		placementRepo := ds.GetPlacementRepo()
+
+		testRow, err := getOwnPlacementTest(ctx, placementRepo, userID, testID)
+		if err != nil {
+			return err
+		}
+		if testRow.FinishedAt != nil {
+			return ErrPlacementFinished
+		}
+
+		questionRows, err := placementRepo.GetQuestions(ctx, testID)
+		if err != nil {
+			return fmt.Errorf("%w: failed to get placement questions: %w", ErrRepoFailed, err)
+		}
+
+		// Only the last question can be answered, previous ones have already been used to pick it.
+		current := questionRows[len(questionRows)-1]
+		if current.Id != answerData.QuestionId || current.AnswerIndex != nil {
+			return ErrPlacementNotCurrent
+		}
+		if answerData.ChoiceIndex >= len(current.Choices) {
+			return fmt.Errorf("%w: choice index out of range", ErrValidation)
+		}
+
+		if err := placementRepo.SaveAnswer(ctx, current.Id, answerData.ChoiceIndex); err != nil {
+			return fmt.Errorf("%w: failed to save the answer: %w", ErrRepoFailed, err)
+		}
+		current.AnswerIndex = &answerData.ChoiceIndex
+
+		asked := make(map[int]bool, len(questionRows))
+		responses := make([]placement.Response, 0, len(questionRows))
+		for _, question := range questionRows {
+			asked[question.ItemId] = true
+			responses = append(responses, placement.Response{
+				Level:   question.Level,
+				Correct: *question.AnswerIndex == question.CorrectIndex,
+			})
+		}
+
+		ability, stdErr := placement.Estimate(responses)
+
+		if !placement.Done(len(responses), stdErr) {
+			question, err := askPlacementQuestion(ctx, placementRepo, testID, asked, ability)
+			if err != nil {
+				return err
+			}
+			if question != nil {
+				step = &domain.PlacementStep{
+					TestId:   testID,
+					Answered: len(responses),
+					Question: question,
+				}
+				return nil
+			}
+			// The word bank has run out, so the current estimate is as good as it gets.
+		}
+
+		result := &domain.PlacementResult{
+			Level:   placement.LevelFor(ability),
+			Ability: ability,
+			StdErr:  stdErr,
+		}
+		if err := placementRepo.Finish(ctx, testID, result); err != nil {
+			return fmt.Errorf("%w: failed to finish the placement test: %w", ErrRepoFailed, err)
+		}
+
+		preferences, err := getPreferences(ctx, ds, userID)
+		if err != nil {
+			return err
+		}
+		preferences.CefrLevel = &result.Level
+		if err := ds.GetPreferencesRepo().Upsert(ctx, userID, preferences); err != nil {
+			return fmt.Errorf("%w: failed to save preferences: %w", ErrRepoFailed, err)
+		}
+
+		testRow, err = placementRepo.Get(ctx, testID)
+		if err != nil {
+			return fmt.Errorf("%w: failed to get the placement test: %w", ErrRepoFailed, err)
+		}
+
+		step = placementStep(testRow, questionRows)
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("atomic operation failed: %w", err)
+	}
+
+	return step, nil
+}
+
+func (uc *placementUseCase) GetRecommended(ctx context.Context, userID string) ([]*domain.StudySetWithAuthor, error) {
+	preferences, err := getPreferences(ctx, uc.dataStore, userID)
+	if err != nil {
+		return nil, err
+	}
+	if preferences.CefrLevel == nil {
+		return nil, ErrPlacementNotTaken
+	}
+
+	studySets, err := uc.dataStore.GetStudySetRepo().GetByCefrLevel(ctx, placement.Language, *preferences.CefrLevel)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to get study sets: %w", ErrRepoFailed, err)
+	}
+
+	return studySets, nil
+}
+
+// getOwnPlacementTest gets the placement test and makes sure it belongs to the given user.
+func getOwnPlacementTest(ctx context.Context, placementRepo domain.PlacementRepo, userID string, testID int64) (*domain.PlacementTestRow, error) {
+	testRow, err := placementRepo.Get(ctx, testID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to get the placement test: %w", ErrRepoFailed, err)
+	}
+	if testRow == nil {
+		return nil, &ErrNotFound{
+			Resource: PlacementTestResource,
+		}
+	}
+	if testRow.UserId != userID {
+		return nil, ErrForbidden
+	}
+	return testRow, nil
+}
+
+// askPlacementQuestion adds a question about a word not asked yet at the difficulty closest to the ability.
+// Nil is returned when there are no words left.
+func askPlacementQuestion(ctx context.Context, placementRepo domain.PlacementRepo, testID int64, asked map[int]bool, ability float64) (*domain.PlacementQuestion, error) {
+	item, ok := placement.Next(asked, ability)
+	if !ok {
+		return nil, nil
+	}
+
+	choices, correctIndex := placement.Choices(item)
+	questionRow := &domain.PlacementQuestionRow{
+		ItemId:       item.Id,
+		Word:         item.Word,
+		Level:        item.Level,
+		Choices:      choices,
+		CorrectIndex: correctIndex,
+	}
+
+	questionID, err := placementRepo.InsertQuestion(ctx, testID, len(asked), questionRow)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to insert the placement question: %w", ErrRepoFailed, err)
+	}
+	questionRow.Id = questionID
+
+	return questionRow.Populate(), nil
+}
+
+// placementStep describes the current state of the placement test.
+func placementStep(testRow *domain.PlacementTestRow, questionRows []*domain.PlacementQuestionRow) *domain.PlacementStep {
+	step := &domain.PlacementStep{
+		TestId: testRow.Id,
+	}
+
+	for _, question := range questionRows {
+		if question.AnswerIndex != nil {
+			step.Answered++
+		} else {
+			step.Question = question.Populate()
+		}
+	}
+
+	if testRow.FinishedAt != nil {
+		step.Question = nil
+		step.Result = &domain.PlacementResult{
+			Level:      *testRow.Level,
+			Ability:    *testRow.Ability,
+			StdErr:     *testRow.StdErr,
+			FinishedAt: testRow.FinishedAt,
+		}
+	}
+
+	return step
+}
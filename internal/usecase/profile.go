@@ -5,6 +5,8 @@ import (
 	"errors"
 	"fmt"
 
+	"github.com/go-playground/validator/v10"
+
 	"ailingo/internal/domain"
 	"ailingo/internal/mysql"
 	"ailingo/pkg/auth"
@@ -17,50 +19,43 @@ var (
 type ProfileUseCase struct {
 	dataStore   domain.DataStore
 	userService *auth.UserService
+	validate    *validator.Validate
 }
 
-func NewProfileUseCase(dataStore domain.DataStore, userService *auth.UserService) *ProfileUseCase {
+func NewProfileUseCase(dataStore domain.DataStore, userService *auth.UserService, validate *validator.Validate) *ProfileUseCase {
 	return &ProfileUseCase{
 		dataStore:   dataStore,
 		userService: userService,
+		validate:    validate,
 	}
 }
 
-func (uc *ProfileUseCase) GetStarredStudySets(ctx context.Context, userID string) ([]*domain.StudySetWithAuthor, error) {
-	studySetRepo := uc.dataStore.GetStudySetRepo()
-
-	studySets, err := studySetRepo.GetStarredBy(ctx, userID)
-	if err != nil {
-		return nil, fmt.Errorf("%w: failed to get all starred study sets: %w", ErrRepoFailed, err)
-	}
-
-	return studySets, nil
+func (uc *ProfileUseCase) GetStarredStudySets(ctx context.Context, userID string, filter *domain.StudySetFilter, cursor string, limit int) (*domain.StudySetPage, error) {
+	// Study sets starred through a link stay on the list, unless they have been made private.
+	filter.StarredBy = userID
+	filter.ViewerId = userID
+	filter.IncludeUnlisted = true
+	return getStudySetPage(ctx, uc.dataStore, uc.validate, filter, cursor, limit)
 }
 
-func (uc *ProfileUseCase) GetCreatedStudySets(ctx context.Context, userID string) ([]*domain.StudySet, error) {
-	studySetRepo := uc.dataStore.GetStudySetRepo()
-
-	studySets, err := studySetRepo.GetCreatedBy(ctx, userID)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get created study sets: %w", err)
-	}
+func (uc *ProfileUseCase) GetCreatedStudySets(ctx context.Context, userID string, filter *domain.StudySetFilter, cursor string, limit int) (*domain.StudySetPage, error) {
+	filter.AuthorId = userID
+	filter.ViewerId = userID
+	return getStudySetPage(ctx, uc.dataStore, uc.validate, filter, cursor, limit)
+}
 
-	return studySets, nil
+func (uc *ProfileUseCase) GetSharedStudySets(ctx context.Context, userID string, filter *domain.StudySetFilter, cursor string, limit int) (*domain.StudySetPage, error) {
+	filter.SharedWith = userID
+	filter.ViewerId = userID
+	return getStudySetPage(ctx, uc.dataStore, uc.validate, filter, cursor, limit)
 }
 
 func (uc *ProfileUseCase) StarStudySet(ctx context.Context, userID string, studySetID int64) error {
 	err := uc.dataStore.Atomic(ctx, func(ds domain.DataStore) error {
-		studySetRepo := uc.dataStore.GetStudySetRepo()
-		profileRepo := uc.dataStore.GetProfileRepo()
+		profileRepo := ds.GetProfileRepo()
 
-		parentExists, err := studySetRepo.Exists(ctx, studySetID)
-		if err != nil {
-			return fmt.Errorf("%w: failed to check if parent study set exists: %w", ErrRepoFailed, err)
-		}
-		if !parentExists {
-			return &ErrNotFound{
-				Resource: StudySetResource,
-			}
+		if _, err := newPermissionService(ds).Authorize(ctx, userID, studySetID, actionView); err != nil {
+			return err
 		}
 
 		if err := profileRepo.InsertStar(ctx, userID, studySetID); err != nil {
@@ -81,9 +76,17 @@ func (uc *ProfileUseCase) StarStudySet(ctx context.Context, userID string, study
 }
 
 func (uc *ProfileUseCase) InstarStudySet(ctx context.Context, userID string, studySetID int64) error {
-	profileRepo := uc.dataStore.GetProfileRepo()
-	if err := profileRepo.DeleteStar(ctx, userID, studySetID); err != nil {
-		return fmt.Errorf("failed to delete the start: %w", err)
+	err := uc.dataStore.Atomic(ctx, func(ds domain.DataStore) error {
+		profileRepo := ds.GetProfileRepo()
+		if err := profileRepo.DeleteStar(ctx, userID, studySetID); err != nil {
+			return fmt.Errorf("failed to delete the start: %w", err)
+		}
+		return nil
+	})
+
+	if err != nil {
+		return fmt.Errorf("atomic operation failed: %w", err)
 	}
+
 	return nil
 }
@@ -0,0 +1,412 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+
+	"ailingo/internal/domain"
+	"ailingo/pkg/srs"
+)
+
+const (
+	defaultQuizQuestionCount = 10
+	quizChoiceCount          = 4
+
+	// Grades used to feed quiz answers into the review history.
+	quizCorrectGrade    = srs.Grade(4)
+	quizIncorrectGrade  = srs.Grade(1)
+	quizUnansweredGrade = srs.Grade(0)
+)
+
+var (
+	ErrQuizAlreadySubmitted = errors.New("quiz has already been submitted")
+	ErrNotEnoughDefinitions = errors.New("study set does not have enough definitions")
+)
+
+type quizUseCase struct {
+	dataStore domain.DataStore
+	validate  *validator.Validate
+}
+
+func NewQuizUseCase(dataStore domain.DataStore, validate *validator.Validate) domain.QuizUseCase {
+	return &quizUseCase{
+		dataStore: dataStore,
+		validate:  validate,
+	}
+}
+
+func (uc *quizUseCase) Create(ctx context.Context, userID string, studySetID int64, createData *domain.CreateQuizData) (*domain.Quiz, error) {
+	if err := uc.validate.Struct(createData); err != nil {
+		return nil, fmt.Errorf("%w: invalid create data: %w", ErrValidation, err)
+	}
+
+	questionCount := createData.QuestionCount
+	if questionCount == 0 {
+		questionCount = defaultQuizQuestionCount
+	}
+
+	var quizID int64
+
+	err := uc.dataStore.Atomic(ctx, func(ds domain.DataStore) error {
+		definitionRepo := ds.GetDefinitionRepo()
+		quizRepo := ds.GetQuizRepo()
+
+		studySet, err := newPermissionService(ds).Authorize(ctx, userID, studySetID, actionView)
+		if err != nil {
+			return err
+		}
+
+		definitionRows, err := definitionRepo.GetAllFor(ctx, studySetID)
+		if err != nil {
+			return fmt.Errorf("%w: failed to get definitions for the study set: %w", ErrRepoFailed, err)
+		}
+		if len(definitionRows) == 0 {
+			return ErrNotEnoughDefinitions
+		}
+
+		// Small study sets do not provide enough distractors, so we borrow them from study sets in the same languages.
+		var pool []*domain.DefinitionRow
+		if len(uniqueQuizAnswers(definitionRows, createData.Direction)) < quizChoiceCount {
+			pool, err = definitionRepo.GetSample(ctx, studySet.PhraseLanguage, studySet.DefinitionLanguage, studySetID, quizChoiceCount*questionCount)
+			if err != nil {
+				return fmt.Errorf("%w: failed to get definitions for distractors: %w", ErrRepoFailed, err)
+			}
+		}
+
+		// Questions are asked about random definitions, preferring the ones at the right difficulty for the user.
+		ability, err := getAbility(ctx, ds, userID, studySet.PhraseLanguage)
+		if err != nil {
+			return err
+		}
+		difficulties, err := getDifficulties(ctx, ds, studySetID)
+		if err != nil {
+			return err
+		}
+		rand.Shuffle(len(definitionRows), func(i, j int) {
+			definitionRows[i], definitionRows[j] = definitionRows[j], definitionRows[i]
+		})
+		sortByDifficultyMatch(definitionRows, ability, difficulties)
+
+		questions := buildQuizQuestions(definitionRows, pool, createData.Direction, questionCount)
+		if len(questions) == 0 {
+			return ErrNotEnoughDefinitions
+		}
+
+		quizID, err = quizRepo.Insert(ctx, userID, studySetID, createData.Direction)
+		if err != nil {
+			return fmt.Errorf("%w: failed to insert the quiz: %w", ErrRepoFailed, err)
+		}
+
+		for i, question := range questions {
+			if _, err := quizRepo.InsertQuestion(ctx, quizID, i, question); err != nil {
+				return fmt.Errorf("%w: failed to insert a quiz question: %w", ErrRepoFailed, err)
+			}
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("atomic operation failed: %w", err)
+	}
+
+	return uc.Get(ctx, userID, quizID)
+}
+
+func (uc *quizUseCase) Get(ctx context.Context, userID string, quizID int64) (*domain.Quiz, error) {
+	var quiz *domain.Quiz
+
+	err := uc.dataStore.Atomic(ctx, func(ds domain.DataStore) error {
+		quizRepo := ds.GetQuizRepo()
+
+		quizRow, err := getOwnQuiz(ctx, quizRepo, userID, quizID)
+		if err != nil {
+			return err
+		}
+
+		questionRows, err := quizRepo.GetQuestions(ctx, quizID)
+		if err != nil {
+			return fmt.Errorf("%w: failed to get quiz questions: %w", ErrRepoFailed, err)
+		}
+
+		quiz = &domain.Quiz{
+			Id:          quizRow.Id,
+			StudySetId:  quizRow.StudySetId,
+			Direction:   quizRow.Direction,
+			Questions:   make([]*domain.QuizQuestion, 0, len(questionRows)),
+			CreatedAt:   quizRow.CreatedAt,
+			SubmittedAt: quizRow.SubmittedAt,
+		}
+		for _, questionRow := range questionRows {
+			quiz.Questions = append(quiz.Questions, questionRow.Populate())
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("atomic operation failed: %w", err)
+	}
+
+	return quiz, nil
+}
+
+func (uc *quizUseCase) Submit(ctx context.Context, userID string, quizID int64, submitData *domain.SubmitQuizData) (*domain.QuizResult, error) {
+	if err := uc.validate.Struct(submitData); err != nil {
+		return nil, fmt.Errorf("%w: invalid submit data: %w", ErrValidation, err)
+	}
+
+	var result *domain.QuizResult
+
+	err := uc.dataStore.Atomic(ctx, func(ds domain.DataStore) error {
+		quizRepo := ds.GetQuizRepo()
+
+		quizRow, err := getOwnQuiz(ctx, quizRepo, userID, quizID)
+		if err != nil {
+			return err
+		}
+		if quizRow.SubmittedAt != nil {
+			return ErrQuizAlreadySubmitted
+		}
+
+		questionRows, err := quizRepo.GetQuestions(ctx, quizID)
+		if err != nil {
+			return fmt.Errorf("%w: failed to get quiz questions: %w", ErrRepoFailed, err)
+		}
+
+		questionsByID := make(map[int64]*domain.QuizQuestionRow, len(questionRows))
+		for _, questionRow := range questionRows {
+			questionsByID[questionRow.Id] = questionRow
+		}
+
+		for _, answer := range submitData.Answers {
+			question, ok := questionsByID[answer.QuestionId]
+			if !ok {
+				return fmt.Errorf("%w: question %d does not belong to the quiz", ErrValidation, answer.QuestionId)
+			}
+			if answer.ChoiceIndex >= len(question.Choices) {
+				return fmt.Errorf("%w: choice index out of range for question %d", ErrValidation, answer.QuestionId)
+			}
+
+			choiceIndex := answer.ChoiceIndex
+			question.AnswerIndex = &choiceIndex
+
+			if err := quizRepo.SaveAnswer(ctx, question.Id, choiceIndex); err != nil {
+				return fmt.Errorf("%w: failed to save the answer: %w", ErrRepoFailed, err)
+			}
+		}
+
+		result = scoreQuiz(quizID, questionRows)
+
+		// The quiz may have been submitted by a concurrent request since it was read.
+		submitted, err := quizRepo.Submit(ctx, quizID, result.Score)
+		if err != nil {
+			return fmt.Errorf("%w: failed to submit the quiz: %w", ErrRepoFailed, err)
+		}
+		if !submitted {
+			return ErrQuizAlreadySubmitted
+		}
+
+		reviews, err := quizReviews(ctx, ds, quizRow.StudySetId, questionRows)
+		if err != nil {
+			return err
+		}
+
+		return applyReviews(ctx, ds, userID, quizRow.StudySetId, reviews)
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("atomic operation failed: %w", err)
+	}
+
+	return result, nil
+}
+
+// getOwnQuiz gets the quiz and makes sure it belongs to the given user.
+func getOwnQuiz(ctx context.Context, quizRepo domain.QuizRepo, userID string, quizID int64) (*domain.QuizRow, error) {
+	quizRow, err := quizRepo.Get(ctx, quizID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to get the quiz: %w", ErrRepoFailed, err)
+	}
+	if quizRow == nil {
+		return nil, &ErrNotFound{
+			Resource: QuizResource,
+		}
+	}
+	if quizRow.UserId != userID {
+		return nil, ErrForbidden
+	}
+	return quizRow, nil
+}
+
+// scoreQuiz compares answers with the correct choices.
+func scoreQuiz(quizID int64, questionRows []*domain.QuizQuestionRow) *domain.QuizResult {
+	result := &domain.QuizResult{
+		QuizId:    quizID,
+		Total:     len(questionRows),
+		Questions: make([]*domain.QuizQuestionResult, 0, len(questionRows)),
+	}
+
+	for _, question := range questionRows {
+		correct := question.AnswerIndex != nil && *question.AnswerIndex == question.CorrectIndex
+		if correct {
+			result.Score++
+		}
+
+		result.Questions = append(result.Questions, &domain.QuizQuestionResult{
+			QuestionId:   question.Id,
+			ChoiceIndex:  question.AnswerIndex,
+			CorrectIndex: question.CorrectIndex,
+			Correct:      correct,
+		})
+	}
+
+	return result
+}
+
+// quizReviews converts answered questions into reviews.
+// Questions about definitions removed from the study set after the quiz was created are skipped.
+func quizReviews(ctx context.Context, ds domain.DataStore, studySetID int64, questionRows []*domain.QuizQuestionRow) ([]*domain.ReviewGradeData, error) {
+	definitionRows, err := ds.GetDefinitionRepo().GetAllFor(ctx, studySetID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to get definitions for the study set: %w", ErrRepoFailed, err)
+	}
+
+	definitionIDs := make(map[int64]bool, len(definitionRows))
+	for _, definitionRow := range definitionRows {
+		definitionIDs[definitionRow.Id] = true
+	}
+
+	now := time.Now()
+	reviews := make([]*domain.ReviewGradeData, 0, len(questionRows))
+	for _, question := range questionRows {
+		if !definitionIDs[question.DefinitionId] {
+			continue
+		}
+
+		grade := quizUnansweredGrade
+		if question.AnswerIndex != nil {
+			if *question.AnswerIndex == question.CorrectIndex {
+				grade = quizCorrectGrade
+			} else {
+				grade = quizIncorrectGrade
+			}
+		}
+
+		reviews = append(reviews, &domain.ReviewGradeData{
+			DefinitionId: question.DefinitionId,
+			Grade:        int(grade),
+			AnsweredAt:   &now,
+		})
+	}
+
+	return reviews, nil
+}
+
+// quizPrompt returns the part of the definition shown to the learner.
+func quizPrompt(definition *domain.DefinitionRow, direction string) string {
+	if direction == domain.QuizDirectionMeaningToPhrase {
+		return definition.Meaning
+	}
+	return definition.Phrase
+}
+
+// quizAnswer returns the part of the definition the learner has to pick.
+func quizAnswer(definition *domain.DefinitionRow, direction string) string {
+	if direction == domain.QuizDirectionMeaningToPhrase {
+		return definition.Phrase
+	}
+	return definition.Meaning
+}
+
+// uniqueQuizAnswers returns answers of the given definitions without case-insensitive duplicates.
+func uniqueQuizAnswers(definitions []*domain.DefinitionRow, direction string) []string {
+	seen := make(map[string]bool, len(definitions))
+	answers := make([]string, 0, len(definitions))
+
+	for _, definition := range definitions {
+		answer := quizAnswer(definition, direction)
+		key := strings.ToLower(strings.TrimSpace(answer))
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		answers = append(answers, answer)
+	}
+
+	return answers
+}
+
+// buildQuizQuestions generates up to count questions about the definitions, in the given order.
+// Distractors are picked from other definitions of the study set first and then from the pool.
+// Definitions without any possible distractor are skipped.
+func buildQuizQuestions(definitions []*domain.DefinitionRow, pool []*domain.DefinitionRow, direction string, count int) []*domain.QuizQuestionRow {
+	setAnswers := uniqueQuizAnswers(definitions, direction)
+	poolAnswers := uniqueQuizAnswers(pool, direction)
+
+	questions := make([]*domain.QuizQuestionRow, 0, count)
+	for _, definition := range definitions {
+		if len(questions) == count {
+			break
+		}
+
+		correct := quizAnswer(definition, direction)
+		distractors := pickDistractors(correct, setAnswers, poolAnswers, quizChoiceCount-1)
+		if len(distractors) == 0 {
+			continue
+		}
+
+		choices := append(distractors, correct)
+		rand.Shuffle(len(choices), func(i, j int) {
+			choices[i], choices[j] = choices[j], choices[i]
+		})
+
+		correctIndex := 0
+		for i, choice := range choices {
+			if choice == correct {
+				correctIndex = i
+				break
+			}
+		}
+
+		questions = append(questions, &domain.QuizQuestionRow{
+			DefinitionId: definition.Id,
+			Prompt:       quizPrompt(definition, direction),
+			Choices:      choices,
+			CorrectIndex: correctIndex,
+		})
+	}
+
+	return questions
+}
+
+// pickDistractors picks up to n random answers different from the correct one, preferring primary candidates.
+func pickDistractors(correct string, primary []string, secondary []string, n int) []string {
+	seen := map[string]bool{
+		strings.ToLower(strings.TrimSpace(correct)): true,
+	}
+	distractors := make([]string, 0, n)
+
+	for _, candidates := range [][]string{primary, secondary} {
+		for _, i := range rand.Perm(len(candidates)) {
+			if len(distractors) == n {
+				return distractors
+			}
+
+			key := strings.ToLower(strings.TrimSpace(candidates[i]))
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			distractors = append(distractors, candidates[i])
+		}
+	}
+
+	return distractors
+}
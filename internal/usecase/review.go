@@ -0,0 +1,492 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+
+	"ailingo/internal/domain"
+	"ailingo/pkg/srs"
+)
+
+const (
+	defaultReviewLimit = 20
+	maxReviewLimit     = 100
+
+	defaultDueQueueNewLimit = 10
+	// newCandidateFactor tells how many more new definitions are considered than included in the queue.
+	newCandidateFactor = 3
+
+	// matureIntervalDays is the review interval from which a definition is considered mature.
+	matureIntervalDays = 21
+	// progressHistoryDays is the number of days covered by accuracy history.
+	progressHistoryDays = 30
+	// defaultResponseTime is used to estimate study time when the user has no review history.
+	defaultResponseTime = 10 * time.Second
+
+	defaultWeakDefinitionLimit = 50
+	maxWeakDefinitionLimit     = 200
+)
+
+// weakDefinitionCriteria decides which definitions end up in the weak words set.
+// Definitions leave the set once they mature.
+var weakDefinitionCriteria = domain.WeakDefinitionCriteria{
+	MaxIntervalDays: matureIntervalDays,
+	MinLapses:       2,
+	MaxAccuracy:     0.6,
+	MinAnswers:      3,
+}
+
+type reviewUseCase struct {
+	dataStore domain.DataStore
+	validate  *validator.Validate
+}
+
+func NewReviewUseCase(dataStore domain.DataStore, validate *validator.Validate) domain.ReviewUseCase {
+	return &reviewUseCase{
+		dataStore: dataStore,
+		validate:  validate,
+	}
+}
+
+func (uc *reviewUseCase) GetNext(ctx context.Context, userID string, studySetID int64, limit int) ([]*domain.ReviewCard, error) {
+	if limit <= 0 {
+		limit = defaultReviewLimit
+	}
+	if limit > maxReviewLimit {
+		return nil, fmt.Errorf("%w: limit cannot be greater than %d", ErrValidation, maxReviewLimit)
+	}
+
+	var cards []*domain.ReviewCard
+
+	err := uc.dataStore.Atomic(ctx, func(ds domain.DataStore) error {
+		studySet, err := newPermissionService(ds).Authorize(ctx, userID, studySetID, actionView)
+		if err != nil {
+			return err
+		}
+
+		definitionRows, err := ds.GetDefinitionRepo().GetAllFor(ctx, studySetID)
+		if err != nil {
+			return fmt.Errorf("%w: failed to get definitions for the study set: %w", ErrRepoFailed, err)
+		}
+
+		// New definitions are introduced starting from the ones at the right difficulty for the user.
+		ability, err := getAbility(ctx, ds, userID, studySet.PhraseLanguage)
+		if err != nil {
+			return err
+		}
+		difficulties, err := getDifficulties(ctx, ds, studySetID)
+		if err != nil {
+			return err
+		}
+		sortByDifficultyMatch(definitionRows, ability, difficulties)
+
+		states, err := ds.GetReviewRepo().GetStatesFor(ctx, userID, studySetID)
+		if err != nil {
+			return fmt.Errorf("%w: failed to get review states: %w", ErrRepoFailed, err)
+		}
+
+		cards = nextReviewCards(definitionRows, states, time.Now(), limit)
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("atomic operation failed: %w", err)
+	}
+
+	return cards, nil
+}
+
+func (uc *reviewUseCase) GetDueQueue(ctx context.Context, userID string, params *domain.DueQueueParams) ([]*domain.QueueCard, error) {
+	limit := params.Limit
+	if limit <= 0 {
+		limit = defaultReviewLimit
+	}
+	if limit > maxReviewLimit {
+		return nil, fmt.Errorf("%w: limit cannot be greater than %d", ErrValidation, maxReviewLimit)
+	}
+
+	newLimit := min(defaultDueQueueNewLimit, limit)
+	if params.NewLimit != nil {
+		newLimit = *params.NewLimit
+	}
+	if newLimit < 0 {
+		return nil, fmt.Errorf("%w: new cards limit cannot be negative", ErrValidation)
+	}
+	reviewLimit := params.ReviewLimit
+	if reviewLimit <= 0 {
+		reviewLimit = limit
+	}
+	if newLimit > limit || reviewLimit > limit {
+		return nil, fmt.Errorf("%w: quotas cannot be greater than the limit", ErrValidation)
+	}
+
+	var cards []*domain.QueueCard
+
+	err := uc.dataStore.Atomic(ctx, func(ds domain.DataStore) error {
+		reviewRepo := ds.GetReviewRepo()
+
+		due, err := reviewRepo.GetDue(ctx, userID, time.Now(), reviewLimit)
+		if err != nil {
+			return fmt.Errorf("%w: failed to get due definitions: %w", ErrRepoFailed, err)
+		}
+
+		fresh := make([]*domain.QueueCard, 0)
+		if newLimit > 0 {
+			// More new definitions than needed are fetched, so that the ones at the right difficulty can be picked.
+			if fresh, err = reviewRepo.GetNew(ctx, userID, newLimit*newCandidateFactor); err != nil {
+				return fmt.Errorf("%w: failed to get new definitions: %w", ErrRepoFailed, err)
+			}
+			if err := sortQueueByDifficultyMatch(ctx, ds, userID, fresh); err != nil {
+				return err
+			}
+			if len(fresh) > newLimit {
+				fresh = fresh[:newLimit]
+			}
+		}
+
+		// The queue is cut before interleaving, so that due definitions are never pushed out by new ones.
+		cards = append(due, fresh...)
+		if len(cards) > limit {
+			cards = cards[:limit]
+		}
+		if params.Interleave {
+			cards = interleaveByStudySet(cards)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("atomic operation failed: %w", err)
+	}
+
+	return cards, nil
+}
+
+func (uc *reviewUseCase) GetWeakDefinitions(ctx context.Context, userID string, limit int) ([]*domain.WeakDefinition, error) {
+	if limit <= 0 {
+		limit = defaultWeakDefinitionLimit
+	}
+	if limit > maxWeakDefinitionLimit {
+		return nil, fmt.Errorf("%w: limit cannot be greater than %d", ErrValidation, maxWeakDefinitionLimit)
+	}
+
+	definitions, err := uc.dataStore.GetReviewRepo().GetWeak(ctx, userID, &weakDefinitionCriteria, limit)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to get weak definitions: %w", ErrRepoFailed, err)
+	}
+
+	return definitions, nil
+}
+
+func (uc *reviewUseCase) GetProgress(ctx context.Context, userID string, studySetID int64) (*domain.StudySetProgress, error) {
+	var progress *domain.StudySetProgress
+
+	err := uc.dataStore.Atomic(ctx, func(ds domain.DataStore) error {
+		reviewRepo := ds.GetReviewRepo()
+
+		if _, err := newPermissionService(ds).Authorize(ctx, userID, studySetID, actionView); err != nil {
+			return err
+		}
+
+		definitionRows, err := ds.GetDefinitionRepo().GetAllFor(ctx, studySetID)
+		if err != nil {
+			return fmt.Errorf("%w: failed to get definitions for the study set: %w", ErrRepoFailed, err)
+		}
+
+		states, err := reviewRepo.GetStatesFor(ctx, userID, studySetID)
+		if err != nil {
+			return fmt.Errorf("%w: failed to get review states: %w", ErrRepoFailed, err)
+		}
+
+		preferences, err := getPreferences(ctx, ds, userID)
+		if err != nil {
+			return err
+		}
+		location := userLocation(preferences)
+
+		since := time.Now().In(location).AddDate(0, 0, -progressHistoryDays+1)
+		since = time.Date(since.Year(), since.Month(), since.Day(), 0, 0, 0, 0, location)
+
+		entries, err := reviewRepo.GetLogFor(ctx, userID, studySetID, since)
+		if err != nil {
+			return fmt.Errorf("%w: failed to get the review log: %w", ErrRepoFailed, err)
+		}
+
+		progress = studySetProgress(definitionRows, states, entries, location)
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("atomic operation failed: %w", err)
+	}
+
+	return progress, nil
+}
+
+func (uc *reviewUseCase) Grade(ctx context.Context, userID string, studySetID int64, batchData *domain.ReviewBatchData) error {
+	if err := uc.validate.Struct(batchData); err != nil {
+		return fmt.Errorf("%w: invalid review batch: %w", ErrValidation, err)
+	}
+
+	now := time.Now()
+	for _, review := range batchData.Reviews {
+		if review.AnsweredAt.After(now) {
+			return fmt.Errorf("%w: answer cannot be given in the future", ErrValidation)
+		}
+	}
+
+	reviews := make([]*domain.ReviewGradeData, len(batchData.Reviews))
+	copy(reviews, batchData.Reviews)
+	sort.SliceStable(reviews, func(i, j int) bool {
+		return reviews[i].AnsweredAt.Before(*reviews[j].AnsweredAt)
+	})
+
+	err := uc.dataStore.Atomic(ctx, func(ds domain.DataStore) error {
+		if _, err := newPermissionService(ds).Authorize(ctx, userID, studySetID, actionView); err != nil {
+			return err
+		}
+
+		return applyReviews(ctx, ds, userID, studySetID, reviews)
+	})
+
+	if err != nil {
+		return fmt.Errorf("atomic operation failed: %w", err)
+	}
+
+	return nil
+}
+
+// applyReviews appends the given reviews to the review log, updates scheduling state of the reviewed definitions,
+// records study activity, updates skill estimates and refreshes the study session. Reviews must belong to the given study set and be sorted by answer time.
+func applyReviews(ctx context.Context, ds domain.DataStore, userID string, studySetID int64, reviews []*domain.ReviewGradeData) error {
+	reviewRepo := ds.GetReviewRepo()
+
+	definitionRows, err := ds.GetDefinitionRepo().GetAllFor(ctx, studySetID)
+	if err != nil {
+		return fmt.Errorf("%w: failed to get definitions for the study set: %w", ErrRepoFailed, err)
+	}
+
+	states, err := reviewRepo.GetStatesFor(ctx, userID, studySetID)
+	if err != nil {
+		return fmt.Errorf("%w: failed to get review states: %w", ErrRepoFailed, err)
+	}
+
+	definitionIDs := make(map[int64]bool, len(definitionRows))
+	for _, definitionRow := range definitionRows {
+		definitionIDs[definitionRow.Id] = true
+	}
+
+	statesByDefinition := make(map[int64]*domain.ReviewState, len(states))
+	for _, state := range states {
+		statesByDefinition[state.DefinitionId] = state
+	}
+
+	for _, review := range reviews {
+		if !definitionIDs[review.DefinitionId] {
+			return &ErrNotFound{
+				Resource: DefinitionResource,
+			}
+		}
+
+		if err := reviewRepo.InsertLog(ctx, userID, studySetID, review); err != nil {
+			return fmt.Errorf("%w: failed to append the review to the log: %w", ErrRepoFailed, err)
+		}
+
+		state := scheduleReview(statesByDefinition[review.DefinitionId], review.DefinitionId, srs.Grade(review.Grade), *review.AnsweredAt)
+		if err := reviewRepo.UpsertState(ctx, userID, studySetID, state); err != nil {
+			return fmt.Errorf("%w: failed to save the review state: %w", ErrRepoFailed, err)
+		}
+		statesByDefinition[review.DefinitionId] = state
+	}
+
+	if err := recordStudyActivity(ctx, ds, userID, reviews); err != nil {
+		return err
+	}
+
+	if err := updateSkill(ctx, ds, userID, studySetID, reviews); err != nil {
+		return err
+	}
+
+	return refreshStudySession(ctx, ds, userID, studySetID)
+}
+
+// scheduleReview computes the review state of the definition after being graded at the given time.
+// Current state is nil if the definition has never been reviewed.
+func scheduleReview(current *domain.ReviewState, definitionID int64, grade srs.Grade, reviewedAt time.Time) *domain.ReviewState {
+	state := srs.NewState()
+	if current != nil {
+		state = srs.State{
+			Ease:         current.Ease,
+			IntervalDays: current.IntervalDays,
+			Repetitions:  current.Repetitions,
+			Lapses:       current.Lapses,
+		}
+	}
+
+	next := srs.Schedule(state, grade, reviewedAt)
+
+	return &domain.ReviewState{
+		DefinitionId:   definitionID,
+		Ease:           next.Ease,
+		IntervalDays:   next.IntervalDays,
+		Repetitions:    next.Repetitions,
+		Lapses:         next.Lapses,
+		DueAt:          &next.DueAt,
+		LastReviewedAt: &reviewedAt,
+	}
+}
+
+// nextReviewCards picks up to limit cards that are due at the given time, the most overdue first,
+// and fills the remaining space with definitions that have never been reviewed.
+func nextReviewCards(definitionRows []*domain.DefinitionRow, states []*domain.ReviewState, now time.Time, limit int) []*domain.ReviewCard {
+	statesByDefinition := make(map[int64]*domain.ReviewState, len(states))
+	for _, state := range states {
+		statesByDefinition[state.DefinitionId] = state
+	}
+
+	due := make([]*domain.ReviewCard, 0)
+	fresh := make([]*domain.ReviewCard, 0)
+
+	for _, definitionRow := range definitionRows {
+		state, ok := statesByDefinition[definitionRow.Id]
+		if !ok {
+			fresh = append(fresh, &domain.ReviewCard{Definition: *definitionRow.Populate()})
+			continue
+		}
+		if state.DueAt == nil || !state.DueAt.After(now) {
+			due = append(due, &domain.ReviewCard{Definition: *definitionRow.Populate(), State: state})
+		}
+	}
+
+	sort.SliceStable(due, func(i, j int) bool {
+		return dueBefore(due[i].State, due[j].State)
+	})
+
+	cards := append(due, fresh...)
+	if len(cards) > limit {
+		cards = cards[:limit]
+	}
+
+	return cards
+}
+
+func dueBefore(a *domain.ReviewState, b *domain.ReviewState) bool {
+	if a.DueAt == nil || b.DueAt == nil {
+		return a.DueAt == nil && b.DueAt != nil
+	}
+	return a.DueAt.Before(*b.DueAt)
+}
+
+// interleaveByStudySet reorders cards so that consecutive cards come from different study sets where possible.
+// Relative order of cards from the same study set is preserved.
+func interleaveByStudySet(cards []*domain.QueueCard) []*domain.QueueCard {
+	order := make([]int64, 0)
+	groups := make(map[int64][]*domain.QueueCard)
+	for _, card := range cards {
+		if _, ok := groups[card.StudySet.Id]; !ok {
+			order = append(order, card.StudySet.Id)
+		}
+		groups[card.StudySet.Id] = append(groups[card.StudySet.Id], card)
+	}
+
+	interleaved := make([]*domain.QueueCard, 0, len(cards))
+	for len(interleaved) < len(cards) {
+		for _, studySetID := range order {
+			if group := groups[studySetID]; len(group) > 0 {
+				interleaved = append(interleaved, group[0])
+				groups[studySetID] = group[1:]
+			}
+		}
+	}
+
+	return interleaved
+}
+
+// studySetProgress classifies definitions by their review states and summarizes the review log.
+func studySetProgress(definitionRows []*domain.DefinitionRow, states []*domain.ReviewState, entries []*domain.ReviewLogEntry, location *time.Location) *domain.StudySetProgress {
+	statesByDefinition := make(map[int64]*domain.ReviewState, len(states))
+	for _, state := range states {
+		statesByDefinition[state.DefinitionId] = state
+	}
+
+	progress := &domain.StudySetProgress{
+		Total:    len(definitionRows),
+		Accuracy: make([]*domain.DailyAccuracy, 0),
+	}
+
+	for _, definitionRow := range definitionRows {
+		state, ok := statesByDefinition[definitionRow.Id]
+		switch {
+		case !ok:
+			progress.New++
+			progress.EstimatedReviewsToFinish += reviewsToMature(srs.NewState())
+		case state.Repetitions == 0 && state.Lapses > 0:
+			progress.Lapsed++
+			progress.EstimatedReviewsToFinish += reviewsToMature(srs.State{Ease: state.Ease})
+		case state.IntervalDays >= matureIntervalDays:
+			progress.Mature++
+		default:
+			progress.Learning++
+			progress.EstimatedReviewsToFinish += reviewsToMature(srs.State{
+				Ease:         state.Ease,
+				IntervalDays: state.IntervalDays,
+				Repetitions:  state.Repetitions,
+			})
+		}
+	}
+
+	if progress.Total > 0 {
+		progress.Mastery = math.Round(float64(progress.Mature)/float64(progress.Total)*1000) / 10
+	}
+
+	var totalResponseTime time.Duration
+	for _, entry := range entries {
+		date := entry.AnsweredAt.In(location).Format(time.DateOnly)
+
+		n := len(progress.Accuracy)
+		if n == 0 || progress.Accuracy[n-1].Date != date {
+			progress.Accuracy = append(progress.Accuracy, &domain.DailyAccuracy{Date: date})
+			n++
+		}
+
+		day := progress.Accuracy[n-1]
+		day.Reviews++
+		if entry.Grade >= srs.PassingGrade {
+			day.Correct++
+		}
+
+		totalResponseTime += time.Duration(entry.ResponseTimeMs) * time.Millisecond
+	}
+
+	for _, day := range progress.Accuracy {
+		day.Accuracy = math.Round(float64(day.Correct)/float64(day.Reviews)*1000) / 10
+	}
+
+	responseTime := defaultResponseTime
+	if len(entries) > 0 && totalResponseTime > 0 {
+		responseTime = totalResponseTime / time.Duration(len(entries))
+	}
+
+	progress.EstimatedSecondsToFinish = int((time.Duration(progress.EstimatedReviewsToFinish) * responseTime).Seconds())
+
+	return progress
+}
+
+// reviewsToMature estimates how many successful reviews are needed for a definition to become mature.
+// The estimate is capped, as definitions with minimal ease grow their intervals slowly.
+func reviewsToMature(state srs.State) int {
+	reviews := 0
+	for state.IntervalDays < matureIntervalDays && reviews < 20 {
+		state = srs.Schedule(state, srs.Grade(4), time.Time{})
+		reviews++
+	}
+	return reviews
+}
@@ -0,0 +1,455 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"ailingo/internal/domain"
+)
+
+const (
+	defaultRevisionPageLimit = 50
+	maxRevisionPageLimit     = 100
+)
+
+type revisionUseCase struct {
+	dataStore domain.DataStore
+}
+
+func NewRevisionUseCase(dataStore domain.DataStore) domain.RevisionUseCase {
+	return &revisionUseCase{
+		dataStore: dataStore,
+	}
+}
+
+func (uc *revisionUseCase) GetPage(ctx context.Context, userID string, studySetID int64, cursor int64, limit int) (*domain.RevisionPage, error) {
+	if limit == 0 {
+		limit = defaultRevisionPageLimit
+	}
+	if limit < 0 || limit > maxRevisionPageLimit {
+		return nil, fmt.Errorf("%w: limit must be between 1 and %d", ErrValidation, maxRevisionPageLimit)
+	}
+	if cursor < 0 {
+		return nil, fmt.Errorf("%w: invalid cursor", ErrValidation)
+	}
+
+	if _, err := newPermissionService(uc.dataStore).Authorize(ctx, userID, studySetID, actionEdit); err != nil {
+		return nil, err
+	}
+
+	// One additional revision is fetched to find out if there is a next page.
+	revisionRows, err := uc.dataStore.GetRevisionRepo().GetPage(ctx, studySetID, cursor, limit+1)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to get revisions: %w", ErrRepoFailed, err)
+	}
+
+	page := &domain.RevisionPage{
+		Revisions: make([]*domain.Revision, 0, len(revisionRows)),
+	}
+	for _, revisionRow := range revisionRows {
+		page.Revisions = append(page.Revisions, newRevision(revisionRow))
+	}
+	if len(page.Revisions) > limit {
+		page.Revisions = page.Revisions[:limit]
+		page.NextCursor = &page.Revisions[limit-1].Id
+	}
+
+	return page, nil
+}
+
+func (uc *revisionUseCase) GetStudySetAt(ctx context.Context, userID string, studySetID int64, revisionID int64) (*domain.StudySetAtRevision, error) {
+	var studySetAt *domain.StudySetAtRevision
+
+	err := uc.dataStore.Atomic(ctx, func(ds domain.DataStore) error {
+		studySet, err := newPermissionService(ds).Authorize(ctx, userID, studySetID, actionEdit)
+		if err != nil {
+			return err
+		}
+
+		revision, err := getRevision(ctx, ds, studySetID, revisionID)
+		if err != nil {
+			return err
+		}
+
+		state, err := studySetStateAt(ctx, ds, studySet, revisionID)
+		if err != nil {
+			return err
+		}
+
+		studySetAt = &domain.StudySetAtRevision{
+			Revision:    newRevision(revision),
+			StudySet:    state.studySet,
+			Definitions: make([]*domain.Definition, 0, len(state.definitions)),
+		}
+		for _, definitionID := range state.definitionIDs() {
+			definition := state.definitions[definitionID]
+			studySetAt.Definitions = append(studySetAt.Definitions, &domain.Definition{
+				Id:        definitionID,
+				Phrase:    definition.Phrase,
+				Meaning:   definition.Meaning,
+				Sentences: definition.Sentences,
+			})
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("atomic operation failed: %w", err)
+	}
+
+	return studySetAt, nil
+}
+
+func (uc *revisionUseCase) Revert(ctx context.Context, userID string, studySetID int64, revisionID int64) error {
+	err := uc.dataStore.Atomic(ctx, func(ds domain.DataStore) error {
+		studySet, err := newPermissionService(ds).Authorize(ctx, userID, studySetID, actionEdit)
+		if err != nil {
+			return err
+		}
+
+		if _, err := getRevision(ctx, ds, studySetID, revisionID); err != nil {
+			return err
+		}
+
+		current, err := currentStudySetState(ctx, ds, studySet)
+		if err != nil {
+			return err
+		}
+		target, err := studySetStateAt(ctx, ds, studySet, revisionID)
+		if err != nil {
+			return err
+		}
+
+		if err := revertStudySet(ctx, ds, userID, studySet, current.studySet, target.studySet); err != nil {
+			return err
+		}
+		for _, definitionID := range current.definitionIDs() {
+			if err := revertDefinition(ctx, ds, userID, studySetID, definitionID, current.definitions[definitionID], target.definitions[definitionID]); err != nil {
+				return err
+			}
+		}
+		for _, definitionID := range target.definitionIDs() {
+			if _, ok := current.definitions[definitionID]; ok {
+				continue
+			}
+			if err := revertDefinition(ctx, ds, userID, studySetID, definitionID, nil, target.definitions[definitionID]); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return fmt.Errorf("atomic operation failed: %w", err)
+	}
+
+	return nil
+}
+
+func (uc *revisionUseCase) RevertDefinition(ctx context.Context, userID string, studySetID int64, revisionID int64, definitionID int64) error {
+	err := uc.dataStore.Atomic(ctx, func(ds domain.DataStore) error {
+		studySet, err := newPermissionService(ds).Authorize(ctx, userID, studySetID, actionEdit)
+		if err != nil {
+			return err
+		}
+
+		if _, err := getRevision(ctx, ds, studySetID, revisionID); err != nil {
+			return err
+		}
+
+		current, err := currentStudySetState(ctx, ds, studySet)
+		if err != nil {
+			return err
+		}
+		target, err := studySetStateAt(ctx, ds, studySet, revisionID)
+		if err != nil {
+			return err
+		}
+
+		currentDefinition, targetDefinition := current.definitions[definitionID], target.definitions[definitionID]
+		if currentDefinition == nil && targetDefinition == nil {
+			return &ErrNotFound{
+				Resource: DefinitionResource,
+			}
+		}
+
+		return revertDefinition(ctx, ds, userID, studySetID, definitionID, currentDefinition, targetDefinition)
+	})
+
+	if err != nil {
+		return fmt.Errorf("atomic operation failed: %w", err)
+	}
+
+	return nil
+}
+
+func getRevision(ctx context.Context, ds domain.DataStore, studySetID int64, revisionID int64) (*domain.RevisionRow, error) {
+	revision, err := ds.GetRevisionRepo().Get(ctx, studySetID, revisionID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to get the revision: %w", ErrRepoFailed, err)
+	}
+	if revision == nil {
+		return nil, &ErrNotFound{
+			Resource: RevisionResource,
+		}
+	}
+	return revision, nil
+}
+
+// revertStudySet brings the study set fields back to the target snapshot, keeping the current visibility.
+func revertStudySet(ctx context.Context, ds domain.DataStore, userID string, studySet *domain.StudySetWithAuthor, current *domain.StudySetSnapshot, target *domain.StudySetSnapshot) error {
+	if reflect.DeepEqual(current, target) {
+		return nil
+	}
+
+	if err := ds.GetStudySetRepo().Update(ctx, studySet.Id, &domain.UpdateStudySetData{
+		Name:               target.Name,
+		Description:        target.Description,
+		PhraseLanguage:     target.PhraseLanguage,
+		DefinitionLanguage: target.DefinitionLanguage,
+		Icon:               target.Icon,
+		Color:              target.Color,
+		CefrLevel:          target.CefrLevel,
+		Visibility:         studySet.Visibility,
+		Category:           target.Category,
+	}); err != nil {
+		return fmt.Errorf("%w: failed to update the study set: %w", ErrRepoFailed, err)
+	}
+
+	if !reflect.DeepEqual(current.Tags, target.Tags) {
+		if err := ds.GetTagRepo().SetForStudySet(ctx, studySet.Id, target.Tags); err != nil {
+			return fmt.Errorf("%w: failed to set tags: %w", ErrRepoFailed, err)
+		}
+	}
+
+	return recordRevision(ctx, ds, userID, studySet.Id, &domain.RevisionChange{
+		Target:         domain.RevisionTargetStudySet,
+		StudySetBefore: current,
+		StudySetAfter:  target,
+	})
+}
+
+// revertDefinition brings the definition back to the target snapshot. Nil target means the definition did not exist.
+// Definitions deleted since are inserted again under a new id.
+func revertDefinition(ctx context.Context, ds domain.DataStore, userID string, studySetID int64, definitionID int64, current *domain.DefinitionSnapshot, target *domain.DefinitionSnapshot) error {
+	definitionRepo := ds.GetDefinitionRepo()
+
+	switch {
+	case reflect.DeepEqual(current, target):
+		return nil
+	case target == nil:
+		if err := definitionRepo.Delete(ctx, definitionID); err != nil {
+			return fmt.Errorf("%w: failed to delete the definition: %w", ErrRepoFailed, err)
+		}
+	case current == nil:
+		insertedID, err := definitionRepo.Insert(ctx, studySetID, &domain.InsertDefinitionData{
+			Phrase:    target.Phrase,
+			Meaning:   target.Meaning,
+			Sentences: target.Sentences,
+		})
+		if err != nil {
+			return fmt.Errorf("%w: failed to insert the definition: %w", ErrRepoFailed, err)
+		}
+		definitionID = insertedID
+	default:
+		if err := definitionRepo.Update(ctx, definitionID, &domain.UpdateDefinitionData{
+			Phrase:    target.Phrase,
+			Meaning:   target.Meaning,
+			Sentences: target.Sentences,
+		}); err != nil {
+			return fmt.Errorf("%w: failed to update the definition: %w", ErrRepoFailed, err)
+		}
+	}
+
+	return recordRevision(ctx, ds, userID, studySetID, &domain.RevisionChange{
+		Target:           domain.RevisionTargetDefinition,
+		DefinitionId:     &definitionID,
+		DefinitionBefore: current,
+		DefinitionAfter:  target,
+	})
+}
+
+// recordRevision stores the change of the study set made by the user. Changes that do not change anything are skipped.
+func recordRevision(ctx context.Context, ds domain.DataStore, userID string, studySetID int64, change *domain.RevisionChange) error {
+	var changes []*domain.FieldChange
+	if change.Target == domain.RevisionTargetStudySet {
+		_, changes = describeChange(change.StudySetBefore, change.StudySetAfter)
+	} else {
+		_, changes = describeChange(change.DefinitionBefore, change.DefinitionAfter)
+	}
+	if len(changes) == 0 {
+		return nil
+	}
+
+	if err := ds.GetRevisionRepo().Insert(ctx, studySetID, userID, change); err != nil {
+		return fmt.Errorf("%w: failed to record the revision: %w", ErrRepoFailed, err)
+	}
+
+	return nil
+}
+
+// studySetSnapshot returns versioned fields of the study set. Missing tags are stored as an empty list.
+func studySetSnapshot(studySet *domain.StudySetWithAuthor, tags []string) *domain.StudySetSnapshot {
+	if tags == nil {
+		tags = []string{}
+	}
+	return &domain.StudySetSnapshot{
+		Name:               studySet.Name,
+		Description:        studySet.Description,
+		PhraseLanguage:     studySet.PhraseLanguage,
+		DefinitionLanguage: studySet.DefinitionLanguage,
+		Icon:               studySet.Icon,
+		Color:              studySet.Color,
+		CefrLevel:          studySet.CefrLevel,
+		Category:           studySet.Category,
+		Tags:               tags,
+	}
+}
+
+// definitionSnapshot returns versioned fields of the definition.
+// Missing sentences are stored as an empty list, so that they are not reported as changed.
+func definitionSnapshot(phrase string, meaning string, sentences []string) *domain.DefinitionSnapshot {
+	if sentences == nil {
+		sentences = []string{}
+	}
+	return &domain.DefinitionSnapshot{
+		Phrase:    phrase,
+		Meaning:   meaning,
+		Sentences: sentences,
+	}
+}
+
+// studySetState represents versioned content of a study set with its definitions by id.
+type studySetState struct {
+	studySet    *domain.StudySetSnapshot
+	definitions map[int64]*domain.DefinitionSnapshot
+}
+
+func currentStudySetState(ctx context.Context, ds domain.DataStore, studySet *domain.StudySetWithAuthor) (*studySetState, error) {
+	tags, err := ds.GetTagRepo().GetForStudySet(ctx, studySet.Id)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to get tags: %w", ErrRepoFailed, err)
+	}
+
+	definitionRows, err := ds.GetDefinitionRepo().GetAllFor(ctx, studySet.Id)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to get definitions: %w", ErrRepoFailed, err)
+	}
+
+	state := &studySetState{
+		studySet:    studySetSnapshot(studySet, tags),
+		definitions: make(map[int64]*domain.DefinitionSnapshot, len(definitionRows)),
+	}
+	for _, definitionRow := range definitionRows {
+		state.definitions[definitionRow.Id] = definitionSnapshot(definitionRow.Phrase, definitionRow.Meaning, definitionRow.Sentences)
+	}
+
+	return state, nil
+}
+
+// studySetStateAt returns the state of the study set right after the revision by undoing all the newer revisions.
+func studySetStateAt(ctx context.Context, ds domain.DataStore, studySet *domain.StudySetWithAuthor, revisionID int64) (*studySetState, error) {
+	state, err := currentStudySetState(ctx, ds, studySet)
+	if err != nil {
+		return nil, err
+	}
+
+	revisions, err := ds.GetRevisionRepo().GetNewerThan(ctx, studySet.Id, revisionID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to get newer revisions: %w", ErrRepoFailed, err)
+	}
+
+	for _, revision := range revisions {
+		state.undo(revision)
+	}
+
+	return state, nil
+}
+
+func (s *studySetState) undo(revision *domain.RevisionRow) {
+	if revision.Target == domain.RevisionTargetStudySet {
+		if revision.StudySetBefore != nil {
+			s.studySet = revision.StudySetBefore
+		}
+		return
+	}
+
+	if revision.DefinitionId == nil {
+		return
+	}
+	if revision.DefinitionBefore == nil {
+		delete(s.definitions, *revision.DefinitionId)
+	} else {
+		s.definitions[*revision.DefinitionId] = revision.DefinitionBefore
+	}
+}
+
+// definitionIDs returns ids of the definitions in ascending order.
+func (s *studySetState) definitionIDs() []int64 {
+	definitionIDs := make([]int64, 0, len(s.definitions))
+	for definitionID := range s.definitions {
+		definitionIDs = append(definitionIDs, definitionID)
+	}
+	sort.Slice(definitionIDs, func(i, j int) bool {
+		return definitionIDs[i] < definitionIDs[j]
+	})
+	return definitionIDs
+}
+
+func newRevision(row *domain.RevisionRow) *domain.Revision {
+	revision := &domain.Revision{
+		Id:           row.Id,
+		Author:       row.Author,
+		Target:       row.Target,
+		DefinitionId: row.DefinitionId,
+		CreatedAt:    row.CreatedAt,
+	}
+
+	if row.Target == domain.RevisionTargetStudySet {
+		revision.Action, revision.Changes = describeChange(row.StudySetBefore, row.StudySetAfter)
+	} else {
+		revision.Action, revision.Changes = describeChange(row.DefinitionBefore, row.DefinitionAfter)
+	}
+
+	return revision
+}
+
+// describeChange returns the action and the field level diff between two snapshots.
+// Fields are named after their JSON names and listed in the order of the snapshot type.
+func describeChange[T any](before *T, after *T) (string, []*domain.FieldChange) {
+	action := domain.RevisionActionUpdate
+	if before == nil {
+		action = domain.RevisionActionCreate
+	} else if after == nil {
+		action = domain.RevisionActionDelete
+	}
+
+	changes := make([]*domain.FieldChange, 0)
+	if before == nil && after == nil {
+		return action, changes
+	}
+
+	snapshotType := reflect.TypeOf((*T)(nil)).Elem()
+	for i := 0; i < snapshotType.NumField(); i++ {
+		change := &domain.FieldChange{
+			Field: strings.Split(snapshotType.Field(i).Tag.Get("json"), ",")[0],
+		}
+		if before != nil {
+			change.Before = reflect.ValueOf(before).Elem().Field(i).Interface()
+		}
+		if after != nil {
+			change.After = reflect.ValueOf(after).Elem().Field(i).Interface()
+		}
+		if before != nil && after != nil && reflect.DeepEqual(change.Before, change.After) {
+			continue
+		}
+		changes = append(changes, change)
+	}
+
+	return action, changes
+}
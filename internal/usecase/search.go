@@ -0,0 +1,131 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/go-playground/validator/v10"
+
+	"ailingo/internal/domain"
+	"ailingo/pkg/highlight"
+)
+
+const (
+	defaultSearchLimit = 20
+
+	// searchDefinitionLimit caps the number of matching definitions considered for grouping.
+	searchDefinitionLimit = 500
+	// maxSearchDefinitionsPerStudySet is the number of matching definitions shown in a single result.
+	maxSearchDefinitionsPerStudySet = 5
+	// searchStudySetWeight makes a match in the study set itself worth more than a match in one of its definitions.
+	searchStudySetWeight = 2
+	searchSnippetLength  = 160
+)
+
+type searchUseCase struct {
+	dataStore domain.DataStore
+	validate  *validator.Validate
+}
+
+func NewSearchUseCase(dataStore domain.DataStore, validate *validator.Validate) domain.SearchUseCase {
+	return &searchUseCase{
+		dataStore: dataStore,
+		validate:  validate,
+	}
+}
+
+func (uc *searchUseCase) Search(ctx context.Context, userID string, searchData *domain.SearchData) ([]*domain.SearchResult, error) {
+	if err := uc.validate.Struct(searchData); err != nil {
+		return nil, fmt.Errorf("%w: invalid search data: %w", ErrValidation, err)
+	}
+	if searchData.Limit == 0 {
+		searchData.Limit = defaultSearchLimit
+	}
+
+	terms := highlight.Terms(searchData.Query)
+	if len(terms) == 0 {
+		return nil, fmt.Errorf("%w: query does not contain any words", ErrValidation)
+	}
+
+	searchRepo := uc.dataStore.GetSearchRepo()
+
+	studySetHits, err := searchRepo.SearchStudySets(ctx, userID, searchData.Query, searchData.Limit)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to search study sets: %w", ErrRepoFailed, err)
+	}
+	definitionHits, err := searchRepo.SearchDefinitions(ctx, userID, searchData.Query, searchDefinitionLimit)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to search definitions: %w", ErrRepoFailed, err)
+	}
+
+	results := make(map[int64]*domain.SearchResult)
+	for _, hit := range studySetHits {
+		studySet := hit.StudySetWithAuthor
+		results[hit.Id] = &domain.SearchResult{
+			StudySet:    &studySet,
+			Score:       hit.Score * searchStudySetWeight,
+			Definitions: make([]*domain.SearchDefinition, 0),
+		}
+	}
+
+	// Definitions come sorted by relevance, so the best matches of each study set are shown.
+	for _, hit := range definitionHits {
+		result, ok := results[hit.StudySetId]
+		if !ok {
+			result = &domain.SearchResult{
+				Definitions: make([]*domain.SearchDefinition, 0),
+			}
+			results[hit.StudySetId] = result
+		}
+
+		result.Score += hit.Score
+		result.MatchedDefinitions++
+		if len(result.Definitions) < maxSearchDefinitionsPerStudySet {
+			result.Definitions = append(result.Definitions, &domain.SearchDefinition{
+				Id:      hit.Id,
+				Phrase:  highlight.Highlight(hit.Phrase, terms),
+				Meaning: highlight.Highlight(hit.Meaning, terms),
+			})
+		}
+	}
+
+	studySetIDs := make([]int64, 0, len(results))
+	for studySetID := range results {
+		studySetIDs = append(studySetIDs, studySetID)
+	}
+	sort.Slice(studySetIDs, func(i, j int) bool {
+		a, b := results[studySetIDs[i]], results[studySetIDs[j]]
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		return studySetIDs[i] > studySetIDs[j]
+	})
+	if len(studySetIDs) > searchData.Limit {
+		studySetIDs = studySetIDs[:searchData.Limit]
+	}
+
+	ranked := make([]*domain.SearchResult, 0, len(studySetIDs))
+	for _, studySetID := range studySetIDs {
+		result := results[studySetID]
+		// Study sets matched only through their definitions have not been loaded yet.
+		if result.StudySet == nil {
+			studySet, err := uc.dataStore.GetStudySetRepo().GetById(ctx, studySetID)
+			if err != nil {
+				return nil, fmt.Errorf("%w: failed to get the study set: %w", ErrRepoFailed, err)
+			}
+			if studySet == nil {
+				continue
+			}
+			result.StudySet = studySet
+		}
+		ranked = append(ranked, result)
+	}
+
+	for _, result := range ranked {
+		result.Name = highlight.Highlight(result.StudySet.Name, terms)
+		result.Description = highlight.Snippet(result.StudySet.Description, terms, searchSnippetLength)
+	}
+
+	return ranked, nil
+}
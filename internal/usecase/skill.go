@@ -0,0 +1,178 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"ailingo/internal/domain"
+	"ailingo/pkg/elo"
+	"ailingo/pkg/srs"
+)
+
+type skillUseCase struct {
+	dataStore domain.DataStore
+}
+
+func NewSkillUseCase(dataStore domain.DataStore) domain.SkillUseCase {
+	return &skillUseCase{
+		dataStore: dataStore,
+	}
+}
+
+func (uc *skillUseCase) GetAll(ctx context.Context, userID string) ([]*domain.Skill, error) {
+	skills, err := uc.dataStore.GetSkillRepo().GetAll(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to get skills: %w", ErrRepoFailed, err)
+	}
+
+	for _, skill := range skills {
+		skill.ExpectedAccuracy = elo.Probability(skill.Ability, 0)
+	}
+
+	return skills, nil
+}
+
+// updateSkill learns the user's ability in the study set's language and difficulties of the reviewed definitions
+// from the review outcomes. Reviews must belong to the given study set and be sorted by answer time.
+func updateSkill(ctx context.Context, ds domain.DataStore, userID string, studySetID int64, reviews []*domain.ReviewGradeData) error {
+	if len(reviews) == 0 {
+		return nil
+	}
+
+	skillRepo := ds.GetSkillRepo()
+
+	studySet, err := ds.GetStudySetRepo().GetById(ctx, studySetID)
+	if err != nil {
+		return fmt.Errorf("%w: failed to get the study set: %w", ErrRepoFailed, err)
+	}
+	if studySet == nil {
+		return &ErrNotFound{
+			Resource: StudySetResource,
+		}
+	}
+
+	skill, err := skillRepo.Get(ctx, userID, studySet.PhraseLanguage)
+	if err != nil {
+		return fmt.Errorf("%w: failed to get the skill: %w", ErrRepoFailed, err)
+	}
+	var ability elo.Estimate
+	if skill != nil {
+		ability = elo.Estimate{
+			Value:   skill.Ability,
+			Answers: skill.Answers,
+		}
+	}
+
+	difficulties, err := getDifficulties(ctx, ds, studySetID)
+	if err != nil {
+		return err
+	}
+
+	answered := make([]int64, 0, len(reviews))
+	for _, review := range reviews {
+		if _, ok := difficulties[review.DefinitionId]; !ok {
+			difficulties[review.DefinitionId] = elo.Estimate{}
+		}
+		ability, difficulties[review.DefinitionId] = elo.Update(ability, difficulties[review.DefinitionId], review.Grade >= srs.PassingGrade)
+		answered = append(answered, review.DefinitionId)
+	}
+
+	if err := skillRepo.Upsert(ctx, userID, &domain.Skill{
+		Language: studySet.PhraseLanguage,
+		Ability:  ability.Value,
+		Answers:  ability.Answers,
+	}); err != nil {
+		return fmt.Errorf("%w: failed to save the skill: %w", ErrRepoFailed, err)
+	}
+
+	for _, definitionID := range answered {
+		difficulty := difficulties[definitionID]
+		if err := skillRepo.UpsertDifficulty(ctx, &domain.DefinitionDifficulty{
+			DefinitionId: definitionID,
+			Difficulty:   difficulty.Value,
+			Answers:      difficulty.Answers,
+		}); err != nil {
+			return fmt.Errorf("%w: failed to save the definition difficulty: %w", ErrRepoFailed, err)
+		}
+	}
+
+	return nil
+}
+
+// getDifficulties returns difficulty estimates of definitions from the study set.
+// Definitions that have never been answered are missing, which stands for an average difficulty.
+func getDifficulties(ctx context.Context, ds domain.DataStore, studySetID int64) (map[int64]elo.Estimate, error) {
+	rows, err := ds.GetSkillRepo().GetDifficultiesFor(ctx, studySetID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to get definition difficulties: %w", ErrRepoFailed, err)
+	}
+
+	difficulties := make(map[int64]elo.Estimate, len(rows))
+	for _, row := range rows {
+		difficulties[row.DefinitionId] = elo.Estimate{
+			Value:   row.Difficulty,
+			Answers: row.Answers,
+		}
+	}
+
+	return difficulties, nil
+}
+
+// getAbility returns the user's ability in the language or an average ability if the user has never answered in it.
+func getAbility(ctx context.Context, ds domain.DataStore, userID string, language string) (float64, error) {
+	skill, err := ds.GetSkillRepo().Get(ctx, userID, language)
+	if err != nil {
+		return 0, fmt.Errorf("%w: failed to get the skill: %w", ErrRepoFailed, err)
+	}
+	if skill == nil {
+		return 0, nil
+	}
+	return skill.Ability, nil
+}
+
+// sortByDifficultyMatch sorts definitions, so that the ones closest to the target difficulty for the user come first.
+// Definitions at the same distance keep their order.
+func sortByDifficultyMatch(definitions []*domain.DefinitionRow, ability float64, difficulties map[int64]elo.Estimate) {
+	sort.SliceStable(definitions, func(i, j int) bool {
+		return elo.Distance(ability, difficulties[definitions[i].Id].Value) < elo.Distance(ability, difficulties[definitions[j].Id].Value)
+	})
+}
+
+// sortQueueByDifficultyMatch sorts cards, so that the ones closest to the target difficulty for the user come first.
+// Cards at the same distance keep their order.
+func sortQueueByDifficultyMatch(ctx context.Context, ds domain.DataStore, userID string, cards []*domain.QueueCard) error {
+	skills, err := ds.GetSkillRepo().GetAll(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("%w: failed to get skills: %w", ErrRepoFailed, err)
+	}
+	abilities := make(map[string]float64, len(skills))
+	for _, skill := range skills {
+		abilities[skill.Language] = skill.Ability
+	}
+
+	difficulties := make(map[int64]elo.Estimate)
+	fetched := make(map[int64]bool)
+	for _, card := range cards {
+		if fetched[card.StudySet.Id] {
+			continue
+		}
+		studySetDifficulties, err := getDifficulties(ctx, ds, card.StudySet.Id)
+		if err != nil {
+			return err
+		}
+		for definitionID, difficulty := range studySetDifficulties {
+			difficulties[definitionID] = difficulty
+		}
+		fetched[card.StudySet.Id] = true
+	}
+
+	distance := func(card *domain.QueueCard) float64 {
+		return elo.Distance(abilities[card.StudySet.PhraseLanguage], difficulties[card.Definition.Id].Value)
+	}
+	sort.SliceStable(cards, func(i, j int) bool {
+		return distance(cards[i]) < distance(cards[j])
+	})
+
+	return nil
+}
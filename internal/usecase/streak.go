@@ -0,0 +1,275 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+
+	"ailingo/internal/domain"
+)
+
+const (
+	defaultTimezone        = "UTC"
+	defaultDailyGoalType   = domain.DailyGoalReviews
+	defaultDailyGoalTarget = 20
+
+	// A streak freeze is earned every streakFreezeInterval days of the streak, up to maxStreakFreezes.
+	streakFreezeInterval = 7
+	maxStreakFreezes     = 2
+)
+
+type streakUseCase struct {
+	dataStore domain.DataStore
+	validate  *validator.Validate
+}
+
+func NewStreakUseCase(dataStore domain.DataStore, validate *validator.Validate) domain.StreakUseCase {
+	return &streakUseCase{
+		dataStore: dataStore,
+		validate:  validate,
+	}
+}
+
+func (uc *streakUseCase) GetStreak(ctx context.Context, userID string) (*domain.Streak, error) {
+	var streak *domain.Streak
+
+	err := uc.dataStore.Atomic(ctx, func(ds domain.DataStore) error {
+		preferences, err := getPreferences(ctx, ds, userID)
+		if err != nil {
+			return err
+		}
+
+		streakRow, err := ds.GetStreakRepo().Get(ctx, userID)
+		if err != nil {
+			return fmt.Errorf("%w: failed to get the streak: %w", ErrRepoFailed, err)
+		}
+
+		streak = currentStreak(streakRow, today(preferences))
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("atomic operation failed: %w", err)
+	}
+
+	return streak, nil
+}
+
+func (uc *streakUseCase) GetDailyGoal(ctx context.Context, userID string) (*domain.DailyGoal, error) {
+	var goal *domain.DailyGoal
+
+	err := uc.dataStore.Atomic(ctx, func(ds domain.DataStore) error {
+		preferences, err := getPreferences(ctx, ds, userID)
+		if err != nil {
+			return err
+		}
+
+		date := today(preferences)
+		activity, err := ds.GetStreakRepo().GetDailyActivity(ctx, userID, date)
+		if err != nil {
+			return fmt.Errorf("%w: failed to get daily activity: %w", ErrRepoFailed, err)
+		}
+
+		progress := activity.Reviews
+		if preferences.DailyGoalType == domain.DailyGoalMinutes {
+			progress = int(activity.ResponseTimeMs / time.Minute.Milliseconds())
+		}
+
+		goal = &domain.DailyGoal{
+			Type:      preferences.DailyGoalType,
+			Target:    preferences.DailyGoalTarget,
+			Progress:  progress,
+			Completed: progress >= preferences.DailyGoalTarget,
+			Date:      date,
+			Timezone:  preferences.Timezone,
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("atomic operation failed: %w", err)
+	}
+
+	return goal, nil
+}
+
+func (uc *streakUseCase) UpdateDailyGoal(ctx context.Context, userID string, updateData *domain.UpdateDailyGoalData) error {
+	if err := uc.validate.Struct(updateData); err != nil {
+		return fmt.Errorf("%w: invalid update data: %w", ErrValidation, err)
+	}
+
+	err := uc.dataStore.Atomic(ctx, func(ds domain.DataStore) error {
+		preferences, err := getPreferences(ctx, ds, userID)
+		if err != nil {
+			return err
+		}
+
+		preferences.DailyGoalType = updateData.Type
+		preferences.DailyGoalTarget = updateData.Target
+		preferences.Timezone = updateData.Timezone
+
+		if err := ds.GetPreferencesRepo().Upsert(ctx, userID, preferences); err != nil {
+			return fmt.Errorf("%w: failed to save preferences: %w", ErrRepoFailed, err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return fmt.Errorf("atomic operation failed: %w", err)
+	}
+
+	return nil
+}
+
+// getPreferences returns preferences of the given user, falling back to defaults if the user has never saved them.
+func getPreferences(ctx context.Context, ds domain.DataStore, userID string) (*domain.UserPreferences, error) {
+	preferences, err := ds.GetPreferencesRepo().Get(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to get preferences: %w", ErrRepoFailed, err)
+	}
+	if preferences == nil {
+		preferences = &domain.UserPreferences{
+			Timezone:        defaultTimezone,
+			DailyGoalType:   defaultDailyGoalType,
+			DailyGoalTarget: defaultDailyGoalTarget,
+		}
+	}
+	return preferences, nil
+}
+
+// recordStudyActivity adds the reviews to the user's daily activity and extends their streak.
+// Days are determined in the user's timezone.
+func recordStudyActivity(ctx context.Context, ds domain.DataStore, userID string, reviews []*domain.ReviewGradeData) error {
+	if len(reviews) == 0 {
+		return nil
+	}
+
+	streakRepo := ds.GetStreakRepo()
+
+	preferences, err := getPreferences(ctx, ds, userID)
+	if err != nil {
+		return err
+	}
+	location := userLocation(preferences)
+
+	activityByDate := make(map[string]*domain.DailyActivityRow)
+	for _, review := range reviews {
+		date := review.AnsweredAt.In(location).Format(time.DateOnly)
+		activity, ok := activityByDate[date]
+		if !ok {
+			activity = &domain.DailyActivityRow{}
+			activityByDate[date] = activity
+		}
+		activity.Reviews++
+		activity.ResponseTimeMs += int64(review.ResponseTimeMs)
+	}
+
+	dates := make([]string, 0, len(activityByDate))
+	for date, activity := range activityByDate {
+		if err := streakRepo.AddDailyActivity(ctx, userID, date, activity); err != nil {
+			return fmt.Errorf("%w: failed to add daily activity: %w", ErrRepoFailed, err)
+		}
+		dates = append(dates, date)
+	}
+	sort.Strings(dates)
+
+	streakRow, err := streakRepo.Get(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("%w: failed to get the streak: %w", ErrRepoFailed, err)
+	}
+	if streakRow == nil {
+		streakRow = &domain.StreakRow{}
+	}
+
+	for _, date := range dates {
+		advanceStreak(streakRow, date)
+	}
+
+	if err := streakRepo.Upsert(ctx, userID, streakRow); err != nil {
+		return fmt.Errorf("%w: failed to save the streak: %w", ErrRepoFailed, err)
+	}
+
+	return nil
+}
+
+// advanceStreak records study activity on the given date.
+// Activity older than the last active date does not affect the streak.
+func advanceStreak(streak *domain.StreakRow, date string) {
+	if streak.LastActiveDate == nil {
+		streak.Current = 1
+	} else {
+		gap := daysBetween(*streak.LastActiveDate, date)
+		switch {
+		case gap <= 0:
+			return
+		case gap == 1:
+			streak.Current++
+		case gap-1 <= streak.FreezesAvailable:
+			streak.FreezesAvailable -= gap - 1
+			streak.Current++
+		default:
+			streak.Current = 1
+		}
+	}
+
+	if streak.Current%streakFreezeInterval == 0 && streak.FreezesAvailable < maxStreakFreezes {
+		streak.FreezesAvailable++
+	}
+
+	streak.Longest = max(streak.Longest, streak.Current)
+	streak.LastActiveDate = &date
+}
+
+// currentStreak returns the streak as seen on the given date.
+// The streak is reported as lost if missed days cannot be covered with freezes.
+func currentStreak(streakRow *domain.StreakRow, date string) *domain.Streak {
+	if streakRow == nil {
+		return &domain.Streak{}
+	}
+
+	streak := &domain.Streak{
+		Current:          streakRow.Current,
+		Longest:          streakRow.Longest,
+		FreezesAvailable: streakRow.FreezesAvailable,
+		LastActiveDate:   streakRow.LastActiveDate,
+	}
+
+	if streakRow.LastActiveDate != nil {
+		gap := daysBetween(*streakRow.LastActiveDate, date)
+		streak.ActiveToday = gap == 0
+		if gap-1 > streakRow.FreezesAvailable {
+			streak.Current = 0
+		}
+	}
+
+	return streak
+}
+
+// today returns the current date in the user's timezone.
+func today(preferences *domain.UserPreferences) string {
+	return time.Now().In(userLocation(preferences)).Format(time.DateOnly)
+}
+
+func userLocation(preferences *domain.UserPreferences) *time.Location {
+	location, err := time.LoadLocation(preferences.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return location
+}
+
+// daysBetween returns the number of calendar days from a to b. Both dates must be formatted as YYYY-MM-DD.
+func daysBetween(a string, b string) int {
+	from, errFrom := time.Parse(time.DateOnly, a)
+	to, errTo := time.Parse(time.DateOnly, b)
+	if errFrom != nil || errTo != nil {
+		return 0
+	}
+	return int(to.Sub(from).Hours() / 24)
+}
@@ -2,18 +2,31 @@ package usecase
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"time"
+
+	"github.com/go-playground/validator/v10"
 
 	"ailingo/internal/domain"
 )
 
+const (
+	defaultStudySessionHistoryLimit = 20
+	maxStudySessionHistoryLimit     = 100
+)
+
+var ErrStudySessionFinished = errors.New("study session has already been finished")
+
 type studySessionUseCase struct {
 	datastore domain.DataStore
+	validate  *validator.Validate
 }
 
-func NewStudySessionUseCase(datastore domain.DataStore) domain.StudySessionUseCase {
+func NewStudySessionUseCase(datastore domain.DataStore, validate *validator.Validate) domain.StudySessionUseCase {
 	return &studySessionUseCase{
 		datastore: datastore,
+		validate:  validate,
 	}
 }
 
@@ -28,6 +41,34 @@ func (uc *studySessionUseCase) GetRecent(ctx context.Context, userID string) ([]
 	return studySessions, nil
 }
 
+func (uc *studySessionUseCase) GetHistory(ctx context.Context, userID string, cursor int64, limit int) (*domain.StudySessionPage, error) {
+	if limit == 0 {
+		limit = defaultStudySessionHistoryLimit
+	}
+	if limit < 0 || limit > maxStudySessionHistoryLimit {
+		return nil, fmt.Errorf("%w: limit must be between 1 and %d", ErrValidation, maxStudySessionHistoryLimit)
+	}
+	if cursor < 0 {
+		return nil, fmt.Errorf("%w: invalid cursor", ErrValidation)
+	}
+
+	// One additional session is fetched to find out if there is a next page.
+	studySessions, err := uc.datastore.GetStudySessionRepo().GetHistory(ctx, userID, cursor, limit+1)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to get study session history: %w", ErrRepoFailed, err)
+	}
+
+	page := &domain.StudySessionPage{
+		Sessions: studySessions,
+	}
+	if len(studySessions) > limit {
+		page.Sessions = studySessions[:limit]
+		page.NextCursor = &page.Sessions[limit-1].Id
+	}
+
+	return page, nil
+}
+
 func (uc *studySessionUseCase) GetForStudySet(ctx context.Context, userID string, studySetID int64) (*domain.StudySession, error) {
 	studySessionRepo := uc.datastore.GetStudySessionRepo()
 
@@ -44,40 +85,219 @@ func (uc *studySessionUseCase) GetForStudySet(ctx context.Context, userID string
 	return studySession, nil
 }
 
-func (uc *studySessionUseCase) Refresh(ctx context.Context, userID string, studySetID int64) error {
+func (uc *studySessionUseCase) GetDetails(ctx context.Context, userID string, studySessionID int64) (*domain.StudySessionDetails, error) {
+	studySessionRepo := uc.datastore.GetStudySessionRepo()
+
+	studySession, err := getOwnStudySession(ctx, uc.datastore, userID, studySessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	outcomes, err := studySessionRepo.GetOutcomes(ctx, studySessionID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to get study session outcomes: %w", ErrRepoFailed, err)
+	}
+
+	return &domain.StudySessionDetails{
+		StudySessionSummary: studySession.StudySessionSummary,
+		Outcomes:            outcomes,
+	}, nil
+}
+
+func (uc *studySessionUseCase) Start(ctx context.Context, userID string, startData *domain.StartStudySessionData) (int64, error) {
+	if err := uc.validate.Struct(startData); err != nil {
+		return 0, fmt.Errorf("%w: invalid study session: %w", ErrValidation, err)
+	}
+
+	if _, err := newPermissionService(uc.datastore).Authorize(ctx, userID, startData.StudySetId, actionView); err != nil {
+		return 0, err
+	}
+
+	studySessionID, err := uc.datastore.GetStudySessionRepo().Start(ctx, userID, startData.StudySetId, startData.Mode, startData.Direction)
+	if err != nil {
+		return 0, fmt.Errorf("%w: failed to start the study session: %w", ErrRepoFailed, err)
+	}
+
+	return studySessionID, nil
+}
+
+func (uc *studySessionUseCase) Finish(ctx context.Context, userID string, studySessionID int64, finishData *domain.FinishStudySessionData) (*domain.StudySessionSummary, error) {
+	if err := uc.validate.Struct(finishData); err != nil {
+		return nil, fmt.Errorf("%w: invalid study session outcomes: %w", ErrValidation, err)
+	}
+
+	var summary *domain.StudySessionSummary
+
 	if err := uc.datastore.Atomic(ctx, func(ds domain.DataStore) error {
 		studySessionRepo := ds.GetStudySessionRepo()
 
-		studySessionExists, err := studySessionRepo.Exists(ctx, userID, studySetID)
+		studySession, err := getOwnStudySession(ctx, ds, userID, studySessionID)
 		if err != nil {
-			return fmt.Errorf("%w: failed to check if study session exists: %w", ErrRepoFailed, err)
+			return err
+		}
+		if studySession.FinishedAt != nil {
+			return ErrStudySessionFinished
 		}
 
-		if studySessionExists {
-			// If study session already exists we want to update last session timestamp
-			if err := studySessionRepo.Refresh(ctx, userID, studySetID); err != nil {
-				return fmt.Errorf("%w: failed to refresh existing study session: %w", ErrRepoFailed, err)
-			}
-		} else {
-			// Otherwise we want to create a new study session if the study set exists.
-			studySetExists, err := ds.GetStudySetRepo().Exists(ctx, studySetID)
-			if err != nil {
-				return fmt.Errorf("%w: failed to check if study set exists: %w", ErrRepoFailed, err)
+		definitionIDs, err := getStudySessionDefinitionIds(ctx, ds, studySession)
+		if err != nil {
+			return err
+		}
+		for _, outcome := range finishData.Outcomes {
+			if !definitionIDs[outcome.DefinitionId] {
+				return fmt.Errorf("%w: definition %d is not part of the study session", ErrValidation, outcome.DefinitionId)
 			}
-			if !studySetExists {
-				return &ErrNotFound{
-					Resource: StudySetResource,
-				}
+		}
+
+		finishedAt := time.Now()
+		finishRow := summarizeStudySession(studySession.StartedAt, finishData.Outcomes, finishedAt)
+		if err := studySessionRepo.Finish(ctx, studySessionID, finishRow); err != nil {
+			return fmt.Errorf("%w: failed to finish the study session: %w", ErrRepoFailed, err)
+		}
+		for i, outcome := range finishData.Outcomes {
+			if err := studySessionRepo.InsertOutcome(ctx, studySessionID, i, outcome); err != nil {
+				return fmt.Errorf("%w: failed to insert study session outcome: %w", ErrRepoFailed, err)
 			}
-			if err := studySessionRepo.Create(ctx, userID, studySetID); err != nil {
-				return fmt.Errorf("%w: failed to create a new study session: %w", ErrRepoFailed, err)
+		}
+		// Answers of review sessions already counted when they were graded.
+		if studySession.Mode != domain.StudyModeReview {
+			if err := recordStudyActivity(ctx, ds, userID, studySessionActivity(finishData.Outcomes, finishedAt)); err != nil {
+				return err
 			}
 		}
 
+		finished, err := studySessionRepo.Get(ctx, studySessionID)
+		if err != nil {
+			return fmt.Errorf("%w: failed to get the study session: %w", ErrRepoFailed, err)
+		}
+		summary = &finished.StudySessionSummary
+
 		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("atomic operation failed: %w", err)
+	}
+
+	return summary, nil
+}
+
+// getStudySessionDefinitionIds returns ids of definitions that can be studied in the study session,
+// which are those of its study set or of the study sets in its folder and all its subfolders.
+func getStudySessionDefinitionIds(ctx context.Context, ds domain.DataStore, studySession *domain.StudySessionRow) (map[int64]bool, error) {
+	studySetIDs := []int64{studySession.StudySetId}
+	if studySession.FolderId != nil {
+		studySets, err := getFolderSubtreeStudySets(ctx, ds, studySession.UserId, *studySession.FolderId)
+		if err != nil {
+			return nil, err
+		}
+		studySetIDs = studySetIDs[:0]
+		for _, studySet := range studySets {
+			studySetIDs = append(studySetIDs, studySet.Id)
+		}
+	}
+
+	definitionIDs := make(map[int64]bool)
+	for _, studySetID := range studySetIDs {
+		definitions, err := ds.GetDefinitionRepo().GetAllFor(ctx, studySetID)
+		if err != nil {
+			return nil, fmt.Errorf("%w: failed to get definitions: %w", ErrRepoFailed, err)
+		}
+		for _, definition := range definitions {
+			definitionIDs[definition.Id] = true
+		}
+	}
+
+	return definitionIDs, nil
+}
+
+func (uc *studySessionUseCase) Refresh(ctx context.Context, userID string, studySetID int64) error {
+	if err := uc.datastore.Atomic(ctx, func(ds domain.DataStore) error {
+		return refreshStudySession(ctx, ds, userID, studySetID)
 	}); err != nil {
 		return fmt.Errorf("atomic operation failed: %w", err)
 	}
 
 	return nil
 }
+
+// refreshStudySession updates last session timestamp of the given study session.
+// If the session does not exist a new session is created for the study set.
+func refreshStudySession(ctx context.Context, ds domain.DataStore, userID string, studySetID int64) error {
+	studySessionRepo := ds.GetStudySessionRepo()
+
+	studySessionExists, err := studySessionRepo.Exists(ctx, userID, studySetID)
+	if err != nil {
+		return fmt.Errorf("%w: failed to check if study session exists: %w", ErrRepoFailed, err)
+	}
+
+	if studySessionExists {
+		// If study session already exists we want to update last session timestamp
+		if err := studySessionRepo.Refresh(ctx, userID, studySetID); err != nil {
+			return fmt.Errorf("%w: failed to refresh existing study session: %w", ErrRepoFailed, err)
+		}
+	} else {
+		// Otherwise we want to create a new study session if the user can see the study set.
+		if _, err := newPermissionService(ds).Authorize(ctx, userID, studySetID, actionView); err != nil {
+			return err
+		}
+		if err := studySessionRepo.Create(ctx, userID, studySetID); err != nil {
+			return fmt.Errorf("%w: failed to create a new study session: %w", ErrRepoFailed, err)
+		}
+	}
+
+	return nil
+}
+
+// getOwnStudySession returns the study session if it belongs to the given user.
+func getOwnStudySession(ctx context.Context, ds domain.DataStore, userID string, studySessionID int64) (*domain.StudySessionRow, error) {
+	studySession, err := ds.GetStudySessionRepo().Get(ctx, studySessionID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to get the study session: %w", ErrRepoFailed, err)
+	}
+	if studySession == nil {
+		return nil, &ErrNotFound{
+			Resource: StudySessionResource,
+		}
+	}
+	if studySession.UserId != userID {
+		return nil, ErrForbidden
+	}
+
+	return studySession, nil
+}
+
+// summarizeStudySession computes the summary of a study session finished at the given time.
+// Cards seen counts distinct definitions, as a card may be shown more than once in a session.
+func summarizeStudySession(startedAt *time.Time, outcomes []*domain.StudySessionOutcome, finishedAt time.Time) *domain.FinishStudySessionRow {
+	var summary domain.FinishStudySessionRow
+
+	if startedAt != nil && finishedAt.After(*startedAt) {
+		summary.DurationMs = finishedAt.Sub(*startedAt).Milliseconds()
+	}
+
+	seen := make(map[int64]bool)
+	for _, outcome := range outcomes {
+		seen[outcome.DefinitionId] = true
+		if outcome.Correct {
+			summary.CorrectCount++
+		} else {
+			summary.IncorrectCount++
+		}
+	}
+	summary.CardsSeen = len(seen)
+
+	return &summary
+}
+
+// studySessionActivity turns the outcomes into answers counted toward the user's daily activity, all answered when
+// the session is finished. They do not change review schedules, so they are not graded.
+func studySessionActivity(outcomes []*domain.StudySessionOutcome, finishedAt time.Time) []*domain.ReviewGradeData {
+	reviews := make([]*domain.ReviewGradeData, 0, len(outcomes))
+	for _, outcome := range outcomes {
+		reviews = append(reviews, &domain.ReviewGradeData{
+			DefinitionId:   outcome.DefinitionId,
+			ResponseTimeMs: outcome.ResponseTimeMs,
+			AnsweredAt:     &finishedAt,
+		})
+	}
+	return reviews
+}
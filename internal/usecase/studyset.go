@@ -2,6 +2,8 @@ package usecase
 
 import (
 	"context"
+	"encoding/base64"
+	"encoding/json"
 	"fmt"
 
 	"github.com/go-playground/validator/v10"
@@ -10,6 +12,11 @@ import (
 	"ailingo/pkg/auth"
 )
 
+const (
+	defaultStudySetPageLimit = 20
+	maxStudySetPageLimit     = 100
+)
+
 type StudySetUseCase struct {
 	dataStore   domain.DataStore
 	userService *auth.UserService
@@ -25,21 +32,19 @@ func NewStudySetUseCase(dataStore domain.DataStore, userService *auth.UserServic
 	}
 }
 
-func (uc *StudySetUseCase) GetAll(ctx context.Context) ([]*domain.StudySetWithAuthor, error) {
-	studySetRepo := uc.dataStore.GetStudySetRepo()
-
-	studySets, err := studySetRepo.GetAll(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("%w: failed to get study sets: %w", ErrRepoFailed, err)
-	}
-
-	return studySets, nil
+func (uc *StudySetUseCase) GetPage(ctx context.Context, filter *domain.StudySetFilter, cursor string, limit int) (*domain.StudySetPage, error) {
+	// Only public study sets are listed, including the ones of the user.
+	filter.ViewerId = ""
+	filter.IncludeUnlisted = false
+	return getStudySetPage(ctx, uc.dataStore, uc.validate, filter, cursor, limit)
 }
 
-func (uc *StudySetUseCase) GetById(ctx context.Context, studySetID int64) (*domain.StudySetWithAuthor, error) {
-	studySetRepo := uc.dataStore.GetStudySetRepo()
+func (uc *StudySetUseCase) GetById(ctx context.Context, userID string, studySetID int64) (*domain.StudySetDetails, error) {
+	if _, err := newPermissionService(uc.dataStore).Authorize(ctx, userID, studySetID, actionView); err != nil {
+		return nil, err
+	}
 
-	studySet, err := studySetRepo.GetById(ctx, studySetID)
+	studySet, err := uc.dataStore.GetStudySetRepo().GetDetailsById(ctx, studySetID)
 	if err != nil {
 		return nil, fmt.Errorf("%w: failed to get the study set: %w", ErrRepoFailed, err)
 	}
@@ -49,6 +54,10 @@ func (uc *StudySetUseCase) GetById(ctx context.Context, studySetID int64) (*doma
 		}
 	}
 
+	if studySet.Tags, err = uc.dataStore.GetTagRepo().GetForStudySet(ctx, studySetID); err != nil {
+		return nil, fmt.Errorf("%w: failed to get tags: %w", ErrRepoFailed, err)
+	}
+
 	return studySet, nil
 }
 
@@ -57,31 +66,155 @@ func (uc *StudySetUseCase) Create(ctx context.Context, insertData *domain.Insert
 		return 0, fmt.Errorf("%w: invalid insert data: %w", ErrValidation, err)
 	}
 
-	insertedId, err := uc.dataStore.GetStudySetRepo().Insert(ctx, insertData)
+	if insertData.Visibility == "" {
+		insertData.Visibility = domain.VisibilityPublic
+	}
+
+	tags, err := normalizeTags(insertData.Tags)
+	if err != nil {
+		return 0, err
+	}
+
+	var insertedId int64
+
+	err = uc.dataStore.Atomic(ctx, func(ds domain.DataStore) error {
+		var err error
+		if insertedId, err = ds.GetStudySetRepo().Insert(ctx, insertData); err != nil {
+			return fmt.Errorf("%w: failed to create the study set: %w", ErrRepoFailed, err)
+		}
+
+		if err := ds.GetTagRepo().SetForStudySet(ctx, insertedId, tags); err != nil {
+			return fmt.Errorf("%w: failed to set tags: %w", ErrRepoFailed, err)
+		}
+
+		return nil
+	})
+
 	if err != nil {
-		return 0, fmt.Errorf("%w: failed to create the study set: %w", ErrRepoFailed, err)
+		return 0, fmt.Errorf("atomic operation failed: %w", err)
 	}
 
 	return insertedId, nil
 }
 
+func (uc *StudySetUseCase) Fork(ctx context.Context, userID string, studySetID int64) (int64, error) {
+	var forkID int64
+
+	err := uc.dataStore.Atomic(ctx, func(ds domain.DataStore) error {
+		studySet, err := newPermissionService(ds).Authorize(ctx, userID, studySetID, actionView)
+		if err != nil {
+			return err
+		}
+
+		tagRepo := ds.GetTagRepo()
+
+		tags, err := tagRepo.GetForStudySet(ctx, studySetID)
+		if err != nil {
+			return fmt.Errorf("%w: failed to get tags: %w", ErrRepoFailed, err)
+		}
+
+		// Forks start private, as the original might not be meant to be listed.
+		forkID, err = ds.GetStudySetRepo().Insert(ctx, &domain.InsertStudySetData{
+			AuthorId:           userID,
+			Name:               studySet.Name,
+			Description:        studySet.Description,
+			PhraseLanguage:     studySet.PhraseLanguage,
+			DefinitionLanguage: studySet.DefinitionLanguage,
+			Icon:               studySet.Icon,
+			Color:              studySet.Color,
+			CefrLevel:          studySet.CefrLevel,
+			Visibility:         domain.VisibilityPrivate,
+			Category:           studySet.Category,
+			ForkedFromId:       &studySet.Id,
+			ForkedFromAuthorId: &studySet.Author.Id,
+		})
+		if err != nil {
+			return fmt.Errorf("%w: failed to insert the fork: %w", ErrRepoFailed, err)
+		}
+
+		if err := tagRepo.SetForStudySet(ctx, forkID, tags); err != nil {
+			return fmt.Errorf("%w: failed to set tags: %w", ErrRepoFailed, err)
+		}
+
+		if err := ds.GetDefinitionRepo().CopyAll(ctx, studySetID, forkID); err != nil {
+			return fmt.Errorf("%w: failed to copy definitions: %w", ErrRepoFailed, err)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return 0, fmt.Errorf("atomic operation failed: %w", err)
+	}
+
+	return forkID, nil
+}
+
 func (uc *StudySetUseCase) Update(ctx context.Context, userID string, studySetID int64, updateData *domain.UpdateStudySetData) error {
 	if err := uc.validate.Struct(updateData); err != nil {
 		return fmt.Errorf("%w: invalid update data: %w", ErrValidation, err)
 	}
 
+	var tags []string
+	if updateData.Tags != nil {
+		var err error
+		if tags, err = normalizeTags(updateData.Tags); err != nil {
+			return err
+		}
+	}
+
 	err := uc.dataStore.Atomic(ctx, func(ds domain.DataStore) error {
-		studySetRepo := uc.dataStore.GetStudySetRepo()
+		studySetRepo := ds.GetStudySetRepo()
+		tagRepo := ds.GetTagRepo()
 
-		if err := uc.checkStudySetOwnership(ctx, studySetRepo, userID, studySetID); err != nil {
+		permissions := newPermissionService(ds)
+
+		studySet, err := permissions.Authorize(ctx, userID, studySetID, actionEdit)
+		if err != nil {
 			return err
 		}
 
+		// Clients unaware of visibility keep the current one. Editors cannot change it.
+		if updateData.Visibility == "" {
+			updateData.Visibility = studySet.Visibility
+		}
+		if updateData.Visibility != studySet.Visibility {
+			if _, err := permissions.Authorize(ctx, userID, studySetID, actionManage); err != nil {
+				return err
+			}
+		}
+
+		currentTags, err := tagRepo.GetForStudySet(ctx, studySetID)
+		if err != nil {
+			return fmt.Errorf("%w: failed to get tags: %w", ErrRepoFailed, err)
+		}
+		if tags == nil {
+			tags = currentTags
+		}
+
 		if err := studySetRepo.Update(ctx, studySetID, updateData); err != nil {
 			return fmt.Errorf("%w: Update failed: %w", ErrRepoFailed, err)
 		}
 
-		return nil
+		if err := tagRepo.SetForStudySet(ctx, studySetID, tags); err != nil {
+			return fmt.Errorf("%w: failed to set tags: %w", ErrRepoFailed, err)
+		}
+
+		return recordRevision(ctx, ds, userID, studySetID, &domain.RevisionChange{
+			Target:         domain.RevisionTargetStudySet,
+			StudySetBefore: studySetSnapshot(studySet, currentTags),
+			StudySetAfter: &domain.StudySetSnapshot{
+				Name:               updateData.Name,
+				Description:        updateData.Description,
+				PhraseLanguage:     updateData.PhraseLanguage,
+				DefinitionLanguage: updateData.DefinitionLanguage,
+				Icon:               updateData.Icon,
+				Color:              updateData.Color,
+				CefrLevel:          updateData.CefrLevel,
+				Category:           updateData.Category,
+				Tags:               tags,
+			},
+		})
 	})
 
 	if err != nil {
@@ -93,9 +226,9 @@ func (uc *StudySetUseCase) Update(ctx context.Context, userID string, studySetID
 
 func (uc *StudySetUseCase) Delete(ctx context.Context, userID string, studySetID int64) error {
 	err := uc.dataStore.Atomic(ctx, func(ds domain.DataStore) error {
-		studySetRepo := uc.dataStore.GetStudySetRepo()
+		studySetRepo := ds.GetStudySetRepo()
 
-		if err := uc.checkStudySetOwnership(ctx, studySetRepo, userID, studySetID); err != nil {
+		if _, err := newPermissionService(ds).Authorize(ctx, userID, studySetID, actionManage); err != nil {
 			return err
 		}
 
@@ -113,18 +246,93 @@ func (uc *StudySetUseCase) Delete(ctx context.Context, userID string, studySetID
 	return nil
 }
 
-func (uc *StudySetUseCase) checkStudySetOwnership(ctx context.Context, studySetRepo domain.StudySetRepo, userID string, studySetID int64) error {
-	studySet, err := studySetRepo.GetById(ctx, studySetID)
-	if err != nil {
-		return fmt.Errorf("%w: failed to get the study set: %w", ErrRepoFailed, err)
+// getStudySetPage returns a page of study sets matching the filter.
+// One additional study set is fetched to find out if there is a next page.
+func getStudySetPage(ctx context.Context, ds domain.DataStore, validate *validator.Validate, filter *domain.StudySetFilter, cursor string, limit int) (*domain.StudySetPage, error) {
+	if err := validate.Struct(filter); err != nil {
+		return nil, fmt.Errorf("%w: invalid filter: %w", ErrValidation, err)
 	}
-	if studySet == nil {
-		return &ErrNotFound{
-			Resource: StudySetResource,
+	if filter.Sort == "" {
+		filter.Sort = domain.StudySetSortNewest
+	}
+	if filter.Tag != "" {
+		var err error
+		if filter.Tag, err = normalizeTag(filter.Tag); err != nil {
+			return nil, err
 		}
 	}
-	if studySet.Author.Id != userID {
-		return ErrForbidden
+
+	if limit == 0 {
+		limit = defaultStudySetPageLimit
 	}
-	return nil
+	if limit < 0 || limit > maxStudySetPageLimit {
+		return nil, fmt.Errorf("%w: limit must be between 1 and %d", ErrValidation, maxStudySetPageLimit)
+	}
+
+	var after *domain.StudySetCursor
+	if cursor != "" {
+		var err error
+		if after, err = decodeStudySetCursor(cursor); err != nil {
+			return nil, fmt.Errorf("%w: invalid cursor: %w", ErrValidation, err)
+		}
+		if after.Sort != filter.Sort {
+			return nil, fmt.Errorf("%w: cursor does not match the sort option", ErrValidation)
+		}
+	}
+
+	studySets, err := ds.GetStudySetRepo().GetPage(ctx, filter, after, limit+1)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to get study sets: %w", ErrRepoFailed, err)
+	}
+
+	page := &domain.StudySetPage{
+		StudySets: studySets,
+	}
+	if len(studySets) > limit {
+		page.StudySets = studySets[:limit]
+		nextCursor := encodeStudySetCursor(studySetCursor(filter.Sort, page.StudySets[limit-1]))
+		page.NextCursor = &nextCursor
+	}
+
+	return page, nil
+}
+
+// studySetCursor returns the cursor pointing at the given study set.
+func studySetCursor(sort string, studySet *domain.StudySetListing) *domain.StudySetCursor {
+	cursor := &domain.StudySetCursor{
+		Sort: sort,
+		Id:   studySet.Id,
+	}
+	switch sort {
+	case domain.StudySetSortStars:
+		cursor.Count = studySet.Stars
+	case domain.StudySetSortStudied:
+		cursor.Count = studySet.Learners
+	case domain.StudySetSortName:
+		cursor.Name = studySet.Name
+	}
+	return cursor
+}
+
+// encodeStudySetCursor encodes the cursor as an opaque URL safe string.
+func encodeStudySetCursor(cursor *domain.StudySetCursor) string {
+	cursorJson, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(cursorJson)
+}
+
+func decodeStudySetCursor(encoded string) (*domain.StudySetCursor, error) {
+	cursorJson, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, err
+	}
+
+	var cursor domain.StudySetCursor
+	if err := json.Unmarshal(cursorJson, &cursor); err != nil {
+		return nil, err
+	}
+	if cursor.Id <= 0 {
+		return nil, fmt.Errorf("missing study set id")
+	}
+
+	return &cursor, nil
 }
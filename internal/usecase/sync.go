@@ -0,0 +1,217 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+
+	"ailingo/internal/domain"
+	"ailingo/pkg/srs"
+)
+
+const (
+	// syncCursorOverlap makes consecutive deltas overlap, so that changes committed by concurrent transactions
+	// with timestamps slightly older than the cursor are not missed. Deltas are idempotent, so the overlap is harmless.
+	syncCursorOverlap = 5 * time.Second
+	// maxSyncClockSkew is how far ahead of the server clock the client clock can be.
+	maxSyncClockSkew = 5 * time.Minute
+)
+
+const (
+	syncRejectionDefinitionNotFound = "definition not found"
+	syncRejectionFutureAnswer       = "answer cannot be given in the future"
+)
+
+type syncUseCase struct {
+	dataStore domain.DataStore
+	validate  *validator.Validate
+}
+
+func NewSyncUseCase(dataStore domain.DataStore, validate *validator.Validate) domain.SyncUseCase {
+	return &syncUseCase{
+		dataStore: dataStore,
+		validate:  validate,
+	}
+}
+
+func (uc *syncUseCase) Sync(ctx context.Context, userID string, syncData *domain.SyncData) (*domain.SyncResult, error) {
+	if err := uc.validate.Struct(syncData); err != nil {
+		return nil, fmt.Errorf("%w: invalid sync data: %w", ErrValidation, err)
+	}
+
+	result := &domain.SyncResult{
+		Accepted:   make([]string, 0),
+		Duplicates: make([]string, 0),
+		Rejected:   make([]*domain.SyncRejection, 0),
+	}
+
+	err := uc.dataStore.Atomic(ctx, func(ds domain.DataStore) error {
+		reviewRepo := ds.GetReviewRepo()
+		syncRepo := ds.GetSyncRepo()
+
+		// The cursor comes from the database clock, as change timestamps are set by the database.
+		cursor, err := syncRepo.Now(ctx)
+		if err != nil {
+			return fmt.Errorf("%w: failed to get current time: %w", ErrRepoFailed, err)
+		}
+		result.Cursor = cursor
+
+		definitionIDs := make(map[int64]map[int64]bool)
+		affected := make(map[int64]int64)
+		reviewsByStudySet := make(map[int64][]*domain.ReviewGradeData)
+		reviews := make([]*domain.ReviewGradeData, 0, len(syncData.Events))
+		latestAllowed := time.Now().Add(maxSyncClockSkew)
+
+		for _, event := range syncData.Events {
+			studySetDefinitionIDs, ok := definitionIDs[event.StudySetId]
+			if !ok {
+				studySetDefinitionIDs, err = getDefinitionIDs(ctx, ds, userID, event.StudySetId)
+				if err != nil {
+					return err
+				}
+				definitionIDs[event.StudySetId] = studySetDefinitionIDs
+			}
+
+			if !studySetDefinitionIDs[event.DefinitionId] {
+				result.Rejected = append(result.Rejected, &domain.SyncRejection{
+					EventId: event.EventId,
+					Reason:  syncRejectionDefinitionNotFound,
+				})
+				continue
+			}
+			if event.AnsweredAt.After(latestAllowed) {
+				result.Rejected = append(result.Rejected, &domain.SyncRejection{
+					EventId: event.EventId,
+					Reason:  syncRejectionFutureAnswer,
+				})
+				continue
+			}
+
+			review := &domain.ReviewGradeData{
+				DefinitionId:   event.DefinitionId,
+				Grade:          event.Grade,
+				ResponseTimeMs: event.ResponseTimeMs,
+				AnsweredAt:     event.AnsweredAt,
+			}
+
+			inserted, err := reviewRepo.InsertEvent(ctx, userID, event.StudySetId, event.EventId, review)
+			if err != nil {
+				return fmt.Errorf("%w: failed to append the event to the log: %w", ErrRepoFailed, err)
+			}
+			if !inserted {
+				result.Duplicates = append(result.Duplicates, event.EventId)
+				continue
+			}
+
+			result.Accepted = append(result.Accepted, event.EventId)
+			affected[event.DefinitionId] = event.StudySetId
+			reviews = append(reviews, review)
+			reviewsByStudySet[event.StudySetId] = append(reviewsByStudySet[event.StudySetId], review)
+		}
+
+		affectedIDs := make([]int64, 0, len(affected))
+		for definitionID := range affected {
+			affectedIDs = append(affectedIDs, definitionID)
+		}
+		sort.Slice(affectedIDs, func(i, j int) bool {
+			return affectedIDs[i] < affectedIDs[j]
+		})
+
+		for _, definitionID := range affectedIDs {
+			entries, err := reviewRepo.GetDefinitionLog(ctx, userID, definitionID)
+			if err != nil {
+				return fmt.Errorf("%w: failed to get the review log: %w", ErrRepoFailed, err)
+			}
+
+			state := replayReviewLog(definitionID, entries)
+			if err := reviewRepo.UpsertState(ctx, userID, affected[definitionID], state); err != nil {
+				return fmt.Errorf("%w: failed to save the review state: %w", ErrRepoFailed, err)
+			}
+		}
+
+		sort.SliceStable(reviews, func(i, j int) bool {
+			return reviews[i].AnsweredAt.Before(*reviews[j].AnsweredAt)
+		})
+		if err := recordStudyActivity(ctx, ds, userID, reviews); err != nil {
+			return err
+		}
+		for studySetID, studySetReviews := range reviewsByStudySet {
+			sort.SliceStable(studySetReviews, func(i, j int) bool {
+				return studySetReviews[i].AnsweredAt.Before(*studySetReviews[j].AnsweredAt)
+			})
+			if err := updateSkill(ctx, ds, userID, studySetID, studySetReviews); err != nil {
+				return err
+			}
+			if err := refreshStudySession(ctx, ds, userID, studySetID); err != nil {
+				return err
+			}
+		}
+
+		var since *time.Time
+		if syncData.Cursor != nil {
+			overlapped := syncData.Cursor.Add(-syncCursorOverlap)
+			since = &overlapped
+		}
+
+		if result.States, err = syncRepo.GetChangedStates(ctx, userID, since); err != nil {
+			return fmt.Errorf("%w: failed to get changed review states: %w", ErrRepoFailed, err)
+		}
+		if result.Definitions, err = syncRepo.GetChangedDefinitions(ctx, userID, since); err != nil {
+			return fmt.Errorf("%w: failed to get changed definitions: %w", ErrRepoFailed, err)
+		}
+
+		// Clients that have never synced do not have anything to delete.
+		result.DeletedDefinitionIds = make([]int64, 0)
+		if since != nil {
+			if result.DeletedDefinitionIds, err = syncRepo.GetDeletedDefinitions(ctx, userID, *since); err != nil {
+				return fmt.Errorf("%w: failed to get deleted definitions: %w", ErrRepoFailed, err)
+			}
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("atomic operation failed: %w", err)
+	}
+
+	return result, nil
+}
+
+// getDefinitionIDs returns ids of all definitions from the study set.
+// Study sets the user cannot see are treated as empty.
+func getDefinitionIDs(ctx context.Context, ds domain.DataStore, userID string, studySetID int64) (map[int64]bool, error) {
+	if _, err := newPermissionService(ds).Authorize(ctx, userID, studySetID, actionView); err != nil {
+		var errNotFound *ErrNotFound
+		if errors.As(err, &errNotFound) {
+			return make(map[int64]bool), nil
+		}
+		return nil, err
+	}
+
+	definitionRows, err := ds.GetDefinitionRepo().GetAllFor(ctx, studySetID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to get definitions for the study set: %w", ErrRepoFailed, err)
+	}
+
+	definitionIDs := make(map[int64]bool, len(definitionRows))
+	for _, definitionRow := range definitionRows {
+		definitionIDs[definitionRow.Id] = true
+	}
+
+	return definitionIDs, nil
+}
+
+// replayReviewLog computes the review state of the definition from scratch by replaying its review log.
+// Entries must be sorted in the order they should be applied.
+func replayReviewLog(definitionID int64, entries []*domain.ReviewLogEntry) *domain.ReviewState {
+	var state *domain.ReviewState
+	for _, entry := range entries {
+		state = scheduleReview(state, definitionID, srs.Grade(entry.Grade), entry.AnsweredAt)
+	}
+	return state
+}
@@ -0,0 +1,124 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+
+	"ailingo/internal/domain"
+	"ailingo/pkg/grader"
+)
+
+const (
+	defaultTagAutocompleteLimit = 10
+	maxTagAutocompleteLimit     = 50
+
+	minTagLength = 2
+	maxTagLength = 32
+)
+
+type tagUseCase struct {
+	dataStore domain.DataStore
+	validate  *validator.Validate
+}
+
+func NewTagUseCase(dataStore domain.DataStore, validate *validator.Validate) domain.TagUseCase {
+	return &tagUseCase{
+		dataStore: dataStore,
+		validate:  validate,
+	}
+}
+
+func (uc *tagUseCase) Autocomplete(ctx context.Context, prefix string, limit int) ([]*domain.Tag, error) {
+	if limit == 0 {
+		limit = defaultTagAutocompleteLimit
+	}
+	if limit < 0 || limit > maxTagAutocompleteLimit {
+		return nil, fmt.Errorf("%w: limit must be between 1 and %d", ErrValidation, maxTagAutocompleteLimit)
+	}
+
+	// A prefix may end in the middle of a word, so only its length is checked.
+	prefix = foldTag(prefix)
+	if len(prefix) > maxTagLength {
+		return nil, fmt.Errorf("%w: prefix must be at most %d characters long", ErrValidation, maxTagLength)
+	}
+
+	tags, err := uc.dataStore.GetTagRepo().GetByPrefix(ctx, prefix, limit)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to get tags: %w", ErrRepoFailed, err)
+	}
+
+	return tags, nil
+}
+
+func (uc *tagUseCase) GetCategories(ctx context.Context) ([]*domain.CategoryUsage, error) {
+	usage, err := uc.dataStore.GetTagRepo().GetCategoryUsage(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to get category usage: %w", ErrRepoFailed, err)
+	}
+
+	studySets := make(map[string]int64, len(usage))
+	for _, category := range usage {
+		studySets[category.Category] = category.StudySets
+	}
+
+	// Empty categories are listed too, so that the taxonomy does not depend on the content.
+	categories := make([]*domain.CategoryUsage, 0, len(domain.Categories))
+	for _, category := range domain.Categories {
+		categories = append(categories, &domain.CategoryUsage{
+			Category:  category,
+			StudySets: studySets[category],
+		})
+	}
+
+	return categories, nil
+}
+
+func (uc *tagUseCase) GetStudySets(ctx context.Context, tag string, filter *domain.StudySetFilter, cursor string, limit int) (*domain.StudySetPage, error) {
+	filter.Tag = tag
+	filter.ViewerId = ""
+	filter.IncludeUnlisted = false
+	return getStudySetPage(ctx, uc.dataStore, uc.validate, filter, cursor, limit)
+}
+
+// normalizeTag folds case and diacritics and joins words with hyphens,
+// so that "Côte d'Azur" and "cote d azur" become the same "cote-d-azur" tag.
+func normalizeTag(tag string) (string, error) {
+	normalized := foldTag(tag)
+	if len(normalized) < minTagLength || len(normalized) > maxTagLength {
+		return "", fmt.Errorf("%w: tag %q must be between %d and %d characters long", ErrValidation, tag, minTagLength, maxTagLength)
+	}
+	return normalized, nil
+}
+
+// normalizeTags normalizes all the tags and removes duplicates. The tags are sorted by name, which is the order
+// they are read in, so that snapshots of the study set do not differ only by the order of its tags.
+func normalizeTags(tags []string) ([]string, error) {
+	normalized := make([]string, 0, len(tags))
+	seen := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		normalizedTag, err := normalizeTag(tag)
+		if err != nil {
+			return nil, err
+		}
+		if seen[normalizedTag] {
+			continue
+		}
+		seen[normalizedTag] = true
+		normalized = append(normalized, normalizedTag)
+	}
+	sort.Strings(normalized)
+	return normalized, nil
+}
+
+// foldTag lowercases the text, replaces letters with diacritics with their base letters
+// and joins the remaining runs of letters and digits with hyphens.
+func foldTag(text string) string {
+	words := strings.FieldsFunc(grader.Fold(strings.ToLower(text)), func(r rune) bool {
+		return (r < 'a' || r > 'z') && (r < '0' || r > '9')
+	})
+	return strings.Join(words, "-")
+}
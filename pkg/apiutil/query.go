@@ -0,0 +1,26 @@
+package apiutil
+
+import (
+	"net/http"
+	"strconv"
+)
+
+// QueryInt parses the given query parameter as an integer.
+// If the parameter is missing the fallback value is returned.
+func QueryInt(r *http.Request, name string, fallback int) (int, error) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return fallback, nil
+	}
+	return strconv.Atoi(value)
+}
+
+// QueryBool parses the given query parameter as a boolean.
+// If the parameter is missing the fallback value is returned.
+func QueryBool(r *http.Request, name string, fallback bool) (bool, error) {
+	value := r.URL.Query().Get(name)
+	if value == "" {
+		return fallback, nil
+	}
+	return strconv.ParseBool(value)
+}
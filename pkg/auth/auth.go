@@ -45,6 +45,16 @@ func (us *UserService) GetUserFromContext(ctx context.Context) (*clerk.User, err
 	return user, nil
 }
 
+// GetUserIDFromContext returns id of the user whose claims were found in the context.
+// Empty string is returned for anonymous requests, which is meant for endpoints behind the WithOptionalClaims middleware.
+func (us *UserService) GetUserIDFromContext(ctx context.Context) string {
+	claims, ok := clerk.SessionFromContext(ctx)
+	if !ok {
+		return ""
+	}
+	return claims.Subject
+}
+
 func (us *UserService) GetUserById(userID string) (*clerk.User, error) {
 	user, err := us.client.Users().Read(userID)
 	if err != nil {
@@ -74,6 +84,22 @@ func WithClaims(logger *slog.Logger, client clerk.Client) func(http.Handler) htt
 	}
 }
 
+// WithOptionalClaims works like WithClaims, but lets requests without an auth token through as anonymous.
+// Requests with an invalid token are still rejected.
+func WithOptionalClaims(logger *slog.Logger, client clerk.Client) func(http.Handler) http.Handler {
+	withClaims := WithClaims(logger, client)
+	return func(next http.Handler) http.Handler {
+		authenticated := withClaims(next)
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if getAuthToken(r) == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			authenticated.ServeHTTP(w, r)
+		})
+	}
+}
+
 func getAuthToken(r *http.Request) string {
 	authHeader := r.Header.Get("Authorization")
 	return strings.TrimPrefix(strings.TrimSpace(authHeader), "Bearer ")
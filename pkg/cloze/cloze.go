@@ -0,0 +1,157 @@
+// Package cloze turns example sentences into fill-in-the-blank exercises.
+package cloze
+
+import (
+	"strings"
+	"unicode"
+
+	"ailingo/pkg/grader"
+)
+
+// Blank is the placeholder replacing the phrase in a sentence.
+const Blank = "_____"
+
+// maxSuffixLength is the longest inflectional ending accepted after the stem of a word.
+const maxSuffixLength = 4
+
+// leadingWords are skipped when they start a phrase, e.g. "to run" is looked up as "run".
+var leadingWords = []string{"to", "a", "an", "the"}
+
+// Match describes where the phrase was found in a sentence.
+// Start and End are byte offsets of the matched text.
+type Match struct {
+	Start int
+	End   int
+	Text  string
+}
+
+// Exercise is a sentence with the phrase blanked out.
+type Exercise struct {
+	Text   string
+	Answer string
+}
+
+type word struct {
+	start int
+	end   int
+	text  string
+}
+
+// Locate finds the phrase inside the sentence, tolerating inflected forms of its words.
+func Locate(sentence string, phrase string) (*Match, bool) {
+	stems := phraseStems(phrase)
+	if len(stems) == 0 {
+		return nil, false
+	}
+
+	words := splitWords(sentence)
+	for i := 0; i+len(stems) <= len(words); i++ {
+		matched := true
+		for j, stem := range stems {
+			if !matchesStem(words[i+j].text, stem) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			start := words[i].start
+			end := words[i+len(stems)-1].end
+			return &Match{
+				Start: start,
+				End:   end,
+				Text:  sentence[start:end],
+			}, true
+		}
+	}
+
+	return nil, false
+}
+
+// Make blanks out the phrase in the sentence.
+func Make(sentence string, phrase string) (*Exercise, bool) {
+	match, ok := Locate(sentence, phrase)
+	if !ok {
+		return nil, false
+	}
+
+	return &Exercise{
+		Text:   sentence[:match.Start] + Blank + sentence[match.End:],
+		Answer: match.Text,
+	}, true
+}
+
+// phraseStems splits the phrase into words and reduces them to stems.
+func phraseStems(phrase string) []string {
+	words := splitWords(phrase)
+	for len(words) > 1 && isLeadingWord(words[0].text) {
+		words = words[1:]
+	}
+
+	stems := make([]string, 0, len(words))
+	for _, w := range words {
+		stems = append(stems, stem(w.text))
+	}
+
+	return stems
+}
+
+// stem drops the likely inflectional ending of the word. Short words are kept intact.
+func stem(w string) string {
+	runes := []rune(normalize(w))
+	switch {
+	case len(runes) <= 3:
+		return string(runes)
+	case len(runes) <= 5:
+		return string(runes[:len(runes)-1])
+	default:
+		return string(runes[:len(runes)-2])
+	}
+}
+
+func matchesStem(w string, stem string) bool {
+	normalized := normalize(w)
+	// Very short words such as particles are not inflected.
+	if len([]rune(stem)) <= 2 {
+		return normalized == stem
+	}
+	if !strings.HasPrefix(normalized, stem) {
+		return false
+	}
+	return len([]rune(normalized))-len([]rune(stem)) <= maxSuffixLength
+}
+
+func normalize(w string) string {
+	return grader.Fold(strings.ToLower(w))
+}
+
+// splitWords splits text into words, keeping their byte offsets.
+// Apostrophes and hyphens inside words are treated as part of the word.
+func splitWords(text string) []word {
+	words := make([]word, 0)
+	start := -1
+
+	for i, r := range text {
+		inWord := unicode.IsLetter(r) || unicode.IsDigit(r) || ((r == '\'' || r == '-') && start != -1)
+		if inWord && start == -1 {
+			start = i
+		} else if !inWord && start != -1 {
+			words = append(words, word{start: start, end: i, text: text[start:i]})
+			start = -1
+		}
+	}
+	if start != -1 {
+		words = append(words, word{start: start, end: len(text), text: text[start:]})
+	}
+
+	return words
+}
+
+func isLeadingWord(w string) bool {
+	w = strings.ToLower(w)
+	for _, leading := range leadingWords {
+		if w == leading {
+			return true
+		}
+	}
+	return false
+}
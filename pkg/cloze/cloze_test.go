@@ -0,0 +1,57 @@
+package cloze
+
+import "testing"
+
+func TestMake(t *testing.T) {
+	tests := []struct {
+		name     string
+		sentence string
+		phrase   string
+		text     string
+		answer   string
+		ok       bool
+	}{
+		{"exact word", "I like apples.", "apples", "I like _____.", "apples", true},
+		{"inflected word", "She runs every day.", "run", "She _____ every day.", "runs", true},
+		{"leading article", "He bought a new car.", "the car", "He bought a new _____.", "car", true},
+		{"leading to", "They decided to travel abroad.", "to travel", "They decided to _____ abroad.", "travel", true},
+		{"multi word phrase", "Please give up smoking.", "give up", "Please _____ smoking.", "give up", true},
+		{"case insensitive", "Books are great.", "book", "_____ are great.", "Books", true},
+		{"diacritics", "Kupiłem nowy samochód.", "samochod", "Kupiłem nowy _____.", "samochód", true},
+		{"polish inflection", "Nie mam samochodu.", "samochód", "Nie mam _____.", "samochodu", true},
+		{"hyphenated word", "It was a well-known fact.", "well-known", "It was a _____ fact.", "well-known", true},
+		{"too long suffix", "It was unbelievableness.", "unbelievable", "", "", false},
+		{"short words are not inflected", "He is in.", "it", "", "", false},
+		{"missing phrase", "I like apples.", "banana", "", "", false},
+		{"empty phrase", "I like apples.", "", "", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exercise, ok := Make(tt.sentence, tt.phrase)
+			if ok != tt.ok {
+				t.Fatalf("Make(%q, %q) ok = %v, want %v", tt.sentence, tt.phrase, ok, tt.ok)
+			}
+			if !ok {
+				return
+			}
+			if exercise.Text != tt.text {
+				t.Errorf("Make(%q, %q).Text = %q, want %q", tt.sentence, tt.phrase, exercise.Text, tt.text)
+			}
+			if exercise.Answer != tt.answer {
+				t.Errorf("Make(%q, %q).Answer = %q, want %q", tt.sentence, tt.phrase, exercise.Answer, tt.answer)
+			}
+		})
+	}
+}
+
+func TestLocateOffsets(t *testing.T) {
+	sentence := "Zażółć gęślą jaźń."
+	match, ok := Locate(sentence, "gesla")
+	if !ok {
+		t.Fatalf("Locate(%q) did not find the phrase", sentence)
+	}
+	if got := sentence[match.Start:match.End]; got != "gęślą" || match.Text != got {
+		t.Errorf("Locate(%q) matched %q with text %q, want %q", sentence, got, match.Text, "gęślą")
+	}
+}
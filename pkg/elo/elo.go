@@ -0,0 +1,56 @@
+// Package elo estimates learner ability and item difficulty from answer outcomes.
+// It uses the Elo rating system adapted for education, which approximates the Rasch (1PL IRT) model.
+package elo
+
+import "math"
+
+const (
+	// TargetProbability is the success probability at which practice is considered the most effective.
+	TargetProbability = 0.75
+
+	// Step size parameters. Estimates move quickly while there are few answers and settle over time.
+	stepAlpha = 1.0
+	stepBeta  = 0.05
+)
+
+// Estimate represents a single ability or difficulty estimate on the logit scale,
+// where 0 means an average learner or item.
+type Estimate struct {
+	Value   float64
+	Answers int
+}
+
+// Probability returns the chance of answering an item of the given difficulty correctly.
+func Probability(ability float64, difficulty float64) float64 {
+	return 1 / (1 + math.Exp(difficulty-ability))
+}
+
+// Distance tells how far the item is from the target difficulty for the learner, lower is better.
+func Distance(ability float64, difficulty float64) float64 {
+	return math.Abs(Probability(ability, difficulty) - TargetProbability)
+}
+
+// Update returns ability and difficulty estimates after the learner answered the item.
+func Update(ability Estimate, difficulty Estimate, correct bool) (Estimate, Estimate) {
+	outcome := 0.0
+	if correct {
+		outcome = 1
+	}
+	surprise := outcome - Probability(ability.Value, difficulty.Value)
+
+	nextAbility := Estimate{
+		Value:   ability.Value + step(ability.Answers)*surprise,
+		Answers: ability.Answers + 1,
+	}
+	nextDifficulty := Estimate{
+		Value:   difficulty.Value - step(difficulty.Answers)*surprise,
+		Answers: difficulty.Answers + 1,
+	}
+
+	return nextAbility, nextDifficulty
+}
+
+// step returns the step size for an estimate based on the given number of answers.
+func step(answers int) float64 {
+	return stepAlpha / (1 + stepBeta*float64(answers))
+}
@@ -0,0 +1,75 @@
+package elo
+
+import (
+	"math"
+	"testing"
+)
+
+func TestProbability(t *testing.T) {
+	tests := []struct {
+		ability    float64
+		difficulty float64
+		want       float64
+	}{
+		{0, 0, 0.5},
+		{1, 1, 0.5},
+		{math.Log(3), 0, 0.75},
+		{0, math.Log(3), 0.25},
+	}
+
+	for _, tt := range tests {
+		if got := Probability(tt.ability, tt.difficulty); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("Probability(%v, %v) = %v, want %v", tt.ability, tt.difficulty, got, tt.want)
+		}
+	}
+}
+
+func TestDistance(t *testing.T) {
+	if got := Distance(math.Log(3), 0); math.Abs(got) > 1e-9 {
+		t.Errorf("Distance at the target probability = %v, want 0", got)
+	}
+	if Distance(0, 0) <= Distance(1, 0) {
+		t.Errorf("an item closer to the target probability should have a lower distance")
+	}
+	if Distance(5, 0) <= Distance(1, 0) {
+		t.Errorf("an item that is too easy should have a higher distance")
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	tests := []struct {
+		name           string
+		ability        Estimate
+		difficulty     Estimate
+		correct        bool
+		wantAbility    float64
+		wantDifficulty float64
+	}{
+		{"correct answer at even odds", Estimate{}, Estimate{}, true, 0.5, -0.5},
+		{"wrong answer at even odds", Estimate{}, Estimate{}, false, -0.5, 0.5},
+		{"steps shrink with answers", Estimate{Answers: 20}, Estimate{Answers: 180}, true, 0.25, -0.05},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ability, difficulty := Update(tt.ability, tt.difficulty, tt.correct)
+			if math.Abs(ability.Value-tt.wantAbility) > 1e-9 {
+				t.Errorf("ability = %v, want %v", ability.Value, tt.wantAbility)
+			}
+			if math.Abs(difficulty.Value-tt.wantDifficulty) > 1e-9 {
+				t.Errorf("difficulty = %v, want %v", difficulty.Value, tt.wantDifficulty)
+			}
+			if ability.Answers != tt.ability.Answers+1 || difficulty.Answers != tt.difficulty.Answers+1 {
+				t.Errorf("answers = %d and %d, want %d and %d", ability.Answers, difficulty.Answers, tt.ability.Answers+1, tt.difficulty.Answers+1)
+			}
+		})
+	}
+}
+
+func TestUpdateExpectedOutcomeMovesLittle(t *testing.T) {
+	// A strong learner answering an easy item correctly is not a surprise.
+	ability, difficulty := Update(Estimate{Value: 3}, Estimate{Value: -3}, true)
+	if ability.Value-3 > 0.01 || -3-difficulty.Value > 0.01 {
+		t.Errorf("estimates moved too much: ability %v, difficulty %v", ability.Value, difficulty.Value)
+	}
+}
@@ -0,0 +1,244 @@
+// Package grader compares typed answers with the expected ones.
+// It tolerates differences in case, punctuation, leading articles, diacritics and small typos.
+package grader
+
+import (
+	"strings"
+	"unicode"
+)
+
+type Verdict string
+
+const (
+	// VerdictExact means that the answer matches once case, punctuation and articles are ignored.
+	VerdictExact Verdict = "EXACT"
+	// VerdictClose means that the answer is accepted but contains missing diacritics or small typos.
+	VerdictClose Verdict = "CLOSE"
+	// VerdictWrong means that the answer is not accepted.
+	VerdictWrong Verdict = "WRONG"
+)
+
+type Op string
+
+const (
+	OpEqual  Op = "EQUAL"
+	OpInsert Op = "INSERT"
+	OpDelete Op = "DELETE"
+)
+
+// DiffPart is a fragment of a character level diff.
+// Insert means that the fragment is missing in the answer, delete means that it should not be there.
+type DiffPart struct {
+	Op   Op     `json:"op"`
+	Text string `json:"text"`
+}
+
+// Result represents the outcome of grading a single answer.
+type Result struct {
+	Verdict  Verdict    `json:"verdict"`
+	Expected string     `json:"expected"`
+	Distance int        `json:"distance"`
+	Diff     []DiffPart `json:"diff"`
+}
+
+// articles are skipped when they start an answer.
+var articles = []string{"to", "a", "an", "the"}
+
+// foldedRunes maps letters with diacritics onto their base letters.
+// Letters like ł do not decompose under Unicode normalization, so the mapping is explicit.
+var foldedRunes = map[rune]rune{
+	'ą': 'a', 'ć': 'c', 'ę': 'e', 'ł': 'l', 'ń': 'n', 'ó': 'o', 'ś': 's', 'ź': 'z', 'ż': 'z',
+	'á': 'a', 'à': 'a', 'â': 'a', 'ä': 'a', 'ã': 'a', 'å': 'a',
+	'é': 'e', 'è': 'e', 'ê': 'e', 'ë': 'e',
+	'í': 'i', 'ì': 'i', 'î': 'i', 'ï': 'i',
+	'ò': 'o', 'ô': 'o', 'ö': 'o', 'õ': 'o',
+	'ú': 'u', 'ù': 'u', 'û': 'u', 'ü': 'u',
+	'ç': 'c', 'ñ': 'n', 'ý': 'y', 'ÿ': 'y',
+}
+
+// Grade compares the answer with the expected text.
+// The expected text may list alternatives separated with commas, semicolons or slashes, the best match wins.
+func Grade(expected string, answer string) *Result {
+	var best *Result
+
+	for _, alternative := range alternatives(expected) {
+		result := gradeAlternative(alternative, answer)
+		if best == nil || betterThan(result, best) {
+			best = result
+		}
+	}
+
+	return best
+}
+
+func gradeAlternative(expected string, answer string) *Result {
+	normalizedExpected := Normalize(expected)
+	normalizedAnswer := Normalize(answer)
+
+	result := &Result{
+		Expected: expected,
+		Diff:     Diff(strings.TrimSpace(expected), strings.TrimSpace(answer)),
+	}
+
+	if normalizedExpected == normalizedAnswer {
+		result.Verdict = VerdictExact
+		return result
+	}
+
+	foldedExpected := Fold(normalizedExpected)
+	result.Distance = Distance(foldedExpected, Fold(normalizedAnswer))
+
+	if result.Distance <= allowedTypos(foldedExpected) {
+		result.Verdict = VerdictClose
+	} else {
+		result.Verdict = VerdictWrong
+	}
+
+	return result
+}
+
+// Normalize lowercases the text, removes punctuation, collapses whitespace and strips leading articles.
+func Normalize(text string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(text) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+		} else {
+			b.WriteRune(' ')
+		}
+	}
+
+	words := strings.Fields(b.String())
+	for len(words) > 1 && isArticle(words[0]) {
+		words = words[1:]
+	}
+
+	return strings.Join(words, " ")
+}
+
+// Fold replaces letters with diacritics with their base letters.
+func Fold(text string) string {
+	return strings.Map(func(r rune) rune {
+		if folded, ok := foldedRunes[r]; ok {
+			return folded
+		}
+		return r
+	}, text)
+}
+
+// Distance computes the Levenshtein distance between two strings.
+func Distance(a string, b string) int {
+	return distanceMatrix([]rune(a), []rune(b))[len([]rune(a))][len([]rune(b))]
+}
+
+// Diff computes a character level diff turning the answer into the expected text.
+func Diff(expected string, answer string) []DiffPart {
+	e := []rune(expected)
+	a := []rune(answer)
+	d := distanceMatrix(a, e)
+
+	// Walk back from the end of both strings, preferring matches.
+	reversed := make([]DiffPart, 0)
+	i, j := len(a), len(e)
+	for i > 0 || j > 0 {
+		switch {
+		case i > 0 && j > 0 && a[i-1] == e[j-1] && d[i][j] == d[i-1][j-1]:
+			reversed = append(reversed, DiffPart{Op: OpEqual, Text: string(e[j-1])})
+			i--
+			j--
+		case j > 0 && (i == 0 || d[i][j] == d[i][j-1]+1):
+			reversed = append(reversed, DiffPart{Op: OpInsert, Text: string(e[j-1])})
+			j--
+		case i > 0 && d[i][j] == d[i-1][j]+1:
+			reversed = append(reversed, DiffPart{Op: OpDelete, Text: string(a[i-1])})
+			i--
+		default:
+			// Substitution
+			reversed = append(reversed, DiffPart{Op: OpInsert, Text: string(e[j-1])})
+			reversed = append(reversed, DiffPart{Op: OpDelete, Text: string(a[i-1])})
+			i--
+			j--
+		}
+	}
+
+	diff := make([]DiffPart, 0, len(reversed))
+	for k := len(reversed) - 1; k >= 0; k-- {
+		part := reversed[k]
+		if n := len(diff); n > 0 && diff[n-1].Op == part.Op {
+			diff[n-1].Text += part.Text
+			continue
+		}
+		diff = append(diff, part)
+	}
+
+	return diff
+}
+
+// distanceMatrix computes the Levenshtein dynamic programming matrix for a and b.
+func distanceMatrix(a []rune, b []rune) [][]int {
+	d := make([][]int, len(a)+1)
+	for i := range d {
+		d[i] = make([]int, len(b)+1)
+		d[i][0] = i
+	}
+	for j := range d[0] {
+		d[0][j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			d[i][j] = min(d[i-1][j]+1, d[i][j-1]+1, d[i-1][j-1]+cost)
+		}
+	}
+
+	return d
+}
+
+// allowedTypos returns the number of typos tolerated in an answer to the expected text.
+// Very short words must be spelled correctly apart from diacritics.
+func allowedTypos(expected string) int {
+	length := len([]rune(expected))
+	if length <= 3 {
+		return 0
+	}
+	return max(1, length/5)
+}
+
+func alternatives(expected string) []string {
+	parts := strings.FieldsFunc(expected, func(r rune) bool {
+		return r == ',' || r == ';' || r == '/'
+	})
+
+	alternatives := make([]string, 0, len(parts))
+	for _, part := range parts {
+		if part = strings.TrimSpace(part); part != "" {
+			alternatives = append(alternatives, part)
+		}
+	}
+	if len(alternatives) == 0 {
+		alternatives = append(alternatives, expected)
+	}
+
+	return alternatives
+}
+
+func betterThan(a *Result, b *Result) bool {
+	rank := map[Verdict]int{VerdictExact: 0, VerdictClose: 1, VerdictWrong: 2}
+	if rank[a.Verdict] != rank[b.Verdict] {
+		return rank[a.Verdict] < rank[b.Verdict]
+	}
+	return a.Distance < b.Distance
+}
+
+func isArticle(word string) bool {
+	for _, article := range articles {
+		if word == article {
+			return true
+		}
+	}
+	return false
+}
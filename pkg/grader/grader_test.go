@@ -0,0 +1,129 @@
+package grader
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestGrade(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected string
+		answer   string
+		verdict  Verdict
+		distance int
+	}{
+		{"identical", "apple", "apple", VerdictExact, 0},
+		{"case and punctuation", "Hello world!", "hello-world", VerdictExact, 0},
+		{"leading article", "to run", "run", VerdictExact, 0},
+		{"only article is kept", "the", "the", VerdictExact, 0},
+		{"extra whitespace", "ice cream", "  ice   cream ", VerdictExact, 0},
+		{"missing diacritics", "żółw", "zolw", VerdictClose, 0},
+		{"polish l", "łódź", "lodz", VerdictClose, 0},
+		{"small typo", "elephant", "elephnt", VerdictClose, 1},
+		{"short word typo", "cat", "cot", VerdictWrong, 1},
+		{"too many typos", "elephant", "elefant", VerdictWrong, 2},
+		{"typo and diacritics", "pszczoła", "pszczla", VerdictClose, 1},
+		{"different word", "elephant", "giraffe", VerdictWrong, 8},
+		{"second alternative", "car, automobile", "automobile", VerdictExact, 0},
+		{"slash alternatives", "big/large", "large", VerdictExact, 0},
+		{"empty answer", "dog", "", VerdictWrong, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := Grade(tt.expected, tt.answer)
+			if result.Verdict != tt.verdict {
+				t.Errorf("Grade(%q, %q).Verdict = %s, want %s", tt.expected, tt.answer, result.Verdict, tt.verdict)
+			}
+			if result.Distance != tt.distance {
+				t.Errorf("Grade(%q, %q).Distance = %d, want %d", tt.expected, tt.answer, result.Distance, tt.distance)
+			}
+		})
+	}
+}
+
+func TestGradeReportsMatchedAlternative(t *testing.T) {
+	result := Grade("car; automobile", "automobil")
+	if result.Expected != "automobile" {
+		t.Errorf("Expected = %q, want %q", result.Expected, "automobile")
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"The Cat", "cat"},
+		{"a an the dog", "dog"},
+		{"to", "to"},
+		{"well-known", "well known"},
+		{"  Ćma?! ", "ćma"},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		if got := Normalize(tt.text); got != tt.want {
+			t.Errorf("Normalize(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestFold(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"zażółć gęślą jaźń", "zazolc gesla jazn"},
+		{"crème brûlée", "creme brulee"},
+		{"niño", "nino"},
+		{"plain", "plain"},
+	}
+
+	for _, tt := range tests {
+		if got := Fold(tt.text); got != tt.want {
+			t.Errorf("Fold(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestDistance(t *testing.T) {
+	tests := []struct {
+		a    string
+		b    string
+		want int
+	}{
+		{"", "", 0},
+		{"abc", "", 3},
+		{"", "abc", 3},
+		{"kitten", "sitting", 3},
+		{"flaw", "lawn", 2},
+		{"żółw", "zolw", 3},
+	}
+
+	for _, tt := range tests {
+		if got := Distance(tt.a, tt.b); got != tt.want {
+			t.Errorf("Distance(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		expected string
+		answer   string
+		want     []DiffPart
+	}{
+		{"cat", "cat", []DiffPart{{OpEqual, "cat"}}},
+		{"cats", "cat", []DiffPart{{OpEqual, "cat"}, {OpInsert, "s"}}},
+		{"cat", "cart", []DiffPart{{OpEqual, "ca"}, {OpDelete, "r"}, {OpEqual, "t"}}},
+		{"", "", []DiffPart{}},
+	}
+
+	for _, tt := range tests {
+		if got := Diff(tt.expected, tt.answer); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Diff(%q, %q) = %v, want %v", tt.expected, tt.answer, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,145 @@
+// Package highlight marks words of a search query in the matched text.
+// Words are compared the way MySQL full-text search compares them, ignoring case and diacritics.
+package highlight
+
+import (
+	"strings"
+	"unicode"
+
+	"ailingo/pkg/grader"
+)
+
+// Ellipsis is added to snippets cut out of a longer text.
+const Ellipsis = "…"
+
+// Part is a fragment of the highlighted text. Match means that the fragment is a word from the query.
+type Part struct {
+	Text  string `json:"text"`
+	Match bool   `json:"match,omitempty"`
+}
+
+// Terms splits the query into distinct normalized words.
+func Terms(query string) []string {
+	terms := make([]string, 0)
+	seen := make(map[string]bool)
+
+	for _, word := range strings.FieldsFunc(query, isSeparator) {
+		term := normalize(word)
+		if !seen[term] {
+			seen[term] = true
+			terms = append(terms, term)
+		}
+	}
+
+	return terms
+}
+
+// Highlight splits the whole text into parts, marking words that are equal to one of the terms.
+func Highlight(text string, terms []string) []Part {
+	return highlight([]rune(text), terms)
+}
+
+// Snippet cuts a fragment of at most maxRunes runes out of the text and highlights it.
+// The fragment starts a little before the first match, so that the match is shown with some context.
+func Snippet(text string, terms []string, maxRunes int) []Part {
+	runes := []rune(text)
+	if len(runes) <= maxRunes {
+		return highlight(runes, terms)
+	}
+
+	start := 0
+	if first := firstMatch(runes, terms); first > maxRunes/4 {
+		start = min(first-maxRunes/4, len(runes)-maxRunes)
+		// Snippets should not start in the middle of a word.
+		for start < first && !isSeparator(runes[start]) {
+			start++
+		}
+		for start < first && isSeparator(runes[start]) {
+			start++
+		}
+	}
+	end := min(start+maxRunes, len(runes))
+
+	parts := highlight(runes[start:end], terms)
+	if start > 0 {
+		parts = append([]Part{{Text: Ellipsis}}, parts...)
+	}
+	if end < len(runes) {
+		parts = append(parts, Part{Text: Ellipsis})
+	}
+
+	return parts
+}
+
+func highlight(runes []rune, terms []string) []Part {
+	parts := make([]Part, 0)
+	plain := 0
+
+	forEachWord(runes, func(start int, end int) bool {
+		if !matches(runes[start:end], terms) {
+			return true
+		}
+		if start > plain {
+			parts = append(parts, Part{Text: string(runes[plain:start])})
+		}
+		parts = append(parts, Part{Text: string(runes[start:end]), Match: true})
+		plain = end
+		return true
+	})
+
+	if plain < len(runes) {
+		parts = append(parts, Part{Text: string(runes[plain:])})
+	}
+
+	return parts
+}
+
+// firstMatch returns the offset of the first matching word or 0 if there is none.
+func firstMatch(runes []rune, terms []string) int {
+	first := 0
+	forEachWord(runes, func(start int, end int) bool {
+		if matches(runes[start:end], terms) {
+			first = start
+			return false
+		}
+		return true
+	})
+	return first
+}
+
+// forEachWord calls fn with bounds of consecutive words until it returns false.
+func forEachWord(runes []rune, fn func(start int, end int) bool) {
+	start := -1
+	for i := 0; i <= len(runes); i++ {
+		if i < len(runes) && !isSeparator(runes[i]) {
+			if start < 0 {
+				start = i
+			}
+			continue
+		}
+		if start >= 0 {
+			if !fn(start, i) {
+				return
+			}
+			start = -1
+		}
+	}
+}
+
+func matches(word []rune, terms []string) bool {
+	normalized := normalize(string(word))
+	for _, term := range terms {
+		if normalized == term {
+			return true
+		}
+	}
+	return false
+}
+
+func normalize(word string) string {
+	return grader.Fold(strings.ToLower(word))
+}
+
+func isSeparator(r rune) bool {
+	return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+}
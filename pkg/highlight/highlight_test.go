@@ -0,0 +1,129 @@
+package highlight
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTerms(t *testing.T) {
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"", []string{}},
+		{"Dog", []string{"dog"}},
+		{"dog, cat; dog", []string{"dog", "cat"}},
+		{"Zażółć  GĘŚLĄ", []string{"zazolc", "gesla"}},
+		{"well-known", []string{"well", "known"}},
+	}
+
+	for _, tt := range tests {
+		if got := Terms(tt.query); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Terms(%q) = %q, want %q", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestHighlight(t *testing.T) {
+	tests := []struct {
+		name  string
+		text  string
+		query string
+		want  []Part
+	}{
+		{
+			name:  "no match",
+			text:  "I like apples.",
+			query: "banana",
+			want:  []Part{{Text: "I like apples."}},
+		},
+		{
+			name:  "match in the middle",
+			text:  "I like apples.",
+			query: "like",
+			want:  []Part{{Text: "I "}, {Text: "like", Match: true}, {Text: " apples."}},
+		},
+		{
+			name:  "matches at both ends",
+			text:  "Apples and apples",
+			query: "apples",
+			want:  []Part{{Text: "Apples", Match: true}, {Text: " and "}, {Text: "apples", Match: true}},
+		},
+		{
+			name:  "diacritics",
+			text:  "Zażółć gęślą jaźń",
+			query: "gesla",
+			want:  []Part{{Text: "Zażółć "}, {Text: "gęślą", Match: true}, {Text: " jaźń"}},
+		},
+		{
+			name:  "only whole words",
+			text:  "pineapple apple",
+			query: "apple",
+			want:  []Part{{Text: "pineapple "}, {Text: "apple", Match: true}},
+		},
+		{
+			name:  "empty text",
+			text:  "",
+			query: "apple",
+			want:  []Part{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Highlight(tt.text, Terms(tt.query)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Highlight(%q, %q) = %v, want %v", tt.text, tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSnippet(t *testing.T) {
+	text := "one two three four five six seven eight nine ten"
+
+	tests := []struct {
+		name     string
+		query    string
+		maxRunes int
+		want     []Part
+	}{
+		{
+			name:     "short text is not cut",
+			query:    "three",
+			maxRunes: 100,
+			want:     []Part{{Text: "one two "}, {Text: "three", Match: true}, {Text: " four five six seven eight nine ten"}},
+		},
+		{
+			name:     "no match starts at the beginning",
+			query:    "eleven",
+			maxRunes: 12,
+			want:     []Part{{Text: "one two thre"}, {Text: Ellipsis}},
+		},
+		{
+			name:     "match near the beginning",
+			query:    "two",
+			maxRunes: 16,
+			want:     []Part{{Text: "one "}, {Text: "two", Match: true}, {Text: " three fo"}, {Text: Ellipsis}},
+		},
+		{
+			name:     "late match keeps whole words before it",
+			query:    "eight",
+			maxRunes: 40,
+			want:     []Part{{Text: Ellipsis}, {Text: "four five six seven "}, {Text: "eight", Match: true}, {Text: " nine ten"}},
+		},
+		{
+			name:     "snippet does not start in the middle of a word",
+			query:    "five",
+			maxRunes: 12,
+			want:     []Part{{Text: Ellipsis}, {Text: "five", Match: true}, {Text: " six sev"}, {Text: Ellipsis}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Snippet(text, Terms(tt.query), tt.maxRunes); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Snippet(%q, %d) = %v, want %v", tt.query, tt.maxRunes, got, tt.want)
+			}
+		})
+	}
+}
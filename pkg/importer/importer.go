@@ -0,0 +1,215 @@
+// Package importer splits text exported from other flashcard tools into rows of terms.
+// It understands CSV, tab separated lines and custom term and row separators, and can detect the format on its own.
+package importer
+
+import (
+	"encoding/csv"
+	"errors"
+	"io"
+	"strings"
+)
+
+type Format string
+
+const (
+	// FormatAuto means that the format is detected from the text.
+	FormatAuto Format = "AUTO"
+	// FormatCSV means comma separated values with optional quoting, as exported by spreadsheets.
+	FormatCSV Format = "CSV"
+	// FormatTSV means one row per line with terms separated by tabs, as copied from most flashcard tools.
+	FormatTSV Format = "TSV"
+	// FormatCustom means plain text split with the given term and row separators.
+	FormatCustom Format = "CUSTOM"
+)
+
+var (
+	ErrUnknownFormat       = errors.New("unknown format")
+	ErrNoTermSeparator     = errors.New("term separator is required for custom format")
+	ErrInvalidCSVSeparator = errors.New("term separator of CSV must be a single character")
+)
+
+// detectedSeparators are term separators tried during detection, the most specific first.
+var detectedSeparators = []string{"\t", ",", ";", " - "}
+
+// detectedRows is the number of non-empty rows looked at during detection.
+const detectedRows = 20
+
+type Options struct {
+	Format Format
+	// TermSeparator separates terms of a row. It is used by CSV and custom format and overrides detection.
+	TermSeparator string
+	// RowSeparator separates rows. It is used by custom format and defaults to a new line.
+	RowSeparator string
+}
+
+// Row represents terms of a single non-empty row with surrounding whitespace trimmed.
+// Number is the position of the row in the text starting at 1, empty rows included.
+type Row struct {
+	Number int      `json:"number"`
+	Terms  []string `json:"terms"`
+}
+
+// Table represents the parsed text together with the format that was used.
+type Table struct {
+	Format        Format `json:"format"`
+	TermSeparator string `json:"termSeparator"`
+	RowSeparator  string `json:"rowSeparator"`
+	Rows          []*Row `json:"rows"`
+}
+
+// Parse splits the text into rows of terms. Empty rows are left out.
+func Parse(text string, options Options) (*Table, error) {
+	text = strings.ReplaceAll(strings.TrimPrefix(text, "\ufeff"), "\r\n", "\n")
+
+	table := &Table{
+		Format:        options.Format,
+		TermSeparator: options.TermSeparator,
+		RowSeparator:  options.RowSeparator,
+	}
+	if table.Format == "" || table.Format == FormatAuto {
+		table.Format, table.TermSeparator = detect(text, options)
+	}
+	if table.RowSeparator == "" || table.Format != FormatCustom {
+		table.RowSeparator = "\n"
+	}
+
+	switch table.Format {
+	case FormatCSV:
+		if table.TermSeparator == "" {
+			table.TermSeparator = ","
+		}
+		if len([]rune(table.TermSeparator)) != 1 {
+			return nil, ErrInvalidCSVSeparator
+		}
+		if err := table.parseCSV(text); err != nil {
+			return nil, err
+		}
+		return table, nil
+	case FormatTSV:
+		table.TermSeparator = "\t"
+	case FormatCustom:
+		if table.TermSeparator == "" {
+			return nil, ErrNoTermSeparator
+		}
+	default:
+		return nil, ErrUnknownFormat
+	}
+
+	for i, line := range strings.Split(text, table.RowSeparator) {
+		table.add(i+1, strings.Split(line, table.TermSeparator))
+	}
+	return table, nil
+}
+
+// parseCSV reads the text with quoting rules of CSV, where quoted terms may span multiple lines.
+func (t *Table) parseCSV(text string) error {
+	reader := csv.NewReader(strings.NewReader(text))
+	reader.Comma = []rune(t.TermSeparator)[0]
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		line, _ := reader.FieldPos(0)
+		t.add(line, record)
+	}
+}
+
+func (t *Table) add(number int, terms []string) {
+	empty := true
+	for i := range terms {
+		terms[i] = strings.TrimSpace(terms[i])
+		if terms[i] != "" {
+			empty = false
+		}
+	}
+	if empty {
+		return
+	}
+
+	t.Rows = append(t.Rows, &Row{
+		Number: number,
+		Terms:  terms,
+	})
+}
+
+// detect picks the first separator that splits all the first rows. Commas and semicolons are read as CSV,
+// so that quoted terms can contain them. Given separators or a custom row separator always mean custom format.
+func detect(text string, options Options) (Format, string) {
+	if options.TermSeparator != "" {
+		return FormatCustom, options.TermSeparator
+	}
+	rowSeparator := options.RowSeparator
+	if rowSeparator == "" {
+		rowSeparator = "\n"
+	}
+	custom := rowSeparator != "\n"
+
+	rows := make([]string, 0, detectedRows)
+	for _, row := range strings.Split(text, rowSeparator) {
+		if strings.TrimSpace(row) == "" {
+			continue
+		}
+		rows = append(rows, row)
+		if len(rows) == detectedRows {
+			break
+		}
+	}
+
+	for _, separator := range detectedSeparators {
+		if !custom && (separator == "," || separator == ";") {
+			if splitsCSV(text, separator) {
+				return FormatCSV, separator
+			}
+			continue
+		}
+		if !splitsAll(rows, separator) {
+			continue
+		}
+
+		if !custom && separator == "\t" {
+			return FormatTSV, separator
+		}
+		return FormatCustom, separator
+	}
+
+	if custom {
+		return FormatCustom, ""
+	}
+	return FormatTSV, "\t"
+}
+
+// splitsAll tells if the separator occurs in all the rows.
+func splitsAll(rows []string, separator string) bool {
+	for _, row := range rows {
+		if !strings.Contains(row, separator) {
+			return false
+		}
+	}
+	return len(rows) > 0
+}
+
+// splitsCSV tells if the first records of the text read as CSV with the separator have at least two terms.
+func splitsCSV(text string, separator string) bool {
+	reader := csv.NewReader(strings.NewReader(text))
+	reader.Comma = []rune(separator)[0]
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+
+	for i := 0; i < detectedRows; i++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return i > 0
+		}
+		if err != nil || len(record) < 2 {
+			return false
+		}
+	}
+	return true
+}
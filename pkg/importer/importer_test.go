@@ -0,0 +1,146 @@
+package importer
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name          string
+		text          string
+		options       Options
+		format        Format
+		termSeparator string
+		rows          []*Row
+	}{
+		{
+			name:          "detected tabs",
+			text:          "dog\tpies\ncat\tkot\n",
+			format:        FormatTSV,
+			termSeparator: "\t",
+			rows:          []*Row{{1, []string{"dog", "pies"}}, {2, []string{"cat", "kot"}}},
+		},
+		{
+			name:          "detected commas with quotes",
+			text:          "dog,pies\n\"hello, world\",\"witaj, świecie\"\n",
+			format:        FormatCSV,
+			termSeparator: ",",
+			rows:          []*Row{{1, []string{"dog", "pies"}}, {2, []string{"hello, world", "witaj, świecie"}}},
+		},
+		{
+			name:          "detected multi-line quoted term",
+			text:          "dog,\"pies\nkundel\"\ncat,kot",
+			format:        FormatCSV,
+			termSeparator: ",",
+			rows:          []*Row{{1, []string{"dog", "pies\nkundel"}}, {3, []string{"cat", "kot"}}},
+		},
+		{
+			name:          "detected semicolons",
+			text:          "dog;pies\ncat;kot",
+			format:        FormatCSV,
+			termSeparator: ";",
+			rows:          []*Row{{1, []string{"dog", "pies"}}, {2, []string{"cat", "kot"}}},
+		},
+		{
+			name:          "detected dashes",
+			text:          "dog - pies\ncat - kot",
+			format:        FormatCustom,
+			termSeparator: " - ",
+			rows:          []*Row{{1, []string{"dog", "pies"}}, {2, []string{"cat", "kot"}}},
+		},
+		{
+			name:          "no separator falls back to tabs",
+			text:          "dog\ncat",
+			format:        FormatTSV,
+			termSeparator: "\t",
+			rows:          []*Row{{1, []string{"dog"}}, {2, []string{"cat"}}},
+		},
+		{
+			name:          "empty rows and whitespace are skipped",
+			text:          "\ufeffdog\t pies \r\n\r\n\t\ncat\tkot",
+			format:        FormatTSV,
+			termSeparator: "\t",
+			rows:          []*Row{{1, []string{"dog", "pies"}}, {4, []string{"cat", "kot"}}},
+		},
+		{
+			name:          "custom separators",
+			text:          "dog=pies|cat=kot|",
+			options:       Options{TermSeparator: "=", RowSeparator: "|"},
+			format:        FormatCustom,
+			termSeparator: "=",
+			rows:          []*Row{{1, []string{"dog", "pies"}}, {2, []string{"cat", "kot"}}},
+		},
+		{
+			name:          "custom row separator with detected term separator",
+			text:          "dog\tpies;;cat\tkot",
+			options:       Options{RowSeparator: ";;"},
+			format:        FormatCustom,
+			termSeparator: "\t",
+			rows:          []*Row{{1, []string{"dog", "pies"}}, {2, []string{"cat", "kot"}}},
+		},
+		{
+			name:          "explicit CSV with custom separator",
+			text:          "dog|pies\ncat|kot",
+			options:       Options{Format: FormatCSV, TermSeparator: "|"},
+			format:        FormatCSV,
+			termSeparator: "|",
+			rows:          []*Row{{1, []string{"dog", "pies"}}, {2, []string{"cat", "kot"}}},
+		},
+		{
+			name:          "explicit TSV ignores commas",
+			text:          "dog, hound\tpies",
+			options:       Options{Format: FormatTSV},
+			format:        FormatTSV,
+			termSeparator: "\t",
+			rows:          []*Row{{1, []string{"dog, hound", "pies"}}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			table, err := Parse(tt.text, tt.options)
+			if err != nil {
+				t.Fatalf("Parse returned an error: %v", err)
+			}
+			if table.Format != tt.format {
+				t.Errorf("Format = %s, want %s", table.Format, tt.format)
+			}
+			if table.TermSeparator != tt.termSeparator {
+				t.Errorf("TermSeparator = %q, want %q", table.TermSeparator, tt.termSeparator)
+			}
+			if !reflect.DeepEqual(table.Rows, tt.rows) {
+				t.Errorf("Rows = %v, want %v", rowsString(table.Rows), rowsString(tt.rows))
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		options Options
+		err     error
+	}{
+		{"unknown format", Options{Format: "XML"}, ErrUnknownFormat},
+		{"custom without separator", Options{Format: FormatCustom}, ErrNoTermSeparator},
+		{"CSV with long separator", Options{Format: FormatCSV, TermSeparator: "::"}, ErrInvalidCSVSeparator},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Parse("dog\tpies", tt.options); !errors.Is(err, tt.err) {
+				t.Errorf("Parse error = %v, want %v", err, tt.err)
+			}
+		})
+	}
+}
+
+func rowsString(rows []*Row) []Row {
+	values := make([]Row, 0, len(rows))
+	for _, row := range rows {
+		values = append(values, *row)
+	}
+	return values
+}
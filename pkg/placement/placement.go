@@ -0,0 +1,185 @@
+// Package placement implements an adaptive vocabulary test estimating learner's CEFR level.
+// Items are multiple choice questions from a curated word bank. The ability is estimated with
+// a three parameter logistic IRT model, where item difficulty comes from its CEFR level.
+package placement
+
+import (
+	_ "embed"
+	"encoding/json"
+	"math"
+	"math/rand"
+)
+
+const (
+	// Language is the language of words in the bank.
+	Language = "en-US"
+	// MeaningLanguage is the language of meanings in the bank.
+	MeaningLanguage = "pl-PL"
+
+	// ChoiceCount is the number of choices in each question.
+	ChoiceCount = 4
+	// MinQuestions is the number of questions asked before the test can stop.
+	MinQuestions = 6
+	// MaxQuestions is the number of questions after which the test stops regardless of confidence.
+	MaxQuestions = 20
+	// TargetStdErr is the standard error of the ability estimate at which the test is confident enough to stop.
+	TargetStdErr = 0.7
+
+	// guessing is the chance of picking the right choice at random.
+	guessing = 1.0 / ChoiceCount
+	// priorStdDev is the spread of abilities expected among new learners.
+	priorStdDev = 1.5
+)
+
+// Levels lists CEFR levels from the lowest to the highest.
+var Levels = []string{"A1", "A2", "B1", "B2", "C1", "C2"}
+
+// Item represents a single word from the bank.
+type Item struct {
+	Id      int    `json:"id"`
+	Word    string `json:"word"`
+	Meaning string `json:"meaning"`
+	Level   string `json:"level"`
+}
+
+// Response represents an answer to an item of the given level.
+type Response struct {
+	Level   string
+	Correct bool
+}
+
+//go:embed words.json
+var wordsJson []byte
+
+var bank []Item
+
+func init() {
+	if err := json.Unmarshal(wordsJson, &bank); err != nil {
+		panic("placement: invalid word bank: " + err.Error())
+	}
+}
+
+// Bank returns all items from the word bank.
+func Bank() []Item {
+	return bank
+}
+
+// Find returns the item with the given id.
+func Find(itemID int) (*Item, bool) {
+	for i := range bank {
+		if bank[i].Id == itemID {
+			return &bank[i], true
+		}
+	}
+	return nil, false
+}
+
+// Difficulty returns difficulty of items of the given level on the logit scale.
+// Levels are spread evenly around 0, so that B1 and B2 surround an average learner.
+func Difficulty(level string) float64 {
+	for i, l := range Levels {
+		if l == level {
+			return float64(i) - float64(len(Levels)-1)/2
+		}
+	}
+	return 0
+}
+
+// LevelFor returns the level closest to the given ability.
+func LevelFor(ability float64) string {
+	i := int(math.Round(ability + float64(len(Levels)-1)/2))
+	i = max(0, min(len(Levels)-1, i))
+	return Levels[i]
+}
+
+// Estimate returns the expected a posteriori ability and its standard error given the responses.
+func Estimate(responses []Response) (float64, float64) {
+	const (
+		from = -4.0
+		to   = 4.0
+		step = 0.05
+	)
+
+	var total, mean, squares float64
+	for theta := from; theta <= to; theta += step {
+		likelihood := math.Exp(-theta * theta / (2 * priorStdDev * priorStdDev))
+		for _, response := range responses {
+			p := probability(theta, Difficulty(response.Level))
+			if response.Correct {
+				likelihood *= p
+			} else {
+				likelihood *= 1 - p
+			}
+		}
+
+		total += likelihood
+		mean += theta * likelihood
+		squares += theta * theta * likelihood
+	}
+
+	mean /= total
+	variance := squares/total - mean*mean
+
+	return mean, math.Sqrt(max(variance, 0))
+}
+
+// Done tells if the test can stop after the given number of answers.
+func Done(answered int, stdErr float64) bool {
+	return answered >= MaxQuestions || (answered >= MinQuestions && stdErr <= TargetStdErr)
+}
+
+// Next picks a random item not asked yet among the ones with difficulty closest to the ability.
+func Next(asked map[int]bool, ability float64) (*Item, bool) {
+	bestDistance := math.Inf(1)
+	candidates := make([]*Item, 0)
+
+	for i := range bank {
+		if asked[bank[i].Id] {
+			continue
+		}
+
+		distance := math.Abs(Difficulty(bank[i].Level) - ability)
+		switch {
+		case distance < bestDistance:
+			bestDistance = distance
+			candidates = append(candidates[:0], &bank[i])
+		case distance == bestDistance:
+			candidates = append(candidates, &bank[i])
+		}
+	}
+
+	if len(candidates) == 0 {
+		return nil, false
+	}
+	return candidates[rand.Intn(len(candidates))], true
+}
+
+// Choices returns shuffled meanings for the item together with the index of the correct one.
+// Distractors are meanings of other random items from the bank.
+func Choices(item *Item) ([]string, int) {
+	choices := []string{item.Meaning}
+	for _, i := range rand.Perm(len(bank)) {
+		if len(choices) == ChoiceCount {
+			break
+		}
+		if bank[i].Id != item.Id {
+			choices = append(choices, bank[i].Meaning)
+		}
+	}
+
+	rand.Shuffle(len(choices), func(i, j int) {
+		choices[i], choices[j] = choices[j], choices[i]
+	})
+
+	for i, choice := range choices {
+		if choice == item.Meaning {
+			return choices, i
+		}
+	}
+	return choices, 0
+}
+
+// probability returns the chance of answering an item of the given difficulty correctly, guessing included.
+func probability(ability float64, difficulty float64) float64 {
+	return guessing + (1-guessing)/(1+math.Exp(difficulty-ability))
+}
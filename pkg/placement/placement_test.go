@@ -0,0 +1,166 @@
+package placement
+
+import (
+	"math"
+	"testing"
+)
+
+func TestBank(t *testing.T) {
+	if len(Bank()) == 0 {
+		t.Fatal("word bank is empty")
+	}
+
+	ids := make(map[int]bool)
+	perLevel := make(map[string]int)
+	for _, item := range Bank() {
+		if ids[item.Id] {
+			t.Errorf("duplicate item id %d", item.Id)
+		}
+		ids[item.Id] = true
+		if item.Word == "" || item.Meaning == "" {
+			t.Errorf("item %d has no word or meaning", item.Id)
+		}
+		perLevel[item.Level]++
+	}
+
+	for _, level := range Levels {
+		if perLevel[level] < ChoiceCount {
+			t.Errorf("level %s has %d items, want at least %d", level, perLevel[level], ChoiceCount)
+		}
+		delete(perLevel, level)
+	}
+	for level := range perLevel {
+		t.Errorf("unknown level %q in the word bank", level)
+	}
+}
+
+func TestFind(t *testing.T) {
+	item := Bank()[0]
+	found, ok := Find(item.Id)
+	if !ok || found.Id != item.Id {
+		t.Errorf("Find(%d) = %v, %v", item.Id, found, ok)
+	}
+	if _, ok := Find(-1); ok {
+		t.Errorf("Find(-1) found an item")
+	}
+}
+
+func TestDifficulty(t *testing.T) {
+	tests := []struct {
+		level string
+		want  float64
+	}{
+		{"A1", -2.5},
+		{"B1", -0.5},
+		{"B2", 0.5},
+		{"C2", 2.5},
+		{"X9", 0},
+	}
+
+	for _, tt := range tests {
+		if got := Difficulty(tt.level); got != tt.want {
+			t.Errorf("Difficulty(%q) = %v, want %v", tt.level, got, tt.want)
+		}
+	}
+}
+
+func TestLevelFor(t *testing.T) {
+	tests := []struct {
+		ability float64
+		want    string
+	}{
+		{-10, "A1"},
+		{-2.5, "A1"},
+		{-0.4, "B1"},
+		{0.6, "B2"},
+		{2.5, "C2"},
+		{10, "C2"},
+	}
+
+	for _, tt := range tests {
+		if got := LevelFor(tt.ability); got != tt.want {
+			t.Errorf("LevelFor(%v) = %q, want %q", tt.ability, got, tt.want)
+		}
+	}
+}
+
+func TestEstimate(t *testing.T) {
+	ability, stdErr := Estimate(nil)
+	if math.Abs(ability) > 0.01 {
+		t.Errorf("ability without responses = %v, want 0", ability)
+	}
+	if math.Abs(stdErr-priorStdDev) > 0.05 {
+		t.Errorf("standard error without responses = %v, want about %v", stdErr, priorStdDev)
+	}
+
+	strong := []Response{{"B2", true}, {"C1", true}, {"C1", true}, {"C2", true}, {"C2", false}, {"C2", true}}
+	weak := []Response{{"A1", true}, {"A2", false}, {"A2", false}, {"B1", false}, {"A1", false}, {"A2", false}}
+
+	strongAbility, strongStdErr := Estimate(strong)
+	weakAbility, _ := Estimate(weak)
+	if strongAbility <= 1 {
+		t.Errorf("ability of a strong learner = %v, want above 1", strongAbility)
+	}
+	if weakAbility >= -1 {
+		t.Errorf("ability of a weak learner = %v, want below -1", weakAbility)
+	}
+	if strongStdErr >= stdErr {
+		t.Errorf("standard error did not shrink with responses: %v", strongStdErr)
+	}
+}
+
+func TestDone(t *testing.T) {
+	tests := []struct {
+		answered int
+		stdErr   float64
+		want     bool
+	}{
+		{0, 0, false},
+		{MinQuestions - 1, 0.1, false},
+		{MinQuestions, TargetStdErr, true},
+		{MinQuestions, TargetStdErr + 0.1, false},
+		{MaxQuestions, 10, true},
+	}
+
+	for _, tt := range tests {
+		if got := Done(tt.answered, tt.stdErr); got != tt.want {
+			t.Errorf("Done(%d, %v) = %v, want %v", tt.answered, tt.stdErr, got, tt.want)
+		}
+	}
+}
+
+func TestNext(t *testing.T) {
+	item, ok := Next(map[int]bool{}, Difficulty("C1"))
+	if !ok || item.Level != "C1" {
+		t.Fatalf("Next at C1 ability = %v, %v, want a C1 item", item, ok)
+	}
+
+	asked := make(map[int]bool)
+	for _, item := range Bank() {
+		if item.Level == "C1" {
+			asked[item.Id] = true
+		}
+	}
+	item, ok = Next(asked, Difficulty("C1"))
+	if !ok || item.Level == "C1" || asked[item.Id] {
+		t.Errorf("Next with all C1 items asked = %v, %v", item, ok)
+	}
+
+	for _, item := range Bank() {
+		asked[item.Id] = true
+	}
+	if _, ok := Next(asked, 0); ok {
+		t.Errorf("Next with all items asked found an item")
+	}
+}
+
+func TestChoices(t *testing.T) {
+	item := &Bank()[0]
+	choices, correct := Choices(item)
+	if len(choices) != ChoiceCount {
+		t.Fatalf("got %d choices, want %d", len(choices), ChoiceCount)
+	}
+	if choices[correct] != item.Meaning {
+		t.Errorf("choice %d = %q, want %q", correct, choices[correct], item.Meaning)
+	}
+}
@@ -0,0 +1,70 @@
+// Package srs implements the SM-2 spaced repetition algorithm.
+package srs
+
+import (
+	"math"
+	"time"
+)
+
+const (
+	// DefaultEase is the ease factor assigned to cards that have never been reviewed.
+	DefaultEase = 2.5
+	// MinEase is the lowest ease factor a card can reach.
+	MinEase = 1.3
+	// PassingGrade is the lowest grade that counts as a successful recall.
+	PassingGrade = 3
+)
+
+// Grade represents learner's self-assessment of recall quality in 0-5 range,
+// where 0 means complete blackout and 5 means perfect response.
+type Grade int
+
+// State represents scheduling state of a single card.
+type State struct {
+	Ease         float64
+	IntervalDays int
+	Repetitions  int
+	Lapses       int
+	DueAt        time.Time
+}
+
+// NewState returns the state of a card that has never been reviewed.
+func NewState() State {
+	return State{
+		Ease: DefaultEase,
+	}
+}
+
+// Valid checks if the grade is in the supported range.
+func (g Grade) Valid() bool {
+	return g >= 0 && g <= 5
+}
+
+// Schedule computes the next state of a card reviewed at the given time with the given grade.
+func Schedule(s State, grade Grade, reviewedAt time.Time) State {
+	next := s
+
+	if grade < PassingGrade {
+		if s.Repetitions > 0 {
+			next.Lapses++
+		}
+		next.Repetitions = 0
+		next.IntervalDays = 1
+	} else {
+		switch s.Repetitions {
+		case 0:
+			next.IntervalDays = 1
+		case 1:
+			next.IntervalDays = 6
+		default:
+			next.IntervalDays = int(math.Round(float64(s.IntervalDays) * s.Ease))
+		}
+		next.Repetitions++
+	}
+
+	q := float64(5 - grade)
+	next.Ease = math.Max(MinEase, s.Ease+(0.1-q*(0.08+q*0.02)))
+	next.DueAt = reviewedAt.AddDate(0, 0, next.IntervalDays)
+
+	return next
+}
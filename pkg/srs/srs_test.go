@@ -0,0 +1,67 @@
+package srs
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestSchedule(t *testing.T) {
+	reviewedAt := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name         string
+		state        State
+		grade        Grade
+		ease         float64
+		intervalDays int
+		repetitions  int
+		lapses       int
+	}{
+		{"first review", NewState(), 4, 2.5, 1, 1, 0},
+		{"second review", State{Ease: 2.5, IntervalDays: 1, Repetitions: 1}, 4, 2.5, 6, 2, 0},
+		{"third review", State{Ease: 2.5, IntervalDays: 6, Repetitions: 2}, 4, 2.5, 15, 3, 0},
+		{"perfect response raises ease", State{Ease: 2.5, IntervalDays: 6, Repetitions: 2}, 5, 2.6, 15, 3, 0},
+		{"hard response lowers ease", State{Ease: 2.5, IntervalDays: 6, Repetitions: 2}, 3, 2.36, 15, 3, 0},
+		{"lapse resets repetitions", State{Ease: 2.5, IntervalDays: 15, Repetitions: 3}, 2, 2.18, 1, 0, 1},
+		{"failing a new card is not a lapse", NewState(), 0, 1.7, 1, 0, 0},
+		{"ease does not drop below minimum", State{Ease: 1.4, IntervalDays: 6, Repetitions: 2, Lapses: 2}, 0, MinEase, 1, 0, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next := Schedule(tt.state, tt.grade, reviewedAt)
+			if math.Abs(next.Ease-tt.ease) > 1e-9 {
+				t.Errorf("Ease = %v, want %v", next.Ease, tt.ease)
+			}
+			if next.IntervalDays != tt.intervalDays {
+				t.Errorf("IntervalDays = %d, want %d", next.IntervalDays, tt.intervalDays)
+			}
+			if next.Repetitions != tt.repetitions {
+				t.Errorf("Repetitions = %d, want %d", next.Repetitions, tt.repetitions)
+			}
+			if next.Lapses != tt.lapses {
+				t.Errorf("Lapses = %d, want %d", next.Lapses, tt.lapses)
+			}
+			if want := reviewedAt.AddDate(0, 0, tt.intervalDays); !next.DueAt.Equal(want) {
+				t.Errorf("DueAt = %v, want %v", next.DueAt, want)
+			}
+		})
+	}
+}
+
+func TestScheduleDoesNotModifyState(t *testing.T) {
+	state := State{Ease: 2.5, IntervalDays: 6, Repetitions: 2}
+	Schedule(state, 5, time.Now())
+	if state != (State{Ease: 2.5, IntervalDays: 6, Repetitions: 2}) {
+		t.Errorf("Schedule modified the given state: %+v", state)
+	}
+}
+
+func TestGradeValid(t *testing.T) {
+	for grade := Grade(-1); grade <= 6; grade++ {
+		if want := grade >= 0 && grade <= 5; grade.Valid() != want {
+			t.Errorf("Grade(%d).Valid() = %v, want %v", grade, grade.Valid(), want)
+		}
+	}
+}
@@ -8,8 +8,21 @@ CREATE TABLE study_set
 	`definition_language` ENUM ('pl-PL', 'en-US') NOT NULL,
 	`icon`                VARCHAR(32)             NOT NULL,
 	`color`               VARCHAR(32)             NOT NULL,
+	`cefr_level`          ENUM ('A1', 'A2', 'B1', 'B2', 'C1', 'C2') DEFAULT NULL,
+	`visibility`          ENUM ('PRIVATE', 'UNLISTED', 'PUBLIC')   NOT NULL DEFAULT 'PUBLIC',
+	`category`            ENUM ('TRAVEL', 'BUSINESS', 'EXAMS', 'EVERYDAY', 'FOOD', 'HEALTH', 'SCIENCE', 'TECHNOLOGY', 'CULTURE', 'SCHOOL') DEFAULT NULL,
+	`forked_from_id`      INT                     DEFAULT NULL,
+	`forked_from_author_id` VARCHAR(32)           DEFAULT NULL,
+	`stars`               INT                     NOT NULL DEFAULT 0,
+	`learners`            INT                     NOT NULL DEFAULT 0,
 
 	INDEX (`author_id`(20)),
+	INDEX (`forked_from_id`),
+	INDEX (`category`),
+	INDEX (`name`, `id`),
+	INDEX (`stars`, `id`),
+	INDEX (`learners`, `id`),
+	FULLTEXT (`name`, `description`),
 	PRIMARY KEY (`id`)
 );
 
@@ -20,7 +33,11 @@ CREATE TABLE definition
 	`phrase`       VARCHAR(256)       NOT NULL,
 	`meaning`      VARCHAR(256)       NOT NULL,
 	`sentences`    JSON               NOT NULL,
+	`position`     INT                NOT NULL DEFAULT 0,
+	`updated_at`   DATETIME(3)        NOT NULL DEFAULT CURRENT_TIMESTAMP(3) ON UPDATE CURRENT_TIMESTAMP(3),
 
+	INDEX (`study_set_id`, `position`),
+	FULLTEXT (`phrase`, `meaning`),
 	PRIMARY KEY (`id`)
 );
 
@@ -30,17 +47,42 @@ CREATE TABLE star
 	`study_set_id` INT         NOT NULL,
 
 	INDEX (`user_id`(20)),
+	INDEX (`study_set_id`),
 	UNIQUE (`user_id`, `study_set_id`)
 );
 
 CREATE TABLE study_session
 (
-	`user_id`         VARCHAR(32) NOT NULL,
-	`study_set_id`    INT         NOT NULL,
-	`last_session_at` DATETIME DEFAULT (NOW()),
+	`id`              INT AUTO_INCREMENT                                             NOT NULL,
+	`user_id`         VARCHAR(32)                                                    NOT NULL,
+	`study_set_id`    INT         DEFAULT NULL,
+	`folder_id`       INT         DEFAULT NULL,
+	`mode`            ENUM ('FLASHCARDS', 'WRITE', 'QUIZ', 'CLOZE', 'REVIEW')        NOT NULL,
+	`direction`       ENUM ('PHRASE_TO_MEANING', 'MEANING_TO_PHRASE')                NOT NULL,
+	`started_at`      DATETIME(3) DEFAULT (NOW(3)),
+	`last_session_at` DATETIME(3) DEFAULT (NOW(3)),
+	`finished_at`     DATETIME(3) DEFAULT NULL,
+	`duration_ms`     BIGINT      DEFAULT NULL,
+	`cards_seen`      INT         DEFAULT NULL,
+	`correct_count`   INT         DEFAULT NULL,
+	`incorrect_count` INT         DEFAULT NULL,
 
-	INDEX (`user_id`(20)),
-	UNIQUE (`user_id`, `study_set_id`)
+	INDEX (`user_id`(20), `study_set_id`),
+	INDEX (`study_set_id`),
+	INDEX (`folder_id`),
+	PRIMARY KEY (`id`)
+);
+
+CREATE TABLE study_session_outcome
+(
+	`study_session_id` INT     NOT NULL,
+	`definition_id`    INT     NOT NULL,
+	`position`         INT     NOT NULL,
+	`correct`          BOOLEAN NOT NULL,
+	`response_time_ms` INT     NOT NULL,
+
+	INDEX (`study_session_id`),
+	INDEX (`definition_id`)
 );
 
 CREATE TABLE user
@@ -60,4 +102,272 @@ CREATE TABLE task
 	`result` JSON                               DEFAULT NULL,
 
 	PRIMARY KEY (`id`)
-);
\ No newline at end of file
+);
+
+CREATE TABLE review_state
+(
+	`user_id`          VARCHAR(32) NOT NULL,
+	`definition_id`    INT         NOT NULL,
+	`study_set_id`     INT         NOT NULL,
+	`ease`             DOUBLE      NOT NULL,
+	`interval_days`    INT         NOT NULL,
+	`repetitions`      INT         NOT NULL,
+	`lapses`           INT         NOT NULL,
+	`due_at`           DATETIME    NOT NULL,
+	`last_reviewed_at` DATETIME    NOT NULL,
+	`updated_at`       DATETIME(3) NOT NULL DEFAULT CURRENT_TIMESTAMP(3) ON UPDATE CURRENT_TIMESTAMP(3),
+
+	INDEX (`user_id`(20), `study_set_id`),
+	INDEX (`user_id`(20), `updated_at`),
+	INDEX (`definition_id`),
+	UNIQUE (`user_id`, `definition_id`)
+);
+
+CREATE TABLE review_log
+(
+	`id`               BIGINT AUTO_INCREMENT NOT NULL,
+	`user_id`          VARCHAR(32)           NOT NULL,
+	`definition_id`    INT                   NOT NULL,
+	`study_set_id`     INT                   NOT NULL,
+	`grade`            TINYINT               NOT NULL,
+	`response_time_ms` INT                   NOT NULL,
+	`answered_at`      DATETIME(3)           NOT NULL,
+	`client_event_id`  VARCHAR(64) DEFAULT NULL,
+	`created_at`       DATETIME(3) DEFAULT (NOW(3)),
+
+	INDEX (`user_id`(20), `answered_at`),
+	INDEX (`definition_id`),
+	UNIQUE (`user_id`, `client_event_id`),
+	PRIMARY KEY (`id`)
+);
+
+CREATE TABLE quiz
+(
+	`id`           INT AUTO_INCREMENT                                NOT NULL,
+	`user_id`      VARCHAR(32)                                       NOT NULL,
+	`study_set_id` INT                                               NOT NULL,
+	`direction`    ENUM ('PHRASE_TO_MEANING', 'MEANING_TO_PHRASE')   NOT NULL,
+	`score`        INT      DEFAULT NULL,
+	`created_at`   DATETIME DEFAULT (NOW()),
+	`submitted_at` DATETIME DEFAULT NULL,
+
+	INDEX (`user_id`(20)),
+	PRIMARY KEY (`id`)
+);
+
+CREATE TABLE quiz_question
+(
+	`id`            INT AUTO_INCREMENT NOT NULL,
+	`quiz_id`       INT                NOT NULL,
+	`definition_id` INT                NOT NULL,
+	`position`      INT                NOT NULL,
+	`prompt`        VARCHAR(256)       NOT NULL,
+	`choices`       JSON               NOT NULL,
+	`correct_index` INT                NOT NULL,
+	`answer_index`  INT DEFAULT NULL,
+
+	INDEX (`quiz_id`),
+	INDEX (`definition_id`),
+	PRIMARY KEY (`id`)
+);
+
+CREATE TABLE user_preferences
+(
+	`user_id`           VARCHAR(32)                 NOT NULL,
+	`timezone`          VARCHAR(64)                 NOT NULL DEFAULT 'UTC',
+	`daily_goal_type`   ENUM ('REVIEWS', 'MINUTES') NOT NULL DEFAULT 'REVIEWS',
+	`daily_goal_target` INT                         NOT NULL DEFAULT 20,
+	`cefr_level`        ENUM ('A1', 'A2', 'B1', 'B2', 'C1', 'C2') DEFAULT NULL,
+
+	PRIMARY KEY (`user_id`)
+);
+
+CREATE TABLE streak
+(
+	`user_id`           VARCHAR(32) NOT NULL,
+	`current_streak`    INT         NOT NULL,
+	`longest_streak`    INT         NOT NULL,
+	`freezes_available` INT         NOT NULL,
+	`last_active_date`  DATE DEFAULT NULL,
+
+	PRIMARY KEY (`user_id`)
+);
+
+CREATE TABLE daily_activity
+(
+	`user_id`          VARCHAR(32) NOT NULL,
+	`day`              DATE        NOT NULL,
+	`reviews`          INT         NOT NULL,
+	`response_time_ms` BIGINT      NOT NULL,
+
+	PRIMARY KEY (`user_id`, `day`)
+);
+
+CREATE TABLE exam
+(
+	`id`                 INT AUTO_INCREMENT NOT NULL,
+	`user_id`            VARCHAR(32)        NOT NULL,
+	`study_set_id`       INT                NOT NULL,
+	`time_limit_seconds` INT                NOT NULL,
+	`score`              INT         DEFAULT NULL,
+	`total`              INT                NOT NULL,
+	`timed_out`          BOOLEAN            NOT NULL DEFAULT FALSE,
+	`started_at`         DATETIME(3)        NOT NULL,
+	`deadline_at`        DATETIME(3)        NOT NULL,
+	`submitted_at`       DATETIME(3) DEFAULT NULL,
+
+	INDEX (`user_id`(20), `study_set_id`),
+	PRIMARY KEY (`id`)
+);
+
+CREATE TABLE exam_question
+(
+	`id`            INT AUTO_INCREMENT                               NOT NULL,
+	`exam_id`       INT                                              NOT NULL,
+	`definition_id` INT                                              NOT NULL,
+	`position`      INT                                              NOT NULL,
+	`type`          ENUM ('MULTIPLE_CHOICE', 'TYPED', 'CLOZE')       NOT NULL,
+	`prompt`        VARCHAR(1024)                                    NOT NULL,
+	`choices`       JSON         DEFAULT NULL,
+	`answer`        VARCHAR(256)                                     NOT NULL,
+	`response`      VARCHAR(256) DEFAULT NULL,
+	`correct`       BOOLEAN      DEFAULT NULL,
+
+	INDEX (`exam_id`),
+	INDEX (`definition_id`),
+	PRIMARY KEY (`id`)
+);
+
+CREATE TABLE definition_tombstone
+(
+	`definition_id` INT         NOT NULL,
+	`study_set_id`  INT         NOT NULL,
+	`deleted_at`    DATETIME(3) NOT NULL DEFAULT CURRENT_TIMESTAMP(3),
+
+	INDEX (`study_set_id`, `deleted_at`)
+);
+
+CREATE TABLE study_set_tombstone
+(
+	`study_set_id` INT         NOT NULL,
+	`user_id`      VARCHAR(32) NOT NULL,
+
+	INDEX (`user_id`(20), `study_set_id`)
+);
+
+CREATE TABLE skill
+(
+	`user_id`    VARCHAR(32) NOT NULL,
+	`language`   VARCHAR(16) NOT NULL,
+	`ability`    DOUBLE      NOT NULL,
+	`answers`    INT         NOT NULL,
+	`updated_at` DATETIME(3) NOT NULL DEFAULT CURRENT_TIMESTAMP(3) ON UPDATE CURRENT_TIMESTAMP(3),
+
+	PRIMARY KEY (`user_id`, `language`)
+);
+
+CREATE TABLE definition_difficulty
+(
+	`definition_id` INT    NOT NULL,
+	`difficulty`    DOUBLE NOT NULL,
+	`answers`       INT    NOT NULL,
+
+	PRIMARY KEY (`definition_id`)
+);
+
+CREATE TABLE placement_test
+(
+	`id`          INT AUTO_INCREMENT NOT NULL,
+	`user_id`     VARCHAR(32)        NOT NULL,
+	`ability`     DOUBLE                                   DEFAULT NULL,
+	`std_err`     DOUBLE                                   DEFAULT NULL,
+	`level`       ENUM ('A1', 'A2', 'B1', 'B2', 'C1', 'C2') DEFAULT NULL,
+	`started_at`  DATETIME                                 DEFAULT (NOW()),
+	`finished_at` DATETIME                                 DEFAULT NULL,
+
+	INDEX (`user_id`(20)),
+	PRIMARY KEY (`id`)
+);
+
+CREATE TABLE placement_question
+(
+	`id`            INT AUTO_INCREMENT                         NOT NULL,
+	`test_id`       INT                                        NOT NULL,
+	`item_id`       INT                                        NOT NULL,
+	`position`      INT                                        NOT NULL,
+	`word`          VARCHAR(256)                               NOT NULL,
+	`level`         ENUM ('A1', 'A2', 'B1', 'B2', 'C1', 'C2') NOT NULL,
+	`choices`       JSON                                       NOT NULL,
+	`correct_index` INT                                        NOT NULL,
+	`answer_index`  INT DEFAULT NULL,
+
+	INDEX (`test_id`),
+	PRIMARY KEY (`id`)
+);
+
+CREATE TABLE collaborator
+(
+	`study_set_id` INT                        NOT NULL,
+	`user_id`      VARCHAR(32)                NOT NULL,
+	`role`         ENUM ('EDITOR', 'VIEWER')  NOT NULL,
+	`added_at`     DATETIME DEFAULT (NOW()),
+
+	INDEX (`user_id`(20)),
+	UNIQUE (`study_set_id`, `user_id`)
+);
+
+CREATE TABLE revision
+(
+	`id`              INT AUTO_INCREMENT                NOT NULL,
+	`study_set_id`    INT                               NOT NULL,
+	`author_id`       VARCHAR(32)                       NOT NULL,
+	`target`          ENUM ('STUDY_SET', 'DEFINITION')  NOT NULL,
+	`definition_id`   INT      DEFAULT NULL,
+	`before_snapshot` JSON     DEFAULT NULL,
+	`after_snapshot`  JSON     DEFAULT NULL,
+	`created_at`      DATETIME DEFAULT (NOW()),
+
+	INDEX (`study_set_id`, `id`),
+	PRIMARY KEY (`id`)
+);
+
+CREATE TABLE tag
+(
+	`id`   INT AUTO_INCREMENT NOT NULL,
+	`name` VARCHAR(32)        NOT NULL,
+
+	UNIQUE (`name`),
+	PRIMARY KEY (`id`)
+);
+
+CREATE TABLE study_set_tag
+(
+	`study_set_id` INT NOT NULL,
+	`tag_id`       INT NOT NULL,
+
+	INDEX (`tag_id`),
+	UNIQUE (`study_set_id`, `tag_id`)
+);
+
+CREATE TABLE folder
+(
+	`id`         INT AUTO_INCREMENT NOT NULL,
+	`user_id`    VARCHAR(32)        NOT NULL,
+	`parent_id`  INT      DEFAULT NULL,
+	`name`       VARCHAR(128)       NOT NULL,
+	`position`   INT                NOT NULL,
+	`created_at` DATETIME DEFAULT (NOW()),
+
+	INDEX (`user_id`(20)),
+	PRIMARY KEY (`id`)
+);
+
+CREATE TABLE folder_study_set
+(
+	`folder_id`    INT NOT NULL,
+	`study_set_id` INT NOT NULL,
+	`position`     INT NOT NULL,
+
+	INDEX (`study_set_id`),
+	UNIQUE (`folder_id`, `study_set_id`)
+);